@@ -1,15 +1,49 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
 	"github.com/ruancaetano/go-gin-fruits/internal/app"
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
 
 	docs "github.com/ruancaetano/go-gin-fruits/docs"
 )
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description "Bearer <token>", signed with HS256 or RS256
+
 func main() {
 	docs.SwaggerInfo.Title = "Fruit Crud"
 	docs.SwaggerInfo.Version = "1.0"
 
-	s := app.NewServer()
-	s.Start()
+	cfg, err := config.Load(os.Args[1:], os.Getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	s := app.NewServer(cfg)
+	err = s.Start(ctx)
+	stop()
+
+	if err != nil {
+		log.Fatal(err)
+	}
 }
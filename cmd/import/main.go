@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/app"
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/spreadsheet"
+)
+
+// Import the fruits of a csv or xlsx file into the repository the api is configured with, e.g.
+//
+//	FRUITS_REPOSITORY=sqlite FRUITS_SQLITE_DSN=fruits.db go run ./cmd/import -owner bob -mode upsert supplier.xlsx
+//
+// Exits with status 1 when the import fails, and 2 when some rows are refused.
+func main() {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: import [flags] file.csv|file.xlsx")
+		fs.PrintDefaults()
+	}
+
+	path := fs.String("config", os.Getenv("FRUITS_CONFIG"), "yaml config file, FRUITS_* env vars apply as they do to the api")
+	owner := fs.String("owner", "", "owner of the imported fruits (required)")
+	tenantID := fs.String("tenant", tenant.Default, "tenant the fruits are imported into")
+	mode := fs.String("mode", string(usecase.ImportCreate), "create a fruit per row, or upsert the owner fruits with the same name")
+	dryRun := fs.Bool("dry-run", false, "validate the rows and tell what would be done, without changing any fruit")
+	columns := fs.String("columns", "", "headers of the fruit fields, e.g. Fruta=name,Estoque=quantity,Valor=price")
+
+	err := fs.Parse(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+
+	if err != nil {
+		os.Exit(1)
+	}
+
+	if fs.NArg() != 1 || *owner == "" {
+		fs.Usage()
+		os.Exit(1)
+	}
+
+	var args []string
+	if *path != "" {
+		args = []string{"-config", *path}
+	}
+
+	cfg, err := config.Load(args, os.Getenv)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	options := &app.ImportOptions{
+		File:   fs.Arg(0),
+		Owner:  *owner,
+		Tenant: *tenantID,
+		Mode:   usecase.ImportMode(*mode),
+		DryRun: *dryRun,
+	}
+
+	if *columns != "" {
+		if options.Columns, err = spreadsheet.ParseColumns(*columns); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	output, err := app.Import(ctx, cfg, options, os.Stderr)
+	stop()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	for _, row := range output.Rows {
+		switch {
+		case row.Err != nil:
+			fmt.Printf("line %d: %s: %s\n", row.Line, row.Action, row.Err)
+		case row.ID != "":
+			fmt.Printf("line %d: %s %s\n", row.Line, row.Action, row.ID)
+		default:
+			fmt.Printf("line %d: %s\n", row.Line, row.Action)
+		}
+	}
+
+	summary := fmt.Sprintf("%d created, %d updated, %d failed", output.Created, output.Updated, output.Failed)
+	if output.DryRun {
+		summary += " (dry run, nothing was changed)"
+	}
+	fmt.Println(summary)
+
+	if output.Failed > 0 {
+		os.Exit(2)
+	}
+}
@@ -22,6 +22,7 @@ Feature: create fruit
     Then The response code should be 200
 
   Scenario: update fruit
+    Given I set header "x-owner" with value "ruan"
     When I send "PUT" request to "/fruits/test-uuid" with body:
       """json
       {
@@ -41,8 +42,28 @@ Feature: create fruit
     Then The response code should be 200
     Then The json path "Results" should have count "1"
 
+  Scenario: transition fruit
+    Given I set header "x-owner" with value "ruan"
+    When I send "POST" request to "/fruits/test-uuid/transitions" with body:
+      """json
+      {
+          "status": "reserved"
+      }
+      """
+    Then The response code should be 200
+    Then The json path "status" should have value "reserved"
+
 
   Scenario: search fruit
+    Given I set header "x-owner" with value "ruan"
     When I send "DELETE" request to "/fruits/test-uuid"
     Then The response code should be 200
     Then The json path "status" should have value "podrido"
+
+
+  Scenario: restore fruit
+    Given I set header "x-user" with value "ruan"
+    When I send "POST" request to "/fruits/test-uuid/restore"
+    Then The response code should be 200
+    Then The json path "status" should have value "reserved"
+    Then The json path "restored_by" should have value "ruan"
@@ -0,0 +1,93 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/spreadsheet"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+)
+
+// ImportOptions what the import command imports and how
+type ImportOptions struct {
+	File   string // csv or xlsx, told apart by extension
+	Owner  string // of the imported fruits
+	Tenant string
+	Mode   usecase.ImportMode
+	DryRun bool
+	// Columns map the file headers to fruit fields, nil recognizes the default headers
+	Columns map[string]string
+}
+
+// Import import the fruits of a spreadsheet file into the configured repository, on behalf of the owner, logging to logs
+func Import(ctx context.Context, cfg *config.Config, options *ImportOptions, logs io.Writer) (output *usecase.ImportFruitsUseCaseOutputDTO, err error) {
+	s := &Server{
+		config: cfg,
+		log:    logger.New(cfg.Server.Mode, cfg.Log.Level, logs),
+	}
+	defer func() {
+		if closeErr := s.close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	if err := tenant.Validate(options.Tenant); err != nil {
+		return nil, err
+	}
+
+	// the fruits would be gone as soon as the command exits
+	if cfg.Repository.Driver == "memory" && !options.DryRun {
+		return nil, errors.New("the memory repository doesn't outlive the import, import into sqlite or use a dry run")
+	}
+
+	format, err := spreadsheet.FormatOf(options.File)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(options.File)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open import file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := spreadsheet.ReadFruitRows(file, format, options.Columns, usecase.MaxImportRows)
+	if err != nil {
+		return nil, err
+	}
+
+	fruitRepository, err := s.makeFruitRepository(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("fail to setup fruit repository: %w", err)
+	}
+
+	authorizer := auth.NewOwnerAuthorizer(false)
+	u := usecase.NewImportFruitsUseCase(
+		fruitRepository,
+		usecase.NewCreateFruitUseCase(fruitRepository),
+		usecase.NewUpdateFruitUseCase(fruitRepository, authorizer),
+	)
+
+	ctx = tenant.WithTenant(ctx, options.Tenant)
+	ctx = auth.WithPrincipal(ctx, &auth.Principal{Subject: options.Owner, Tenant: options.Tenant, Method: auth.MethodCLI})
+	ctx = logger.WithContext(ctx, s.log.WithField("command", "import"))
+
+	importRows := make([]*usecase.ImportFruitRow, len(rows))
+	for i, row := range rows {
+		importRows[i] = &usecase.ImportFruitRow{Line: row.Line, Name: row.Name, Quantity: row.Quantity, Price: row.Price}
+	}
+
+	return u.Execute(ctx, &usecase.ImportFruitsUseCaseInputDTO{
+		Rows:   importRows,
+		Owner:  options.Owner,
+		Mode:   options.Mode,
+		DryRun: options.DryRun,
+	})
+}
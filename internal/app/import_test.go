@@ -0,0 +1,57 @@
+package app_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/app"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func importFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "supplier.csv")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestImport(t *testing.T) {
+	t.Run("Create then upsert into the repository", func(t *testing.T) {
+		cfg := testConfig(t)
+		file := importFile(t, "name,quantity,price\nbanana,10,2.5\nuva,0,1\n")
+
+		output, err := app.Import(context.Background(), cfg, &app.ImportOptions{File: file, Owner: "bob", Tenant: "shop", Mode: usecase.ImportCreate}, io.Discard)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Created, 1)
+		assert.Equal(t, output.Failed, 1)
+		assert.EqualError(t, output.Rows[1].Err, "quantity must be greater than zero")
+
+		file = importFile(t, "Fruit,Qty,Price\nBanana,3,4\n")
+
+		output, err = app.Import(context.Background(), cfg, &app.ImportOptions{File: file, Owner: "bob", Tenant: "shop", Mode: usecase.ImportUpsert, DryRun: true}, io.Discard)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Updated, 1)
+
+		output, err = app.Import(context.Background(), cfg, &app.ImportOptions{File: file, Owner: "bob", Tenant: "other", Mode: usecase.ImportUpsert}, io.Discard)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Created, 1)
+	})
+
+	t.Run("With invalid options", func(t *testing.T) {
+		cfg := testConfig(t)
+		file := importFile(t, "name,quantity,price\n")
+
+		_, err := app.Import(context.Background(), cfg, &app.ImportOptions{File: file, Owner: "bob", Tenant: "not a tenant", Mode: usecase.ImportCreate}, io.Discard)
+		assert.EqualError(t, err, "tenant must be 1 to 64 letters, digits, dashes or underscores")
+
+		_, err = app.Import(context.Background(), cfg, &app.ImportOptions{File: filepath.Join(t.TempDir(), "missing.csv"), Owner: "bob", Tenant: "shop", Mode: usecase.ImportCreate}, io.Discard)
+		assert.ErrorContains(t, err, "fail to open import file")
+
+		cfg.Repository.Driver = "memory"
+		_, err = app.Import(context.Background(), cfg, &app.ImportOptions{File: file, Owner: "bob", Tenant: "shop", Mode: usecase.ImportCreate}, io.Discard)
+		assert.EqualError(t, err, "the memory repository doesn't outlive the import, import into sqlite or use a dry run")
+	})
+}
@@ -1,40 +1,318 @@
 package app
 
 import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/cursor"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/database"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/health"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/metrics"
 	"github.com/ruancaetano/go-gin-fruits/internal/infra/repository"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/tracing"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/job"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/swaggo/files"
 	"github.com/swaggo/gin-swagger"
 )
 
 type Server struct {
+	config  *config.Config
+	log     *logrus.Logger
+	metrics *metrics.Metrics
+	tracer  trace.TracerProvider
+	health  *health.Checker
+	// closers release the resources opened by Start, in reverse order
+	closers []io.Closer
+}
+
+func NewServer(cfg *config.Config) *Server {
+	return &Server{
+		config:  cfg,
+		log:     logger.New(cfg.Server.Mode, cfg.Log.Level, os.Stdout),
+		metrics: metrics.New(),
+		health:  health.NewChecker(cfg.Server.ReadinessTimeout),
+	}
+}
+
+// Start serve the api until ctx is done, then fail readiness for the configured drain delay, drain in-flight
+// requests within the configured shutdown timeout, stop the background jobs and release the repository resources
+func (s *Server) Start(ctx context.Context) (err error) {
+	defer func() {
+		if closeErr := s.close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	if err := s.setupTracing(ctx); err != nil {
+		return fmt.Errorf("fail to setup tracing: %w", err)
+	}
+
+	gin.SetMode(s.config.Server.Mode)
+	r := gin.New()
+	r.Use(middleware.RequestID(s.log), tracing.Middleware(s.tracer), middleware.AccessLog(), s.metrics.Middleware(), middleware.Recovery())
+
+	fruitRepository, err := s.makeFruitRepository(ctx)
+	if err != nil {
+		return fmt.Errorf("fail to setup fruit repository: %w", err)
+	}
+
+	// optional repository capabilities are looked up before decorating it, decorators hide them
+	if counter, ok := fruitRepository.(protocol.FruitStatusCounter); ok {
+		s.metrics.Register(metrics.NewFruitStatusCollector(counter))
+	}
+
+	if pinger, ok := fruitRepository.(protocol.Pinger); ok {
+		s.health.Register("repository", pinger.Ping)
+	}
+
+	// repository calls join the transaction through the context, so the decorated repository still takes part in it
+	transactor, _ := fruitRepository.(protocol.Transactor)
+
+	fruitRepository = tracing.NewFruitRepository(s.tracer, fruitRepository)
+
+	authenticator, err := s.makeAuthenticator()
+	if err != nil {
+		return fmt.Errorf("fail to setup authentication: %w", err)
+	}
+
+	cursors, err := s.makeCursorCodec()
+	if err != nil {
+		return fmt.Errorf("fail to setup search cursors: %w", err)
+	}
+
+	s.setupRoutes(r, fruitRepository, transactor, authenticator, cursors)
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	var jobs sync.WaitGroup
+	defer func() {
+		stopJobs()
+		jobs.Wait()
+	}()
+
+	if retentionJob := s.makeRetentionJob(fruitRepository); retentionJob != nil {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			retentionJob.Run(logger.WithContext(jobsCtx, s.log.WithField("job", "retention")))
+		}()
+	}
+
+	srv := &http.Server{
+		Addr:         s.config.Server.Addr(),
+		Handler:      r,
+		ReadTimeout:  s.config.Server.ReadTimeout,
+		WriteTimeout: s.config.Server.WriteTimeout,
+		IdleTimeout:  s.config.Server.IdleTimeout,
+		// lets routes such as exports move the write deadline
+		ConnContext: middleware.WithConn,
+	}
+
+	s.log.WithField("addr", srv.Addr).Info("server listening")
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return fmt.Errorf("fail to start server: %w", err)
+	case <-ctx.Done():
+	}
+
+	s.health.Drain()
+	if delay := s.config.Server.DrainDelay; delay > 0 {
+		s.log.Infof("shutting down, failing readiness for %s before draining connections", delay)
+		time.Sleep(delay)
+	}
+
+	s.log.Infof("shutting down, draining connections for up to %s", s.config.Server.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.config.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("fail to drain connections: %w", err)
+	}
+
+	return nil
 }
 
-func NewServer() *Server {
-	return &Server{}
+// close release every resource registered in closers, reporting the first failure
+func (s *Server) close() error {
+	var err error
+	for i := len(s.closers) - 1; i >= 0; i-- {
+		if closeErr := s.closers[i].Close(); closeErr != nil && err == nil {
+			err = fmt.Errorf("fail to release resources: %w", closeErr)
+		}
+	}
+	s.closers = nil
+
+	return err
+}
+
+// closerFunc adapt a cleanup function to io.Closer
+type closerFunc func() error
+
+func (f closerFunc) Close() error {
+	return f()
+}
+
+// setupTracing build the tracer provider for the configured exporter, its pending spans are flushed on close
+func (s *Server) setupTracing(ctx context.Context) error {
+	cfg := s.config.Tracing
+
+	exporter, err := tracing.NewExporter(ctx, cfg.Exporter, cfg.Endpoint, os.Stdout)
+	if err != nil {
+		return err
+	}
+
+	tp := tracing.NewTracerProvider(exporter, cfg.SampleRatio)
+	s.tracer = tp
+	s.closers = append(s.closers, closerFunc(func() error {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		return tp.Shutdown(shutdownCtx)
+	}))
+
+	return nil
+}
+
+// makeFruitRepository build the configured fruit repository backend
+func (s *Server) makeFruitRepository(ctx context.Context) (protocol.FruitRepository, error) {
+	switch driver := s.config.Repository.Driver; driver {
+	case "memory":
+		return repository.NewFruitMemoryRepository(), nil
+	case "sqlite":
+		db, err := database.OpenSQLite(ctx, s.config.Repository.SQLiteDSN)
+		if err != nil {
+			return nil, err
+		}
+		s.closers = append(s.closers, db)
+
+		return repository.NewFruitSQLiteRepository(db), nil
+	default:
+		return nil, fmt.Errorf("unknown fruit repository %q", driver)
+	}
+}
+
+// makeAuthenticator build the authenticator of the configured credentials, or one trusting the x-owner header
+// when authentication is disabled
+func (s *Server) makeAuthenticator() (infraauth.Authenticator, error) {
+	cfg := s.config.Auth
+	if !cfg.Enabled {
+		s.log.Warn("authentication disabled, callers are trusted from the x-owner header")
+		if cfg.DevRoles {
+			s.log.Warn("callers are trusted with the roles of the x-roles header")
+		}
+		return infraauth.NewDevAuthenticator(cfg.DevRoles), nil
+	}
+
+	var chain infraauth.Chain
+
+	if len(cfg.APIKeys) > 0 {
+		keys := make([]infraauth.APIKey, len(cfg.APIKeys))
+		for i, k := range cfg.APIKeys {
+			keys[i] = infraauth.APIKey{Key: k.Key, Subject: k.Subject, Roles: k.Roles, Tenant: k.Tenant}
+		}
+
+		a, err := infraauth.NewAPIKeyAuthenticator(keys)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+
+	if cfg.JWT.Secret != "" || cfg.JWT.JWKSFile != "" {
+		options := infraauth.JWTOptions{
+			Secret:   []byte(cfg.JWT.Secret),
+			Issuer:   cfg.JWT.Issuer,
+			Audience: cfg.JWT.Audience,
+		}
+
+		if cfg.JWT.JWKSFile != "" {
+			keys, err := infraauth.LoadJWKS(cfg.JWT.JWKSFile)
+			if err != nil {
+				return nil, err
+			}
+			options.Keys = keys
+		}
+
+		a, err := infraauth.NewJWTAuthenticator(options)
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, a)
+	}
+
+	return chain, nil
 }
 
-func (s *Server) Start() {
-	r := gin.Default()
+// makeCursorCodec build the codec signing search cursors with the configured secret, or a random one
+func (s *Server) makeCursorCodec() (protocol.FruitCursorCodec, error) {
+	secret := []byte(s.config.Search.CursorSecret)
+	if len(secret) == 0 {
+		s.log.Info("no cursor secret configured, search cursors won't survive restarts")
 
-	s.setupRoutes(r)
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, err
+		}
+	}
 
-	if r.Run() != nil {
-		panic("fail to start server")
+	return cursor.NewHMACCodec(secret), nil
+}
+
+// makeRetentionJob build the purge job, nil when the retention period is zero
+func (s *Server) makeRetentionJob(fruitRepository protocol.FruitRepository) *job.RetentionJob {
+	if s.config.Retention.Period <= 0 {
+		return nil
 	}
+
+	purgeExpiredFruitsUseCase := instrument(s, "purge_expired_fruits", usecase.NewPurgeExpiredFruitsUseCase(fruitRepository))
+
+	return job.NewRetentionJob(purgeExpiredFruitsUseCase, s.config.Retention.Period, s.config.Retention.Interval)
 }
 
-func (s *Server) setupRoutes(r *gin.Engine) {
-	mrepository := repository.NewFruitMemoryRepository()
+// instrument decorate u with the use case metrics and a tracing span
+func instrument[I any, O any](s *Server, name string, u protocol.UseCase[I, O]) protocol.UseCase[I, O] {
+	return metrics.InstrumentUseCase(s.metrics, name, tracing.TraceUseCase(s.tracer, name, u))
+}
 
-	searchFruitUseCase := usecase.NewSearchFruitUseCase(mrepository)
-	createFruitUseCase := usecase.NewCreateFruitUseCase(mrepository)
-	getFruitUseCase := usecase.NewGetFruitUseCase(mrepository)
-	updateFruitUseCase := usecase.NewUpdateFruitUseCase(mrepository)
-	deleteFruitUseCase := usecase.NewDeleteFruitUseCase(mrepository)
+func (s *Server) setupRoutes(r *gin.Engine, fruitRepository protocol.FruitRepository, transactor protocol.Transactor, authenticator infraauth.Authenticator, cursors protocol.FruitCursorCodec) {
+	authorizer := auth.NewOwnerAuthorizer(s.config.Auth.ScopeReads)
+
+	searchFruitUseCase := instrument(s, "search_fruit", usecase.NewSearchFruitUseCase(fruitRepository, authorizer, cursors))
+	exportFruitsUseCase := instrument(s, "export_fruits", usecase.NewExportFruitsUseCase(fruitRepository, authorizer))
+	createFruitUseCase := instrument(s, "create_fruit", usecase.NewCreateFruitUseCase(fruitRepository))
+	getFruitUseCase := instrument(s, "get_fruit", usecase.NewGetFruitUseCase(fruitRepository, authorizer))
+	updateFruitUseCase := instrument(s, "update_fruit", usecase.NewUpdateFruitUseCase(fruitRepository, authorizer))
+	patchFruitUseCase := instrument(s, "patch_fruit", usecase.NewPatchFruitUseCase(fruitRepository, authorizer))
+	deleteFruitUseCase := instrument(s, "delete_fruit", usecase.NewDeleteFruitUseCase(fruitRepository, authorizer))
+	transitionFruitUseCase := instrument(s, "transition_fruit", usecase.NewTransitionFruitUseCase(fruitRepository, authorizer))
+	restoreFruitUseCase := instrument(s, "restore_fruit", usecase.NewRestoreFruitUseCase(fruitRepository, authorizer))
+	purgeFruitUseCase := instrument(s, "purge_fruit", usecase.NewPurgeFruitUseCase(fruitRepository, authorizer))
+	bulkFruitUseCase := instrument(s, "bulk_fruit", usecase.NewBulkFruitUseCase(createFruitUseCase, updateFruitUseCase, deleteFruitUseCase, transactor))
+	importFruitsUseCase := instrument(s, "import_fruits", usecase.NewImportFruitsUseCase(fruitRepository, createFruitUseCase, updateFruitUseCase))
 
 	r.GET("/ping", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -42,11 +320,25 @@ func (s *Server) setupRoutes(r *gin.Engine) {
 		})
 	})
 
+	r.GET("/healthz", handler.MakeLivenessHandler())
+	r.GET("/readyz", handler.MakeReadinessHandler(s.health))
+
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	r.GET("/metrics", gin.WrapH(s.metrics.Handler()))
+
+	api := r.Group("", middleware.Authenticate(authenticator), middleware.Tenant())
+
+	api.GET("/fruits/search", handler.MakeSearchFruitHandler(searchFruitUseCase))
+	api.GET("/fruits/export", middleware.WriteTimeout(s.config.Server.ExportTimeout), handler.MakeExportFruitsHandler(exportFruitsUseCase))
+	api.GET("/fruits/:id", handler.MakeGetFruitHandler(getFruitUseCase))
+	api.POST("/fruits", handler.MakeCreateFruitHandler(createFruitUseCase))
+	api.POST("/fruits/bulk", handler.MakeBulkFruitHandler(bulkFruitUseCase))
+	api.POST("/fruits/import", handler.MakeImportFruitsHandler(importFruitsUseCase))
+	api.PUT("/fruits/:id", handler.MakeUpdateFruitHandler(updateFruitUseCase))
+	api.PATCH("/fruits/:id", handler.MakePatchFruitHandler(patchFruitUseCase))
+	api.DELETE("/fruits/:id", handler.MakeDeleteFruitHandler(deleteFruitUseCase))
+	api.POST("/fruits/:id/transitions", handler.MakeTransitionFruitHandler(transitionFruitUseCase))
+	api.POST("/fruits/:id/restore", handler.MakeRestoreFruitHandler(restoreFruitUseCase))
 
-	r.GET("/fruits/search", handler.MakeSearchFruitHandler(searchFruitUseCase))
-	r.GET("/fruits/:id", handler.MakeGetFruitHandler(getFruitUseCase))
-	r.POST("/fruits", handler.MakeCreateFruitHandler(createFruitUseCase))
-	r.PUT("/fruits/:id", handler.MakeUpdateFruitHandler(updateFruitUseCase))
-	r.DELETE("/fruits/:id", handler.MakeDeleteFruitHandler(deleteFruitUseCase))
+	api.DELETE("/admin/fruits/:id", handler.MakePurgeFruitHandler(purgeFruitUseCase))
 }
@@ -0,0 +1,232 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ruancaetano/go-gin-fruits/internal/app"
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/health"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func freePort(t *testing.T) int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+func testConfig(t *testing.T) *config.Config {
+	cfg := config.Default()
+	cfg.Server.Host = "127.0.0.1"
+	cfg.Server.Port = freePort(t)
+	cfg.Server.Mode = "test"
+	cfg.Server.ShutdownTimeout = time.Second
+	cfg.Repository.Driver = "sqlite"
+	cfg.Repository.SQLiteDSN = filepath.Join(t.TempDir(), "fruits.db")
+
+	return cfg
+}
+
+func TestServer_Start(t *testing.T) {
+	t.Run("Stop gracefully when context is done", func(t *testing.T) {
+		cfg := testConfig(t)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error, 1)
+		go func() {
+			done <- app.NewServer(cfg).Start(ctx)
+		}()
+
+		url := "http://" + cfg.Server.Addr() + "/ping"
+		assert.Eventually(t, func() bool {
+			resp, err := http.Get(url)
+			if err != nil {
+				return false
+			}
+			resp.Body.Close()
+			return resp.StatusCode == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		cancel()
+
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not stop")
+		}
+
+		_, err := http.Get(url)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Fail readiness while draining", func(t *testing.T) {
+		cfg := testConfig(t)
+		cfg.Server.DrainDelay = time.Second
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan error, 1)
+		go func() {
+			done <- app.NewServer(cfg).Start(ctx)
+		}()
+
+		probe := func() (int, *health.Report) {
+			resp, err := http.Get("http://" + cfg.Server.Addr() + "/readyz")
+			if err != nil {
+				return 0, nil
+			}
+			defer resp.Body.Close()
+
+			var report health.Report
+			assert.Nil(t, json.NewDecoder(resp.Body).Decode(&report))
+			return resp.StatusCode, &report
+		}
+
+		assert.Eventually(t, func() bool {
+			status, _ := probe()
+			return status == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		_, report := probe()
+		assert.Equal(t, report.Components["repository"].Status, health.StatusUp)
+
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			status, report := probe()
+			return status == http.StatusServiceUnavailable && report.Draining
+		}, time.Second, 10*time.Millisecond)
+
+		select {
+		case err := <-done:
+			assert.Nil(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("server did not stop")
+		}
+	})
+
+	t.Run("Require credentials on the fruit routes when auth is enabled", func(t *testing.T) {
+		cfg := testConfig(t)
+		cfg.Auth.Enabled = true
+		cfg.Auth.APIKeys = []config.APIKeyConfig{{Key: "s3cr3t", Subject: "ruan"}}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = app.NewServer(cfg).Start(ctx)
+		}()
+
+		get := func(path string, key string) int {
+			req, err := http.NewRequest("GET", "http://"+cfg.Server.Addr()+path, nil)
+			assert.Nil(t, err)
+			if key != "" {
+				req.Header.Set("X-API-Key", key)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return 0
+			}
+			resp.Body.Close()
+			return resp.StatusCode
+		}
+
+		assert.Eventually(t, func() bool {
+			return get("/healthz", "") == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		assert.Equal(t, get("/fruits/search?name=uva&status=comestible&offset=1&limit=10", ""), http.StatusUnauthorized)
+		assert.Equal(t, get("/fruits/search?name=uva&status=comestible&offset=1&limit=10", "wrong"), http.StatusUnauthorized)
+		assert.Equal(t, get("/fruits/search?name=uva&status=comestible&offset=1&limit=10", "s3cr3t"), http.StatusOK)
+	})
+
+	t.Run("Keep tenants fruits apart", func(t *testing.T) {
+		cfg := testConfig(t)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		go func() {
+			_ = app.NewServer(cfg).Start(ctx)
+		}()
+
+		do := func(method string, path string, tenant string, body string) (int, map[string]interface{}) {
+			req, err := http.NewRequest(method, "http://"+cfg.Server.Addr()+path, strings.NewReader(body))
+			assert.Nil(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("x-owner", "ruan")
+			if tenant != "" {
+				req.Header.Set("X-Tenant-ID", tenant)
+			}
+
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				return 0, nil
+			}
+			defer resp.Body.Close()
+
+			var response map[string]interface{}
+			_ = json.NewDecoder(resp.Body).Decode(&response)
+			return resp.StatusCode, response
+		}
+
+		assert.Eventually(t, func() bool {
+			code, _ := do("GET", "/healthz", "", "")
+			return code == http.StatusOK
+		}, 5*time.Second, 10*time.Millisecond)
+
+		code, created := do("POST", "/fruits", "acme", `{"name":"uva","quantity":1,"price":10}`)
+		assert.Equal(t, code, http.StatusCreated)
+		id := created["id"].(string)
+
+		code, _ = do("GET", "/fruits/"+id, "acme", "")
+		assert.Equal(t, code, http.StatusOK)
+		code, _ = do("GET", "/fruits/"+id, "globex", "")
+		assert.Equal(t, code, http.StatusNotFound)
+		code, _ = do("GET", "/fruits/"+id, "", "")
+		assert.Equal(t, code, http.StatusNotFound)
+
+		_, found := do("GET", "/fruits/search?name=uva&status=comestible&offset=1&limit=10", "globex", "")
+		assert.Equal(t, found["Paging"].(map[string]interface{})["total"], 0.0)
+		_, found = do("GET", "/fruits/search?name=uva&status=comestible&offset=1&limit=10", "acme", "")
+		assert.Equal(t, found["Paging"].(map[string]interface{})["total"], 1.0)
+
+		code, _ = do("GET", "/fruits/"+id, "acme/globex", "")
+		assert.Equal(t, code, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("With missing jwks file", func(t *testing.T) {
+		cfg := testConfig(t)
+		cfg.Auth.Enabled = true
+		cfg.Auth.JWT.JWKSFile = filepath.Join(t.TempDir(), "jwks.json")
+
+		err := app.NewServer(cfg).Start(context.Background())
+		assert.ErrorContains(t, err, "fail to setup authentication")
+	})
+
+	t.Run("With address already in use", func(t *testing.T) {
+		cfg := testConfig(t)
+
+		l, err := net.Listen("tcp", cfg.Server.Addr())
+		assert.Nil(t, err)
+		defer l.Close()
+
+		err = app.NewServer(cfg).Start(context.Background())
+		assert.ErrorContains(t, err, "fail to start server")
+	})
+
+	t.Run("With unreachable sqlite database", func(t *testing.T) {
+		cfg := testConfig(t)
+		cfg.Repository.SQLiteDSN = filepath.Join(t.TempDir(), "missing", "fruits.db")
+
+		err := app.NewServer(cfg).Start(context.Background())
+		assert.ErrorContains(t, err, "fail to setup fruit repository")
+	})
+}
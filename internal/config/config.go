@@ -0,0 +1,446 @@
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Server     ServerConfig     `yaml:"server"`
+	Repository RepositoryConfig `yaml:"repository"`
+	Retention  RetentionConfig  `yaml:"retention"`
+	Log        LogConfig        `yaml:"log"`
+	Tracing    TracingConfig    `yaml:"tracing"`
+	Auth       AuthConfig       `yaml:"auth"`
+	Search     SearchConfig     `yaml:"search"`
+}
+
+type ServerConfig struct {
+	Host            string        `yaml:"host"`
+	Port            int           `yaml:"port"`
+	Mode            string        `yaml:"mode"` // gin mode: debug, release or test
+	ReadTimeout     time.Duration `yaml:"read_timeout"`
+	WriteTimeout    time.Duration `yaml:"write_timeout"`
+	ExportTimeout   time.Duration `yaml:"export_timeout"` // how long a fruit export gets to stream in place of WriteTimeout, 0 for no limit
+	IdleTimeout     time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"` // how long in-flight requests get to finish on stop
+	// DrainDelay how long /readyz fails before the server stops accepting connections on stop,
+	// giving load balancers time to route traffic elsewhere
+	DrainDelay       time.Duration `yaml:"drain_delay"`
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"` // how long each dependency gets to answer the readiness probe
+}
+
+type RepositoryConfig struct {
+	// Driver is the fruit storage backend: memory or sqlite
+	Driver    string `yaml:"driver"`
+	SQLiteDSN string `yaml:"sqlite_dsn"`
+}
+
+type RetentionConfig struct {
+	// Period deleted fruits are kept before being purged, zero keeps them forever
+	Period   time.Duration `yaml:"period"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+type LogConfig struct {
+	// Level the minimum level logged: debug, info, warn or error. Logs are json lines in release mode, text otherwise
+	Level string `yaml:"level"`
+}
+
+type SearchConfig struct {
+	// CursorSecret sign the search cursors handed to clients. Every instance serving the same clients must share it,
+	// when empty a random one is generated at startup and cursors don't survive restarts
+	CursorSecret string `yaml:"cursor_secret"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter"`     // none, stdout or otlp
+	Endpoint    string  `yaml:"endpoint"`     // otlp http collector host:port
+	SampleRatio float64 `yaml:"sample_ratio"` // share of new traces recorded, incoming traces keep their decision
+}
+
+type AuthConfig struct {
+	// Enabled require an api key or a bearer token on the fruit routes. When disabled the caller identity is
+	// taken from the x-owner header as it is, which is only fit for local development and refused in release mode
+	Enabled bool           `yaml:"enabled"`
+	APIKeys []APIKeyConfig `yaml:"api_keys"`
+	JWT     JWTConfig      `yaml:"jwt"`
+	// ScopeReads restrict callers other than admins to reading their own fruits
+	ScopeReads bool `yaml:"scope_reads"`
+	// DevRoles trust the roles of the x-roles header while auth is disabled, to try admin routes locally. Only
+	// allowed in debug mode, callers have no role otherwise
+	DevRoles bool `yaml:"dev_roles"`
+}
+
+type APIKeyConfig struct {
+	Key     string   `yaml:"key"`
+	Subject string   `yaml:"subject"`
+	Roles   []string `yaml:"roles"`
+	// Tenant bind the key to a tenant. Leave empty to let an admin pick one with the X-Tenant-ID header, other
+	// callers then only reach the default tenant
+	Tenant string `yaml:"tenant"`
+}
+
+type JWTConfig struct {
+	Secret   string `yaml:"secret"`    // verify HS256 tokens
+	JWKSFile string `yaml:"jwks_file"` // verify RS256 tokens with the keys of a local JSON Web Key Set
+	Issuer   string `yaml:"issuer"`    // when set, tokens must carry it as iss claim
+	Audience string `yaml:"audience"`  // when set, tokens must carry it in the aud claim
+}
+
+// Default return the configuration used when nothing else is set, suited for local development
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:          8080,
+			Mode:          "debug",
+			ReadTimeout:   10 * time.Second,
+			WriteTimeout:  10 * time.Second,
+			ExportTimeout: 10 * time.Minute, // exports stream the whole inventory
+			IdleTimeout:   60 * time.Second,
+			// under the 30s grace period docker and kubernetes give before killing the process
+			ShutdownTimeout:  25 * time.Second,
+			ReadinessTimeout: 2 * time.Second,
+		},
+		Repository: RepositoryConfig{
+			Driver:    "memory",
+			SQLiteDSN: "fruits.db",
+		},
+		// deleted fruits stay restorable until a retention period is opted into
+		Retention: RetentionConfig{
+			Interval: time.Hour,
+		},
+		Log: LogConfig{
+			Level: "info",
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			Endpoint:    "localhost:4318",
+			SampleRatio: 1,
+		},
+	}
+}
+
+// Addr the address the http server listens on
+func (sc ServerConfig) Addr() string {
+	return net.JoinHostPort(sc.Host, strconv.Itoa(sc.Port))
+}
+
+// setting a configuration entry that can be overridden by an env var and a command-line flag
+type setting struct {
+	flag  string
+	env   string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+var settings = []setting{
+	{"host", "FRUITS_HOST", "interface the server listens on", func(cfg *Config, v string) error {
+		cfg.Server.Host = v
+		return nil
+	}},
+	{"port", "FRUITS_PORT", "port the server listens on", func(cfg *Config, v string) error {
+		return parseInt(v, &cfg.Server.Port)
+	}},
+	{"mode", "FRUITS_MODE", "gin mode: debug, release or test", func(cfg *Config, v string) error {
+		cfg.Server.Mode = v
+		return nil
+	}},
+	{"read-timeout", "FRUITS_READ_TIMEOUT", "max duration to read a request", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.ReadTimeout)
+	}},
+	{"write-timeout", "FRUITS_WRITE_TIMEOUT", "max duration to write a response", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.WriteTimeout)
+	}},
+	{"export-timeout", "FRUITS_EXPORT_TIMEOUT", "max duration to stream a fruit export, 0 for no limit", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.ExportTimeout)
+	}},
+	{"idle-timeout", "FRUITS_IDLE_TIMEOUT", "max duration to keep an idle connection open", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.IdleTimeout)
+	}},
+	{"shutdown-timeout", "FRUITS_SHUTDOWN_TIMEOUT", "max duration to drain in-flight requests on shutdown", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.ShutdownTimeout)
+	}},
+	{"drain-delay", "FRUITS_DRAIN_DELAY", "how long readiness fails before connections are drained on shutdown", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.DrainDelay)
+	}},
+	{"readiness-timeout", "FRUITS_READINESS_TIMEOUT", "max duration each dependency gets to answer the readiness probe", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Server.ReadinessTimeout)
+	}},
+	{"repository", "FRUITS_REPOSITORY", "fruit storage: memory or sqlite", func(cfg *Config, v string) error {
+		cfg.Repository.Driver = v
+		return nil
+	}},
+	{"sqlite-dsn", "FRUITS_SQLITE_DSN", "sqlite database file", func(cfg *Config, v string) error {
+		cfg.Repository.SQLiteDSN = v
+		return nil
+	}},
+	{"retention-period", "FRUITS_RETENTION_PERIOD", "how long deleted fruits are kept, 0 keeps them forever", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Retention.Period)
+	}},
+	{"retention-interval", "FRUITS_RETENTION_INTERVAL", "how often expired fruits are purged", func(cfg *Config, v string) error {
+		return parseDuration(v, &cfg.Retention.Interval)
+	}},
+	{"log-level", "FRUITS_LOG_LEVEL", "minimum level logged: debug, info, warn or error", func(cfg *Config, v string) error {
+		cfg.Log.Level = v
+		return nil
+	}},
+	{"auth-enabled", "FRUITS_AUTH_ENABLED", "require an api key or a bearer token on the fruit routes", func(cfg *Config, v string) error {
+		return parseBool(v, &cfg.Auth.Enabled)
+	}},
+	{"auth-scope-reads", "FRUITS_AUTH_SCOPE_READS", "restrict callers other than admins to reading their own fruits", func(cfg *Config, v string) error {
+		return parseBool(v, &cfg.Auth.ScopeReads)
+	}},
+	{"auth-dev-roles", "FRUITS_AUTH_DEV_ROLES", "trust the x-roles header while auth is disabled, debug mode only", func(cfg *Config, v string) error {
+		return parseBool(v, &cfg.Auth.DevRoles)
+	}},
+	{"jwt-secret", "FRUITS_JWT_SECRET", "secret verifying HS256 bearer tokens", func(cfg *Config, v string) error {
+		cfg.Auth.JWT.Secret = v
+		return nil
+	}},
+	{"jwks-file", "FRUITS_JWKS_FILE", "JSON Web Key Set file verifying RS256 bearer tokens", func(cfg *Config, v string) error {
+		cfg.Auth.JWT.JWKSFile = v
+		return nil
+	}},
+	{"jwt-issuer", "FRUITS_JWT_ISSUER", "issuer bearer tokens must carry", func(cfg *Config, v string) error {
+		cfg.Auth.JWT.Issuer = v
+		return nil
+	}},
+	{"jwt-audience", "FRUITS_JWT_AUDIENCE", "audience bearer tokens must carry", func(cfg *Config, v string) error {
+		cfg.Auth.JWT.Audience = v
+		return nil
+	}},
+	{"cursor-secret", "FRUITS_CURSOR_SECRET", "secret signing the search cursors", func(cfg *Config, v string) error {
+		cfg.Search.CursorSecret = v
+		return nil
+	}},
+	{"tracing-exporter", "FRUITS_TRACING_EXPORTER", "where spans are sent: none, stdout or otlp", func(cfg *Config, v string) error {
+		cfg.Tracing.Exporter = v
+		return nil
+	}},
+	{"tracing-endpoint", "FRUITS_TRACING_ENDPOINT", "otlp http collector host:port", func(cfg *Config, v string) error {
+		cfg.Tracing.Endpoint = v
+		return nil
+	}},
+	{"tracing-sample-ratio", "FRUITS_TRACING_SAMPLE_RATIO", "share of new traces recorded, between 0 and 1", func(cfg *Config, v string) error {
+		return parseFloat(v, &cfg.Tracing.SampleRatio)
+	}},
+}
+
+// Load build the configuration from, by increasing precedence: defaults, the yaml file pointed by
+// -config or FRUITS_CONFIG, FRUITS_* env vars and command-line flags. The result is validated.
+func Load(args []string, getenv func(string) string) (*Config, error) {
+	fs := flag.NewFlagSet("fruits", flag.ContinueOnError)
+	path := fs.String("config", getenv("FRUITS_CONFIG"), "yaml config file")
+
+	flags := map[string]string{}
+	for _, s := range settings {
+		name := s.flag
+		fs.Func(name, fmt.Sprintf("%s (env %s)", s.usage, s.env), func(v string) error {
+			flags[name] = v
+			return nil
+		})
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	cfg := Default()
+
+	if *path != "" {
+		if err := loadFile(*path, cfg); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, s := range settings {
+		if v := getenv(s.env); v != "" {
+			if err := s.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", s.env, err)
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if v, ok := flags[s.flag]; ok {
+			if err := s.set(cfg, v); err != nil {
+				return nil, fmt.Errorf("invalid -%s: %w", s.flag, err)
+			}
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadFile(path string, cfg *Config) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("fail to open config file: %w", err)
+	}
+	defer f.Close()
+
+	decoder := yaml.NewDecoder(f)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil {
+		return fmt.Errorf("fail to read config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// Validate check every setting, reporting all the invalid ones at once
+func (c *Config) Validate() error {
+	var problems []string
+
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		problems = append(problems, "server port must be between 1 and 65535")
+	}
+
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		problems = append(problems, "server mode must be one of debug, release, test")
+	}
+
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.ExportTimeout < 0 || c.Server.IdleTimeout < 0 {
+		problems = append(problems, "server timeouts cannot be negative")
+	}
+
+	if c.Server.ShutdownTimeout <= 0 {
+		problems = append(problems, "server shutdown timeout must be greater than zero")
+	}
+
+	if c.Server.DrainDelay < 0 {
+		problems = append(problems, "server drain delay cannot be negative")
+	}
+
+	if c.Server.ReadinessTimeout <= 0 {
+		problems = append(problems, "server readiness timeout must be greater than zero")
+	}
+
+	switch c.Repository.Driver {
+	case "memory":
+	case "sqlite":
+		if c.Repository.SQLiteDSN == "" {
+			problems = append(problems, "sqlite dsn is required by the sqlite repository")
+		}
+	default:
+		problems = append(problems, "repository driver must be one of memory, sqlite")
+	}
+
+	if c.Retention.Period < 0 {
+		problems = append(problems, "retention period cannot be negative")
+	}
+
+	if c.Retention.Period > 0 && c.Retention.Interval <= 0 {
+		problems = append(problems, "retention interval must be greater than zero")
+	}
+
+	switch c.Log.Level {
+	case "debug", "info", "warn", "error":
+	default:
+		problems = append(problems, "log level must be one of debug, info, warn, error")
+	}
+
+	switch c.Tracing.Exporter {
+	case "none", "stdout":
+	case "otlp":
+		if c.Tracing.Endpoint == "" {
+			problems = append(problems, "tracing endpoint is required by the otlp exporter")
+		}
+	default:
+		problems = append(problems, "tracing exporter must be one of none, stdout, otlp")
+	}
+
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "tracing sample ratio must be between 0 and 1")
+	}
+
+	// without auth callers are whoever their headers claim, which is only fit for local development
+	if !c.Auth.Enabled && c.Server.Mode == "release" {
+		problems = append(problems, "auth must be enabled in release mode")
+	}
+
+	if c.Auth.DevRoles && (c.Auth.Enabled || c.Server.Mode != "debug") {
+		problems = append(problems, "auth dev roles are only trusted in debug mode with auth disabled")
+	}
+
+	if c.Auth.Enabled && len(c.Auth.APIKeys) == 0 && c.Auth.JWT.Secret == "" && c.Auth.JWT.JWKSFile == "" {
+		problems = append(problems, "auth needs api keys, a jwt secret or a jwks file")
+	}
+
+	for _, k := range c.Auth.APIKeys {
+		if k.Key == "" || k.Subject == "" {
+			problems = append(problems, "every api key needs a key and a subject")
+			break
+		}
+	}
+
+	if c.Auth.JWT.Secret != "" && len(c.Auth.JWT.Secret) < 32 {
+		problems = append(problems, "jwt secret must be at least 32 bytes")
+	}
+
+	if c.Search.CursorSecret != "" && len(c.Search.CursorSecret) < 32 {
+		problems = append(problems, "cursor secret must be at least 32 bytes")
+	}
+
+	if len(problems) > 0 {
+		return errors.New("invalid config: " + strings.Join(problems, "; "))
+	}
+
+	return nil
+}
+
+func parseInt(v string, dst *int) error {
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return err
+	}
+
+	*dst = i
+	return nil
+}
+
+func parseDuration(v string, dst *time.Duration) error {
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return err
+	}
+
+	*dst = d
+	return nil
+}
+
+func parseFloat(v string, dst *float64) error {
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return err
+	}
+
+	*dst = f
+	return nil
+}
+
+func parseBool(v string, dst *bool) error {
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return err
+	}
+
+	*dst = b
+	return nil
+}
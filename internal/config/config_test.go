@@ -0,0 +1,194 @@
+package config_test
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/config"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func envOf(vars map[string]string) func(string) string {
+	return func(name string) string {
+		return vars[name]
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "fruits.yaml")
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	t.Run("With defaults", func(t *testing.T) {
+		cfg, err := config.Load(nil, envOf(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, cfg, config.Default())
+	})
+
+	t.Run("With config file", func(t *testing.T) {
+		path := writeConfigFile(t, `
+server:
+  port: 9090
+  mode: release
+  read_timeout: 5s
+  export_timeout: 1h
+repository:
+  driver: sqlite
+  sqlite_dsn: /tmp/fruits.db
+retention:
+  period: 720h
+auth:
+  enabled: true
+  api_keys:
+    - key: s3cr3t
+      subject: ruan
+      roles: [admin]
+`)
+
+		cfg, err := config.Load([]string{"-config", path}, envOf(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, cfg.Server.Port, 9090)
+		assert.Equal(t, cfg.Server.Mode, "release")
+		assert.Equal(t, cfg.Server.ReadTimeout, 5*time.Second)
+		assert.Equal(t, cfg.Server.WriteTimeout, 10*time.Second)
+		assert.Equal(t, cfg.Server.ExportTimeout, time.Hour)
+		assert.Equal(t, cfg.Repository.Driver, "sqlite")
+		assert.Equal(t, cfg.Repository.SQLiteDSN, "/tmp/fruits.db")
+		assert.Equal(t, cfg.Retention.Period, 720*time.Hour)
+		assert.True(t, cfg.Auth.Enabled)
+		assert.Equal(t, cfg.Auth.APIKeys, []config.APIKeyConfig{{Key: "s3cr3t", Subject: "ruan", Roles: []string{"admin"}}})
+	})
+
+	t.Run("Env vars override config file and flags override env vars", func(t *testing.T) {
+		path := writeConfigFile(t, `
+server:
+  port: 9090
+  mode: release
+repository:
+  driver: sqlite
+auth:
+  enabled: true
+  api_keys:
+    - key: s3cr3t
+      subject: ruan
+`)
+		env := envOf(map[string]string{
+			"FRUITS_CONFIG":     path,
+			"FRUITS_PORT":       "9191",
+			"FRUITS_REPOSITORY": "memory",
+		})
+
+		cfg, err := config.Load([]string{"-port", "9292"}, env)
+		assert.Nil(t, err)
+		assert.Equal(t, cfg.Server.Port, 9292)
+		assert.Equal(t, cfg.Server.Mode, "release")
+		assert.Equal(t, cfg.Repository.Driver, "memory")
+	})
+
+	t.Run("With unknown config file field", func(t *testing.T) {
+		path := writeConfigFile(t, "server:\n  prot: 9090\n")
+
+		cfg, err := config.Load([]string{"-config", path}, envOf(nil))
+		assert.Nil(t, cfg)
+		assert.ErrorContains(t, err, "field prot not found")
+	})
+
+	t.Run("With missing config file", func(t *testing.T) {
+		cfg, err := config.Load([]string{"-config", "missing.yaml"}, envOf(nil))
+		assert.Nil(t, cfg)
+		assert.ErrorContains(t, err, "fail to open config file")
+	})
+
+	t.Run("With malformed env var", func(t *testing.T) {
+		cfg, err := config.Load(nil, envOf(map[string]string{"FRUITS_READ_TIMEOUT": "soon"}))
+		assert.Nil(t, cfg)
+		assert.ErrorContains(t, err, "invalid FRUITS_READ_TIMEOUT")
+	})
+
+	t.Run("With tracing flags", func(t *testing.T) {
+		cfg, err := config.Load([]string{"-tracing-exporter", "otlp", "-tracing-sample-ratio", "0.25"}, envOf(nil))
+		assert.Nil(t, err)
+		assert.Equal(t, cfg.Tracing.Exporter, "otlp")
+		assert.Equal(t, cfg.Tracing.Endpoint, "localhost:4318")
+		assert.Equal(t, cfg.Tracing.SampleRatio, 0.25)
+	})
+
+	t.Run("With auth env vars", func(t *testing.T) {
+		cfg, err := config.Load(nil, envOf(map[string]string{
+			"FRUITS_AUTH_ENABLED":     "true",
+			"FRUITS_AUTH_SCOPE_READS": "1",
+			"FRUITS_JWT_SECRET":       "a-secret-long-enough-for-hs256!!",
+			"FRUITS_JWT_ISSUER":       "fruits-idp",
+		}))
+		assert.Nil(t, err)
+		assert.True(t, cfg.Auth.Enabled)
+		assert.True(t, cfg.Auth.ScopeReads)
+		assert.Equal(t, cfg.Auth.JWT.Secret, "a-secret-long-enough-for-hs256!!")
+		assert.Equal(t, cfg.Auth.JWT.Issuer, "fruits-idp")
+	})
+
+	t.Run("With malformed flag", func(t *testing.T) {
+		cfg, err := config.Load([]string{"-port", "http"}, envOf(nil))
+		assert.Nil(t, cfg)
+		assert.ErrorContains(t, err, "invalid -port")
+	})
+
+	t.Run("With invalid settings", func(t *testing.T) {
+		cfg, err := config.Load([]string{"-port", "0", "-mode", "prod", "-repository", "mongo"}, envOf(nil))
+		assert.Nil(t, cfg)
+		assert.EqualError(t, err, "invalid config: server port must be between 1 and 65535; server mode must be one of debug, release, test; repository driver must be one of memory, sqlite")
+	})
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := config.Default()
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Repository.Driver = "sqlite"
+	cfg.Repository.SQLiteDSN = ""
+	cfg.Server.IdleTimeout = -time.Second
+	cfg.Server.ShutdownTimeout = 0
+	cfg.Server.DrainDelay = -time.Second
+	cfg.Server.ReadinessTimeout = 0
+	cfg.Retention.Period = 720 * time.Hour
+	cfg.Retention.Interval = 0
+	cfg.Log.Level = "trace"
+	cfg.Tracing.Exporter = "otlp"
+	cfg.Tracing.Endpoint = ""
+	cfg.Tracing.SampleRatio = 2
+	cfg.Auth.APIKeys = []config.APIKeyConfig{{Key: "s3cr3t"}}
+	cfg.Auth.JWT.Secret = "short"
+	cfg.Search.CursorSecret = "short"
+	assert.EqualError(t, cfg.Validate(), "invalid config: server timeouts cannot be negative; server shutdown timeout must be greater than zero; server drain delay cannot be negative; server readiness timeout must be greater than zero; sqlite dsn is required by the sqlite repository; retention interval must be greater than zero; log level must be one of debug, info, warn, error; tracing endpoint is required by the otlp exporter; tracing sample ratio must be between 0 and 1; every api key needs a key and a subject; jwt secret must be at least 32 bytes; cursor secret must be at least 32 bytes")
+
+	cfg = config.Default()
+	cfg.Auth.Enabled = true
+	assert.EqualError(t, cfg.Validate(), "invalid config: auth needs api keys, a jwt secret or a jwks file")
+
+	cfg = config.Default()
+	cfg.Server.Mode = "release"
+	assert.EqualError(t, cfg.Validate(), "invalid config: auth must be enabled in release mode")
+
+	cfg.Auth.Enabled = true
+	cfg.Auth.APIKeys = []config.APIKeyConfig{{Key: "s3cr3t", Subject: "ruan"}}
+	assert.Nil(t, cfg.Validate())
+
+	cfg = config.Default()
+	cfg.Auth.DevRoles = true
+	assert.Nil(t, cfg.Validate())
+
+	cfg.Server.Mode = "test"
+	assert.EqualError(t, cfg.Validate(), "invalid config: auth dev roles are only trusted in debug mode with auth disabled")
+
+	cfg = config.Default()
+	cfg.Retention.Interval = 0
+	assert.Nil(t, cfg.Validate())
+}
+
+func TestServerConfig_Addr(t *testing.T) {
+	assert.Equal(t, config.ServerConfig{Port: 8080}.Addr(), ":8080")
+	assert.Equal(t, config.ServerConfig{Host: "127.0.0.1", Port: 9090}.Addr(), "127.0.0.1:9090")
+}
@@ -0,0 +1,78 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+type Action string
+
+const (
+	ActionRead       Action = "read"
+	ActionUpdate     Action = "update"
+	ActionDelete     Action = "delete"
+	ActionTransition Action = "transition"
+	ActionRestore    Action = "restore"
+	ActionPurge      Action = "purge"
+	ActionTransfer   Action = "transfer" // hand the fruit over to another owner
+)
+
+// OwnerAuthorizer let the owner of a fruit or an admin modify it, and only admins purge or transfer fruits.
+// With scopeReads, callers other than admins only see their own fruits.
+type OwnerAuthorizer struct {
+	scopeReads bool
+}
+
+func NewOwnerAuthorizer(scopeReads bool) *OwnerAuthorizer {
+	return &OwnerAuthorizer{
+		scopeReads: scopeReads,
+	}
+}
+
+// Authorize fail with a Forbidden error when the principal of ctx may not perform action on fruit,
+// and with an Unauthenticated one when ctx carries no principal
+func (oa *OwnerAuthorizer) Authorize(ctx context.Context, action Action, fruit *entity.Fruit) error {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return domainerror.NewUnauthenticatedError("missing credentials")
+	}
+
+	if principal.HasRole(RoleAdmin) {
+		return nil
+	}
+
+	switch action {
+	case ActionPurge:
+		return domainerror.NewForbiddenError("only admins can purge fruits")
+	case ActionTransfer:
+		return domainerror.NewForbiddenError("only admins can transfer fruits to another owner")
+	case ActionRead:
+		if oa.scopeReads && fruit.Owner != principal.Subject {
+			return domainerror.NewForbiddenError("fruit belongs to another owner")
+		}
+		return nil
+	default:
+		if fruit.Owner != principal.Subject {
+			return domainerror.NewForbiddenError(fmt.Sprintf("only the owner of the fruit or an admin can %s it", action))
+		}
+		return nil
+	}
+}
+
+// ReadScope return the owner the principal of ctx is restricted to when searching fruits, empty when
+// it may read every fruit
+func (oa *OwnerAuthorizer) ReadScope(ctx context.Context) (string, error) {
+	principal, ok := PrincipalFrom(ctx)
+	if !ok {
+		return "", domainerror.NewUnauthenticatedError("missing credentials")
+	}
+
+	if !oa.scopeReads || principal.HasRole(RoleAdmin) {
+		return "", nil
+	}
+
+	return principal.Subject, nil
+}
@@ -0,0 +1,74 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOwnerAuthorizer_Authorize(t *testing.T) {
+	fruit := &entity.Fruit{ID: "some-uuid", Owner: "ruan"}
+
+	owner := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "ruan"})
+	other := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "clerk"})
+	admin := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "boss", Roles: []string{auth.RoleAdmin}})
+
+	cases := []struct {
+		name       string
+		ctx        context.Context
+		action     auth.Action
+		scopeReads bool
+		kind       domainerror.Kind
+	}{
+		{"owner updates", owner, auth.ActionUpdate, false, ""},
+		{"owner deletes", owner, auth.ActionDelete, false, ""},
+		{"owner purges", owner, auth.ActionPurge, false, domainerror.Forbidden},
+		{"owner transfers", owner, auth.ActionTransfer, false, domainerror.Forbidden},
+		{"other updates", other, auth.ActionUpdate, false, domainerror.Forbidden},
+		{"other transitions", other, auth.ActionTransition, false, domainerror.Forbidden},
+		{"other restores", other, auth.ActionRestore, false, domainerror.Forbidden},
+		{"other reads", other, auth.ActionRead, false, ""},
+		{"other reads scoped", other, auth.ActionRead, true, domainerror.Forbidden},
+		{"owner reads scoped", owner, auth.ActionRead, true, ""},
+		{"admin updates", admin, auth.ActionUpdate, false, ""},
+		{"admin purges", admin, auth.ActionPurge, false, ""},
+		{"admin transfers", admin, auth.ActionTransfer, false, ""},
+		{"admin reads scoped", admin, auth.ActionRead, true, ""},
+		{"anonymous reads", context.Background(), auth.ActionRead, false, domainerror.Unauthenticated},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := auth.NewOwnerAuthorizer(c.scopeReads).Authorize(c.ctx, c.action, fruit)
+			if c.kind == "" {
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.Equal(t, domainerror.KindOf(err), c.kind)
+		})
+	}
+}
+
+func TestOwnerAuthorizer_ReadScope(t *testing.T) {
+	owner := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "ruan"})
+	admin := auth.WithPrincipal(context.Background(), &auth.Principal{Subject: "boss", Roles: []string{auth.RoleAdmin}})
+
+	scope, err := auth.NewOwnerAuthorizer(false).ReadScope(owner)
+	assert.Nil(t, err)
+	assert.Empty(t, scope)
+
+	scope, err = auth.NewOwnerAuthorizer(true).ReadScope(owner)
+	assert.Nil(t, err)
+	assert.Equal(t, scope, "ruan")
+
+	scope, err = auth.NewOwnerAuthorizer(true).ReadScope(admin)
+	assert.Nil(t, err)
+	assert.Empty(t, scope)
+
+	_, err = auth.NewOwnerAuthorizer(true).ReadScope(context.Background())
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+}
@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+)
+
+// RoleAdmin grant access to the administrative operations
+const RoleAdmin = "admin"
+
+// how a principal identity was verified
+const (
+	MethodAPIKey = "api_key"
+	MethodJWT    = "jwt"
+	// MethodDev the identity claimed by the request as it is, authentication being disabled
+	MethodDev = "dev"
+	// MethodCLI the identity given to a command run by an operator
+	MethodCLI = "cli"
+)
+
+// Principal the verified identity a request is served on behalf of
+type Principal struct {
+	Subject string
+	Roles   []string
+	// Tenant the tenant the credentials are bound to, empty when they may act for any tenant
+	Tenant string
+	// Method how the identity was verified, one of the Method constants
+	Method string
+}
+
+func (p *Principal) HasRole(role string) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+type contextKey struct{}
+
+// WithPrincipal return a copy of ctx carrying p, so the use cases know who they act for
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// PrincipalFrom return the principal carried by ctx, if any
+func PrincipalFrom(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(*Principal)
+	return p, ok && p != nil
+}
@@ -0,0 +1,26 @@
+package auth_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPrincipal_HasRole(t *testing.T) {
+	p := &auth.Principal{Subject: "ruan", Roles: []string{"clerk", auth.RoleAdmin}}
+
+	assert.True(t, p.HasRole(auth.RoleAdmin))
+	assert.False(t, p.HasRole("owner"))
+	assert.False(t, (&auth.Principal{Subject: "ruan"}).HasRole(auth.RoleAdmin))
+}
+
+func TestPrincipalFrom(t *testing.T) {
+	_, ok := auth.PrincipalFrom(context.Background())
+	assert.False(t, ok)
+
+	p := &auth.Principal{Subject: "ruan"}
+	found, ok := auth.PrincipalFrom(auth.WithPrincipal(context.Background(), p))
+	assert.True(t, ok)
+	assert.Equal(t, found, p)
+}
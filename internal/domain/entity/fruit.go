@@ -1,22 +1,30 @@
 package entity
 
 import (
-	"errors"
+	"fmt"
 	"regexp"
 	"time"
 
 	"github.com/google/uuid"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 )
 
 type Fruit struct {
-	ID        string    `json:"id"`
-	CreatedAt time.Time `json:"createdAt"`
-	UpdatedAt time.Time `json:"updatedAt"`
-	Name      string    `json:"name"`
-	Quantity  int       `json:"quantity"`
-	Price     float64   `json:"price"`
-	Owner     string    `json:"owner"`
-	Status    string    `json:"status"`
+	ID             string      `json:"id"`
+	Tenant         string      `json:"tenant"` // store the fruit belongs to, fruits of different tenants never mix
+	CreatedAt      time.Time   `json:"createdAt"`
+	UpdatedAt      time.Time   `json:"updatedAt"`
+	Name           string      `json:"name"`
+	Quantity       int         `json:"quantity"`
+	Price          float64     `json:"price"`
+	Owner          string      `json:"owner"`
+	Status         FruitStatus `json:"status"`
+	PreviousStatus FruitStatus `json:"previousStatus,omitempty"`
+	RestoredBy     string      `json:"restoredBy,omitempty"`
+	RestoredAt     *time.Time  `json:"restoredAt,omitempty"`
+	DeletedAt      *time.Time  `json:"deletedAt,omitempty"`
+	Version        int         `json:"version"` // bumped by the repository on every save, zero means never saved
 }
 
 func NewFruit(name string, owner string, quantity int, price float64) (*Fruit, error) {
@@ -28,13 +36,14 @@ func NewFruit(name string, owner string, quantity int, price float64) (*Fruit, e
 
 	fruit := &Fruit{
 		ID:        id,
+		Tenant:    tenant.Default,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 		Name:      name,
 		Owner:     owner,
 		Quantity:  quantity,
 		Price:     price,
-		Status:    "comestible",
+		Status:    StatusComestible,
 	}
 
 	err := fruit.Validate()
@@ -47,26 +56,88 @@ func NewFruit(name string, owner string, quantity int, price float64) (*Fruit, e
 }
 
 func (f *Fruit) Validate() error {
-	if f.Name == "" {
-		return errors.New("name is required")
-	}
+	var violations domainerror.Violations
 
-	valid, err := regexp.MatchString("^[a-zA-Z]+$", f.Name)
-	if err != nil || !valid {
-		return errors.New("name cannot contain numbers or special characters")
+	if f.Name == "" {
+		violations.Add("name", "name is required")
+	} else if valid, err := regexp.MatchString("^[a-zA-Z]+$", f.Name); err != nil || !valid {
+		violations.Add("name", "name cannot contain numbers or special characters")
 	}
 
 	if f.Owner == "" {
-		return errors.New("owner is required")
+		violations.Add("owner", "owner is required")
 	}
 
 	if f.Quantity <= 0 {
-		return errors.New("quantity must be greater than zero")
+		violations.Add("quantity", "quantity must be greater than zero")
 	}
 
 	if f.Price <= 0 {
-		return errors.New("price must be greater than zero")
+		violations.Add("price", "price must be greater than zero")
+	}
+
+	return violations.Err()
+}
+
+// TransitionTo move the fruit to status, refusing moves outside the lifecycle transition table
+func (f *Fruit) TransitionTo(status FruitStatus) error {
+	if !f.Status.CanTransitionTo(status) {
+		return domainerror.NewConflictError(fmt.Sprintf("fruit cannot go from %s to %s", f.Status, status))
+	}
+
+	now := time.Now()
+	f.PreviousStatus = f.Status
+	f.Status = status
+	f.UpdatedAt = now
+
+	if status == StatusPodrido {
+		f.DeletedAt = &now
 	}
 
 	return nil
 }
+
+// Restore undo a soft delete, putting the fruit back in the status it had before going podrido
+func (f *Fruit) Restore(restoredBy string) error {
+	switch f.Status {
+	case StatusPodrido:
+	case StatusDiscarded:
+		return domainerror.NewConflictError("fruit has been discarded and cannot be restored")
+	default:
+		return domainerror.NewConflictError(fmt.Sprintf("fruit with status %s is not deleted", f.Status))
+	}
+
+	status := f.PreviousStatus
+	if !status.IsEditable() {
+		status = StatusComestible
+	}
+
+	now := time.Now()
+	f.PreviousStatus = f.Status
+	f.Status = status
+	f.RestoredBy = restoredBy
+	f.RestoredAt = &now
+	f.DeletedAt = nil
+	f.UpdatedAt = now
+
+	return nil
+}
+
+func (f *Fruit) Update(quantity int, price float64) error {
+	return f.Patch(f.Name, f.Owner, quantity, price)
+}
+
+// Patch replace every editable field at once, validating the fruit they make
+func (f *Fruit) Patch(name string, owner string, quantity int, price float64) error {
+	if !f.Status.IsEditable() {
+		return domainerror.NewConflictError(fmt.Sprintf("fruit with status %s cannot be updated", f.Status))
+	}
+
+	f.Name = name
+	f.Owner = owner
+	f.Quantity = quantity
+	f.Price = price
+	f.UpdatedAt = time.Now()
+
+	return f.Validate()
+}
@@ -0,0 +1,80 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+type FruitStatus string
+
+const (
+	StatusComestible FruitStatus = "comestible"
+	StatusReserved   FruitStatus = "reserved"
+	StatusSold       FruitStatus = "sold"
+	StatusPodrido    FruitStatus = "podrido"
+	StatusDiscarded  FruitStatus = "discarded"
+)
+
+var FruitStatuses = []FruitStatus{
+	StatusComestible,
+	StatusReserved,
+	StatusSold,
+	StatusPodrido,
+	StatusDiscarded,
+}
+
+// fruitStatusTransitions the allowed moves of the fruit lifecycle, sold and discarded are final states
+var fruitStatusTransitions = map[FruitStatus][]FruitStatus{
+	StatusComestible: {StatusReserved, StatusSold, StatusPodrido},
+	StatusReserved:   {StatusComestible, StatusSold, StatusPodrido},
+	StatusSold:       {},
+	StatusPodrido:    {StatusDiscarded},
+	StatusDiscarded:  {},
+}
+
+func ParseFruitStatus(s string) (FruitStatus, error) {
+	status := FruitStatus(s)
+	if !status.IsValid() {
+		var violations domainerror.Violations
+		violations.Add("status", fmt.Sprintf("status must be one of %s", joinStatuses(FruitStatuses)))
+		return "", violations.Err()
+	}
+
+	return status, nil
+}
+
+func (s FruitStatus) IsValid() bool {
+	_, ok := fruitStatusTransitions[s]
+	return ok
+}
+
+func (s FruitStatus) CanTransitionTo(next FruitStatus) bool {
+	for _, allowed := range fruitStatusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+
+	return false
+}
+
+// IsEditable tell if quantity and price can still be changed, only fruits on the shelf can
+func (s FruitStatus) IsEditable() bool {
+	return s == StatusComestible || s == StatusReserved
+}
+
+// IsDeleted tell if the fruit was soft deleted, those are the only ones that can be purged
+func (s FruitStatus) IsDeleted() bool {
+	return s == StatusPodrido || s == StatusDiscarded
+}
+
+func joinStatuses(statuses []FruitStatus) string {
+	names := make([]string, len(statuses))
+	for i, status := range statuses {
+		names[i] = string(status)
+	}
+
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,54 @@
+package entity_test
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseFruitStatus(t *testing.T) {
+	status, err := entity.ParseFruitStatus("reserved")
+	assert.Nil(t, err)
+	assert.Equal(t, status, entity.StatusReserved)
+
+	status, err = entity.ParseFruitStatus("eaten")
+	assert.Equal(t, status, entity.FruitStatus(""))
+	assert.EqualError(t, err, "status must be one of comestible, reserved, sold, podrido, discarded")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+}
+
+func TestFruitStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, entity.StatusComestible.CanTransitionTo(entity.StatusReserved))
+	assert.True(t, entity.StatusComestible.CanTransitionTo(entity.StatusSold))
+	assert.True(t, entity.StatusComestible.CanTransitionTo(entity.StatusPodrido))
+	assert.False(t, entity.StatusComestible.CanTransitionTo(entity.StatusDiscarded))
+	assert.False(t, entity.StatusComestible.CanTransitionTo(entity.StatusComestible))
+
+	assert.True(t, entity.StatusReserved.CanTransitionTo(entity.StatusComestible))
+	assert.True(t, entity.StatusReserved.CanTransitionTo(entity.StatusSold))
+
+	assert.True(t, entity.StatusPodrido.CanTransitionTo(entity.StatusDiscarded))
+	assert.False(t, entity.StatusPodrido.CanTransitionTo(entity.StatusComestible))
+
+	for _, status := range entity.FruitStatuses {
+		assert.False(t, entity.StatusSold.CanTransitionTo(status))
+		assert.False(t, entity.StatusDiscarded.CanTransitionTo(status))
+	}
+}
+
+func TestFruitStatus_IsEditable(t *testing.T) {
+	assert.True(t, entity.StatusComestible.IsEditable())
+	assert.True(t, entity.StatusReserved.IsEditable())
+	assert.False(t, entity.StatusSold.IsEditable())
+	assert.False(t, entity.StatusPodrido.IsEditable())
+	assert.False(t, entity.StatusDiscarded.IsEditable())
+}
+
+func TestFruitStatus_IsDeleted(t *testing.T) {
+	assert.False(t, entity.StatusComestible.IsDeleted())
+	assert.False(t, entity.StatusReserved.IsDeleted())
+	assert.False(t, entity.StatusSold.IsDeleted())
+	assert.True(t, entity.StatusPodrido.IsDeleted())
+	assert.True(t, entity.StatusDiscarded.IsDeleted())
+}
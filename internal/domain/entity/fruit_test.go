@@ -2,6 +2,7 @@ package entity_test
 
 import (
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/stretchr/testify/assert"
 	"testing"
 )
@@ -28,6 +29,20 @@ func TestNewFruit(t *testing.T) {
 		fruit, err = entity.NewFruit("name", "owner", 1, 0)
 		assert.Nil(t, fruit)
 		assert.Error(t, err, "price must be greater than zero")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+	})
+
+	t.Run("Report every invalid field at once", func(t *testing.T) {
+		fruit, err := entity.NewFruit("", "", 0, 0)
+		assert.Nil(t, fruit)
+		assert.EqualError(t, err, "name is required; owner is required; quantity must be greater than zero; price must be greater than zero")
+
+		violations := domainerror.ViolationsOf(err)
+		assert.Len(t, violations, 4)
+		assert.Equal(t, violations[0].Field, "name")
+		assert.Equal(t, violations[1].Field, "owner")
+		assert.Equal(t, violations[2].Field, "quantity")
+		assert.Equal(t, violations[3].Field, "price")
 	})
 
 	t.Run("With valid params", func(t *testing.T) {
@@ -41,6 +56,124 @@ func TestNewFruit(t *testing.T) {
 		assert.Equal(t, fruit.Owner, "Owner")
 		assert.Equal(t, fruit.Quantity, 1)
 		assert.Equal(t, fruit.Price, 10.0)
-		assert.Equal(t, fruit.Status, "comestible")
+		assert.Equal(t, fruit.Status, entity.StatusComestible)
+	})
+}
+
+func TestFruit_TransitionTo(t *testing.T) {
+	t.Run("With allowed transition", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+		updatedAt := fruit.UpdatedAt
+
+		err = fruit.TransitionTo(entity.StatusReserved)
+		assert.Nil(t, err)
+		assert.Equal(t, fruit.Status, entity.StatusReserved)
+		assert.Equal(t, fruit.PreviousStatus, entity.StatusComestible)
+		assert.False(t, fruit.UpdatedAt.Equal(updatedAt))
+		assert.Nil(t, fruit.DeletedAt)
+	})
+
+	t.Run("When going podrido", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+
+		err = fruit.TransitionTo(entity.StatusPodrido)
+		assert.Nil(t, err)
+		assert.NotNil(t, fruit.DeletedAt)
+		assert.True(t, fruit.DeletedAt.Equal(fruit.UpdatedAt))
+	})
+
+	t.Run("With illegal transition", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+
+		err = fruit.TransitionTo(entity.StatusDiscarded)
+		assert.EqualError(t, err, "fruit cannot go from comestible to discarded")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		assert.Equal(t, fruit.Status, entity.StatusComestible)
+	})
+}
+
+func TestFruit_Update(t *testing.T) {
+	t.Run("With editable status", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+
+		err = fruit.Update(5, 20)
+		assert.Nil(t, err)
+		assert.Equal(t, fruit.Quantity, 5)
+		assert.Equal(t, fruit.Price, 20.0)
+	})
+
+	t.Run("With rotten fruit", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+		assert.Nil(t, fruit.TransitionTo(entity.StatusPodrido))
+
+		err = fruit.Update(5, 20)
+		assert.EqualError(t, err, "fruit with status podrido cannot be updated")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		assert.Equal(t, fruit.Quantity, 1)
+		assert.Equal(t, fruit.Price, 10.0)
+	})
+}
+
+func TestFruit_Patch(t *testing.T) {
+	fruit, err := entity.NewFruit("name", "owner", 1, 10)
+	assert.Nil(t, err)
+
+	err = fruit.Patch("pera", "clerk", 2, 5)
+	assert.Nil(t, err)
+	assert.Equal(t, fruit.Name, "pera")
+	assert.Equal(t, fruit.Owner, "clerk")
+	assert.Equal(t, fruit.Quantity, 2)
+	assert.Equal(t, fruit.Price, 5.0)
+
+	err = fruit.Patch("pera2", "", 2, 5)
+	assert.EqualError(t, err, "name cannot contain numbers or special characters; owner is required")
+}
+
+func TestFruit_Restore(t *testing.T) {
+	t.Run("With podrido fruit", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+		assert.Nil(t, fruit.TransitionTo(entity.StatusReserved))
+		assert.Nil(t, fruit.TransitionTo(entity.StatusPodrido))
+
+		err = fruit.Restore("clerk")
+		assert.Nil(t, err)
+		assert.Equal(t, fruit.Status, entity.StatusReserved)
+		assert.Equal(t, fruit.PreviousStatus, entity.StatusPodrido)
+		assert.Equal(t, fruit.RestoredBy, "clerk")
+		assert.NotNil(t, fruit.RestoredAt)
+		assert.True(t, fruit.UpdatedAt.Equal(*fruit.RestoredAt))
+		assert.Nil(t, fruit.DeletedAt)
+	})
+
+	t.Run("Without known previous status", func(t *testing.T) {
+		fruit := &entity.Fruit{Status: entity.StatusPodrido}
+
+		err := fruit.Restore("clerk")
+		assert.Nil(t, err)
+		assert.Equal(t, fruit.Status, entity.StatusComestible)
+	})
+
+	t.Run("With not deleted fruit", func(t *testing.T) {
+		fruit, err := entity.NewFruit("name", "owner", 1, 10)
+		assert.Nil(t, err)
+
+		err = fruit.Restore("clerk")
+		assert.EqualError(t, err, "fruit with status comestible is not deleted")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		assert.Nil(t, fruit.RestoredAt)
+	})
+
+	t.Run("With discarded fruit", func(t *testing.T) {
+		fruit := &entity.Fruit{Status: entity.StatusDiscarded, PreviousStatus: entity.StatusPodrido}
+
+		err := fruit.Restore("clerk")
+		assert.EqualError(t, err, "fruit has been discarded and cannot be restored")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
 	})
 }
@@ -0,0 +1,115 @@
+package error
+
+import (
+	"errors"
+	"strings"
+)
+
+type Kind string
+
+const (
+	NotFound           Kind = "not_found"
+	Validation         Kind = "validation"
+	Conflict           Kind = "conflict"
+	Internal           Kind = "internal"
+	PreconditionFailed Kind = "precondition_failed"
+	Unauthenticated    Kind = "unauthenticated"
+	Forbidden          Kind = "forbidden"
+)
+
+type DomainError struct {
+	Kind       Kind
+	Message    string
+	Err        error
+	Violations []*Violation
+}
+
+type Violation struct {
+	Field   string
+	Message string
+}
+
+// Violations collect every invalid field before failing, so callers can report all of them at once
+type Violations []*Violation
+
+func (v *Violations) Add(field string, message string) {
+	*v = append(*v, &Violation{Field: field, Message: message})
+}
+
+// Err return a validation DomainError carrying the collected violations, nil when there is none
+func (v Violations) Err() error {
+	if len(v) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(v))
+	for i, violation := range v {
+		messages[i] = violation.Message
+	}
+
+	return &DomainError{
+		Kind:       Validation,
+		Message:    strings.Join(messages, "; "),
+		Violations: v,
+	}
+}
+
+func (de *DomainError) Error() string {
+	if de.Message == "" && de.Err != nil {
+		return de.Err.Error()
+	}
+
+	return de.Message
+}
+
+func (de *DomainError) Unwrap() error {
+	return de.Err
+}
+
+func NewNotFoundError(message string) *DomainError {
+	return &DomainError{Kind: NotFound, Message: message}
+}
+
+func NewValidationError(message string) *DomainError {
+	return &DomainError{Kind: Validation, Message: message}
+}
+
+func NewConflictError(message string) *DomainError {
+	return &DomainError{Kind: Conflict, Message: message}
+}
+
+func NewPreconditionFailedError(message string) *DomainError {
+	return &DomainError{Kind: PreconditionFailed, Message: message}
+}
+
+func NewUnauthenticatedError(message string) *DomainError {
+	return &DomainError{Kind: Unauthenticated, Message: message}
+}
+
+func NewForbiddenError(message string) *DomainError {
+	return &DomainError{Kind: Forbidden, Message: message}
+}
+
+func NewInternalError(message string, err error) *DomainError {
+	return &DomainError{Kind: Internal, Message: message, Err: err}
+}
+
+// KindOf return the kind of the first DomainError in err chain, errors outside the taxonomy are Internal
+func KindOf(err error) Kind {
+	var de *DomainError
+	if errors.As(err, &de) {
+		return de.Kind
+	}
+
+	return Internal
+}
+
+// ViolationsOf return the field violations carried by err, if any
+func ViolationsOf(err error) []*Violation {
+	var de *DomainError
+	if errors.As(err, &de) {
+		return de.Violations
+	}
+
+	return nil
+}
@@ -0,0 +1,46 @@
+package error_test
+
+import (
+	"errors"
+	"fmt"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDomainError_Error(t *testing.T) {
+	err := domainerror.NewValidationError("name is required")
+	assert.EqualError(t, err, "name is required")
+
+	cause := errors.New("disk is full")
+	err = domainerror.NewInternalError("", cause)
+	assert.EqualError(t, err, "disk is full")
+	assert.True(t, errors.Is(err, cause))
+}
+
+func TestKindOf(t *testing.T) {
+	assert.Equal(t, domainerror.KindOf(domainerror.NewNotFoundError("fruit not found")), domainerror.NotFound)
+	assert.Equal(t, domainerror.KindOf(domainerror.NewValidationError("name is required")), domainerror.Validation)
+	assert.Equal(t, domainerror.KindOf(domainerror.NewConflictError("fruit already exists")), domainerror.Conflict)
+	assert.Equal(t, domainerror.KindOf(domainerror.NewPreconditionFailedError("fruit is at version 2, not 1")), domainerror.PreconditionFailed)
+	assert.Equal(t, domainerror.KindOf(domainerror.NewInternalError("fail to save fruit", nil)), domainerror.Internal)
+
+	wrapped := fmt.Errorf("get fruit: %w", domainerror.NewNotFoundError("fruit not found"))
+	assert.Equal(t, domainerror.KindOf(wrapped), domainerror.NotFound)
+
+	assert.Equal(t, domainerror.KindOf(errors.New("unknown")), domainerror.Internal)
+}
+
+func TestViolations_Err(t *testing.T) {
+	var violations domainerror.Violations
+	assert.Nil(t, violations.Err())
+
+	violations.Add("name", "name is required")
+	violations.Add("price", "price must be greater than zero")
+
+	err := violations.Err()
+	assert.EqualError(t, err, "name is required; price must be greater than zero")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+	assert.Len(t, domainerror.ViolationsOf(err), 2)
+	assert.Nil(t, domainerror.ViolationsOf(errors.New("unknown")))
+}
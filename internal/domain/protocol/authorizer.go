@@ -0,0 +1,13 @@
+package protocol
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+)
+
+// Authorizer decide what the principal carried by the context may do with fruits
+type Authorizer interface {
+	Authorize(context context.Context, action auth.Action, fruit *entity.Fruit) error
+	ReadScope(context context.Context) (string, error)
+}
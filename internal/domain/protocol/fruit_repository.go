@@ -3,11 +3,106 @@ package protocol
 import (
 	"context"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"time"
 )
 
+// FruitSortField a fruit field search results can be ordered by
+type FruitSortField string
+
+const (
+	SortByName      FruitSortField = "name"
+	SortByPrice     FruitSortField = "price"
+	SortByQuantity  FruitSortField = "quantity"
+	SortByCreatedAt FruitSortField = "createdAt"
+	SortByUpdatedAt FruitSortField = "updatedAt"
+)
+
+// FruitSortFields every field search results can be ordered by
+var FruitSortFields = []FruitSortField{SortByName, SortByPrice, SortByQuantity, SortByCreatedAt, SortByUpdatedAt}
+
+// FruitSortKey order search results by Field, names are compared case insensitively
+type FruitSortKey struct {
+	Field      FruitSortField
+	Descending bool
+}
+
+// FruitNameMatch how the search name is matched against fruit names
+type FruitNameMatch string
+
+const (
+	// MatchContains find the names holding the search name, case insensitively
+	MatchContains FruitNameMatch = "contains"
+	// MatchFuzzy find the names holding every word of the search name, ignoring accents and tolerating typos,
+	// scoring each fruit by relevance
+	MatchFuzzy FruitNameMatch = "fuzzy"
+)
+
+// FruitNameMatches every way of matching names
+var FruitNameMatches = []FruitNameMatch{MatchContains, MatchFuzzy}
+
+// FruitSearchPosition the sort values and key of the fruit a cursor stands at
+type FruitSearchPosition struct {
+	Name      string
+	Price     float64
+	Quantity  int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Tenant    string
+	ID        string
+}
+
+func PositionOf(f *entity.Fruit) FruitSearchPosition {
+	return FruitSearchPosition{
+		Name:      f.Name,
+		Price:     f.Price,
+		Quantity:  f.Quantity,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+		Tenant:    f.Tenant,
+		ID:        f.ID,
+	}
+}
+
+// FruitSearchCursor a position in the results ordered by Sort, pointing to the results after it or before it when Backward
+type FruitSearchCursor struct {
+	Sort     []FruitSortKey
+	Position FruitSearchPosition
+	Backward bool
+}
+
+// FruitCursorCodec turn cursors into opaque tokens handed to clients and back, refusing tampered tokens
+type FruitCursorCodec interface {
+	Encode(cursor *FruitSearchCursor) (string, error)
+	Decode(token string) (*FruitSearchCursor, error)
+}
+
+// FruitSearchFilter the criteria a fruit must meet to be found, zero fields don't filter anything.
+// Ranges are inclusive for prices and quantities and exclusive for instants.
 type FruitSearchFilter struct {
-	Name   string
-	Status string
+	Tenant        string
+	AllTenants    bool           // search every tenant instead of Tenant, reserved to maintenance jobs
+	Name          string         // matched against the fruit names as Match tells
+	Match         FruitNameMatch // defaults to MatchContains
+	Statuses      []entity.FruitStatus
+	Owner         string // when set, only the fruits of this owner match
+	MinPrice      *float64
+	MaxPrice      *float64
+	MinQuantity   *int
+	MaxQuantity   *int
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	DeletedBefore *time.Time
+	// Sort order the results by these keys, then by id so pages never overlap. Without keys results come in insertion order,
+	// or by decreasing relevance for MatchFuzzy.
+	Sort []FruitSortKey
+	// Cursor when set, offset is ignored and the results are the limit ones next to the cursor, still in Sort order.
+	// Sort must be the one the cursor was taken with and can't be empty.
+	Cursor *FruitSearchCursor
+	// SkipTotal leave the paging total at zero instead of counting every match, for callers paging by cursor that
+	// have no use for it
+	SkipTotal bool
 }
 
 type FruitSearchResultPaging struct {
@@ -19,10 +114,34 @@ type FruitSearchResultPaging struct {
 type FruitSearchResult struct {
 	Paging  *FruitSearchResultPaging
 	Results []*entity.Fruit
+	// HasMore whether results follow the page, or precede it when searching backward from a cursor
+	HasMore bool
+	// Scores the relevance of each result, from 0 to 1, only set by MatchFuzzy searches
+	Scores []float64
 }
 
+// FruitRepository store fruits partitioned by tenant: a fruit is only reachable through the tenant it was saved with
 type FruitRepository interface {
 	Save(context context.Context, fruit *entity.Fruit) error
-	Get(context context.Context, id string) (*entity.Fruit, error)
+	Get(context context.Context, tenant string, id string) (*entity.Fruit, error)
 	Search(context context.Context, filter *FruitSearchFilter, offset int, limit int) (*FruitSearchResult, error)
+	// Delete permanently remove a fruit, refusing with a conflict when it was saved since it was read at version
+	Delete(context context.Context, tenant string, id string, version int) error
+}
+
+// Pinger is implemented by repositories relying on an external store, telling whether it is reachable
+type Pinger interface {
+	Ping(context context.Context) error
+}
+
+// Transactor is implemented by repositories able to apply several changes atomically
+type Transactor interface {
+	// InTransaction run fn with a context whose repository calls share one transaction, committed when fn succeeds
+	// and rolled back when it fails. Calls made within a transaction join it.
+	InTransaction(context context.Context, fn func(context context.Context) error) error
+}
+
+// FruitStatusCounter is implemented by repositories able to count their fruits per status cheaply
+type FruitStatusCounter interface {
+	CountByStatus(context context.Context) (map[entity.FruitStatus]int, error)
 }
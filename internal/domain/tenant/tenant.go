@@ -0,0 +1,38 @@
+package tenant
+
+import (
+	"context"
+	"regexp"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+// Default the tenant of requests that name none, and of the fruits stored before tenants existed
+const Default = "default"
+
+var validID = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Validate accept tenant ids of up to 64 letters, digits, dashes and underscores
+func Validate(id string) error {
+	if !validID.MatchString(id) {
+		return domainerror.NewValidationError("tenant must be 1 to 64 letters, digits, dashes or underscores")
+	}
+
+	return nil
+}
+
+type contextKey struct{}
+
+// WithTenant return a copy of ctx scoped to the tenant id, every fruit read or written with it belongs to that tenant
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext return the tenant ctx is scoped to, Default when there is none
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(contextKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Default
+}
@@ -0,0 +1,24 @@
+package tenant_test
+
+import (
+	"context"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, tenant.FromContext(context.Background()), tenant.Default)
+	assert.Equal(t, tenant.FromContext(tenant.WithTenant(context.Background(), "store-1")), "store-1")
+}
+
+func TestValidate(t *testing.T) {
+	assert.Nil(t, tenant.Validate("store_1-north"))
+
+	for _, id := range []string{"", "store 1", "store/1", strings.Repeat("a", 65)} {
+		err := tenant.Validate(id)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation, id)
+	}
+}
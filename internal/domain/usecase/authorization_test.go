@@ -0,0 +1,117 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+// allowed return an authorizer letting everyone do anything, for the tests not about authorization
+func allowed() *mocks.AuthorizerMock {
+	a := &mocks.AuthorizerMock{}
+	a.On("Authorize", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	a.On("ReadScope", mock.Anything).Return("", nil)
+	return a
+}
+
+func as(subject string, roles ...string) context.Context {
+	return auth.WithPrincipal(context.Background(), &auth.Principal{Subject: subject, Roles: roles})
+}
+
+func TestUseCases_Authorization(t *testing.T) {
+	fruit, _ := entity.NewFruit("uva", "ruan", 1, 10.0)
+	fruit.Version = 1
+
+	repository := &mocks.FruitRepositoryMock{}
+	repository.On("Get", mock.Anything, tenant.Default, fruit.ID).Return(fruit, nil)
+	authorizer := auth.NewOwnerAuthorizer(false)
+
+	t.Run("Refuse updates by another owner", func(t *testing.T) {
+		u := usecase.NewUpdateFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("clerk"), &usecase.UpdateFruitUseCaseInputDTO{ID: fruit.ID, Quantity: 2, Price: 10.0})
+		assert.Nil(t, output)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Forbidden)
+		assert.EqualError(t, err, "only the owner of the fruit or an admin can update it")
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Refuse deletes by another owner", func(t *testing.T) {
+		u := usecase.NewDeleteFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("clerk"), &usecase.DeleteFruitUseCaseInputDTO{ID: fruit.ID})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "only the owner of the fruit or an admin can delete it")
+	})
+
+	t.Run("Refuse restores by another owner", func(t *testing.T) {
+		u := usecase.NewRestoreFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("clerk"), &usecase.RestoreFruitUseCaseInputDTO{ID: fruit.ID, RestoredBy: "clerk"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "only the owner of the fruit or an admin can restore it")
+	})
+
+	t.Run("Refuse purges by non admins", func(t *testing.T) {
+		u := usecase.NewPurgeFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("ruan"), &usecase.PurgeFruitUseCaseInputDTO{ID: fruit.ID})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "only admins can purge fruits")
+		repository.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Let admins update any fruit", func(t *testing.T) {
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, fruit.ID).Return(fruit, nil)
+		repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+		u := usecase.NewUpdateFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("boss", auth.RoleAdmin), &usecase.UpdateFruitUseCaseInputDTO{ID: fruit.ID, Quantity: 2, Price: 10.0})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Owner, "ruan")
+	})
+
+	t.Run("Let anyone read when reads are not scoped", func(t *testing.T) {
+		u := usecase.NewGetFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(as("clerk"), &usecase.GetFruitUseCaseInputDTO{ID: fruit.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, output.ID, fruit.ID)
+	})
+
+	t.Run("Scope reads to the caller fruits", func(t *testing.T) {
+		scoped := auth.NewOwnerAuthorizer(true)
+
+		output, err := usecase.NewGetFruitUseCase(repository, scoped).Execute(as("clerk"), &usecase.GetFruitUseCaseInputDTO{ID: fruit.ID})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit belongs to another owner")
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Search", mock.Anything, &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "uva", Statuses: []entity.FruitStatus{entity.StatusComestible}, Owner: "clerk"}, 1, 10).Return(&protocol.FruitSearchResult{Paging: &protocol.FruitSearchResultPaging{}}, nil)
+
+		_, err = usecase.NewSearchFruitUseCase(repository, scoped, &mocks.FruitCursorCodecMock{}).Execute(as("clerk"), &usecase.SearchFruitUseCaseInputDTO{Name: "uva", Statuses: []string{"comestible"}, Offset: 1, Limit: 10})
+		assert.Nil(t, err)
+		repository.AssertExpectations(t)
+
+		_, err = usecase.NewSearchFruitUseCase(repository, scoped, &mocks.FruitCursorCodecMock{}).Execute(as("clerk"), &usecase.SearchFruitUseCaseInputDTO{Owner: "ruan", Offset: 1, Limit: 10})
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Forbidden)
+		assert.EqualError(t, err, "only admins can search the fruits of other owners")
+	})
+
+	t.Run("Without principal", func(t *testing.T) {
+		u := usecase.NewGetFruitUseCase(repository, authorizer)
+
+		output, err := u.Execute(context.Background(), &usecase.GetFruitUseCaseInputDTO{ID: fruit.ID})
+		assert.Nil(t, output)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+	})
+}
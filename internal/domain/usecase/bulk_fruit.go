@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"strings"
+)
+
+// MaxBulkOperations the most operations a batch can hold
+const MaxBulkOperations = 1000
+
+// BulkOp the kind of a batch operation
+type BulkOp string
+
+const (
+	BulkCreate BulkOp = "create"
+	BulkUpdate BulkOp = "update"
+	BulkDelete BulkOp = "delete"
+)
+
+// BulkOutcome what became of a batch operation
+type BulkOutcome string
+
+const (
+	BulkSucceeded BulkOutcome = "succeeded"
+	BulkFailed    BulkOutcome = "failed"
+	// BulkRolledBack the operation succeeded, then was undone with its atomic batch
+	BulkRolledBack BulkOutcome = "rolled_back"
+	// BulkSkipped the operation never ran, an earlier one of its atomic batch failed
+	BulkSkipped BulkOutcome = "skipped"
+)
+
+// errBatchFailed abort the transaction of an atomic batch, the failure itself is reported in the batch items
+var errBatchFailed = errors.New("batch operation failed")
+
+type BulkFruitUseCase struct {
+	create     protocol.UseCase[*CreateFruitUseCaseInputDTO, *CreateFruitUseCaseOutputDTO]
+	update     protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO]
+	delete     protocol.UseCase[*DeleteFruitUseCaseInputDTO, *DeleteFruitUseCaseOutputDTO]
+	transactor protocol.Transactor
+}
+
+// BulkFruitOperation an operation of a batch, with the fields of the use case Op runs
+type BulkFruitOperation struct {
+	Op       BulkOp
+	ID       string // of the fruit to update or delete
+	Name     string
+	Owner    string
+	Quantity int
+	Price    float64
+	// ExpectedVersion when set, an update is refused unless the fruit is still at this version
+	ExpectedVersion *int
+}
+
+type BulkFruitUseCaseInputDTO struct {
+	Operations []*BulkFruitOperation
+	// Atomic apply every operation or none, only when the repository supports transactions
+	Atomic bool
+}
+
+type BulkFruitUseCaseOutputItem struct {
+	Op      BulkOp
+	Outcome BulkOutcome
+	ID      string
+	// Err why the operation failed, when it did
+	Err error
+}
+
+// BulkFruitUseCaseOutputDTO the outcome of every operation, in the order they were given
+type BulkFruitUseCaseOutputDTO struct {
+	Items     []*BulkFruitUseCaseOutputItem
+	Succeeded int
+	Failed    int
+}
+
+// NewBulkFruitUseCase run batches through the create, update and delete use cases, t being nil when the repository
+// has no transactions
+func NewBulkFruitUseCase(
+	c protocol.UseCase[*CreateFruitUseCaseInputDTO, *CreateFruitUseCaseOutputDTO],
+	u protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO],
+	d protocol.UseCase[*DeleteFruitUseCaseInputDTO, *DeleteFruitUseCaseOutputDTO],
+	t protocol.Transactor,
+) protocol.UseCase[*BulkFruitUseCaseInputDTO, *BulkFruitUseCaseOutputDTO] {
+	return &BulkFruitUseCase{
+		create:     c,
+		update:     u,
+		delete:     d,
+		transactor: t,
+	}
+}
+
+func (bf *BulkFruitUseCase) Execute(ctx context.Context, input *BulkFruitUseCaseInputDTO) (*BulkFruitUseCaseOutputDTO, error) {
+	if err := bf.validateInput(input); err != nil {
+		return nil, err
+	}
+
+	items := make([]*BulkFruitUseCaseOutputItem, len(input.Operations))
+	for i, operation := range input.Operations {
+		items[i] = &BulkFruitUseCaseOutputItem{Op: operation.Op, Outcome: BulkSkipped, ID: operation.ID}
+	}
+
+	if input.Atomic {
+		err := bf.transactor.InTransaction(ctx, func(ctx context.Context) error {
+			for i, operation := range input.Operations {
+				if !bf.run(ctx, operation, items[i]) {
+					return errBatchFailed
+				}
+			}
+			return nil
+		})
+
+		switch {
+		case errors.Is(err, errBatchFailed):
+			for _, item := range items {
+				if item.Outcome == BulkSucceeded {
+					item.Outcome = BulkRolledBack
+				}
+			}
+		case err != nil:
+			return nil, err
+		}
+	} else {
+		for i, operation := range input.Operations {
+			bf.run(ctx, operation, items[i])
+		}
+	}
+
+	output := &BulkFruitUseCaseOutputDTO{Items: items}
+	for _, item := range items {
+		if item.Outcome == BulkSucceeded {
+			output.Succeeded++
+		} else {
+			output.Failed++
+		}
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"operations": len(items), "succeeded": output.Succeeded, "failed": output.Failed, "atomic": input.Atomic}).Info("fruit batch applied")
+
+	return output, nil
+}
+
+// run apply operation through its use case, recording its outcome in item, and tell whether it succeeded
+func (bf *BulkFruitUseCase) run(ctx context.Context, operation *BulkFruitOperation, item *BulkFruitUseCaseOutputItem) bool {
+	var err error
+
+	switch operation.Op {
+	case BulkCreate:
+		var output *CreateFruitUseCaseOutputDTO
+		output, err = bf.create.Execute(ctx, &CreateFruitUseCaseInputDTO{
+			Name:     operation.Name,
+			Owner:    operation.Owner,
+			Quantity: operation.Quantity,
+			Price:    operation.Price,
+		})
+		if err == nil {
+			item.ID = output.ID
+		}
+	case BulkUpdate:
+		_, err = bf.update.Execute(ctx, &UpdateFruitUseCaseInputDTO{
+			ID:              operation.ID,
+			Quantity:        operation.Quantity,
+			Price:           operation.Price,
+			ExpectedVersion: operation.ExpectedVersion,
+		})
+	case BulkDelete:
+		_, err = bf.delete.Execute(ctx, &DeleteFruitUseCaseInputDTO{ID: operation.ID})
+	}
+
+	if err != nil {
+		logger.FromContext(ctx).WithError(err).WithFields(logrus.Fields{"op": operation.Op, "fruit_id": operation.ID}).Warn("fruit batch operation failed")
+		item.Outcome, item.Err = BulkFailed, err
+		return false
+	}
+
+	item.Outcome = BulkSucceeded
+	return true
+}
+
+func (bf *BulkFruitUseCase) validateInput(i *BulkFruitUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
+	if len(i.Operations) == 0 || len(i.Operations) > MaxBulkOperations {
+		violations.Add("operations", fmt.Sprintf("a batch must hold between 1 and %d operations", MaxBulkOperations))
+	}
+
+	ops := []string{string(BulkCreate), string(BulkUpdate), string(BulkDelete)}
+	for index, operation := range i.Operations {
+		switch operation.Op {
+		case BulkCreate, BulkUpdate, BulkDelete:
+		default:
+			violations.Add(fmt.Sprintf("operations[%d].op", index), fmt.Sprintf("op must be one of %s", strings.Join(ops, ", ")))
+		}
+	}
+
+	if i.Atomic && bf.transactor == nil {
+		violations.Add("atomic", "atomic batches need a repository with transactions")
+	}
+
+	return violations.Err()
+}
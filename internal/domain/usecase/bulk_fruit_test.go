@@ -0,0 +1,140 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+// bulkUseCase run batches through the real use cases over r
+func bulkUseCase(r protocol.FruitRepository, t protocol.Transactor) protocol.UseCase[*usecase.BulkFruitUseCaseInputDTO, *usecase.BulkFruitUseCaseOutputDTO] {
+	return usecase.NewBulkFruitUseCase(
+		usecase.NewCreateFruitUseCase(r),
+		usecase.NewUpdateFruitUseCase(r, allowed()),
+		usecase.NewDeleteFruitUseCase(r, allowed()),
+		t,
+	)
+}
+
+// bulkRepository a repository holding the fruit "valid-id", any other id being missing
+func bulkRepository(t *testing.T) *mocks.FruitRepositoryMock {
+	fruit, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	fruit.ID = "valid-id"
+
+	r := &mocks.FruitRepositoryMock{}
+	r.On("Get", mock.Anything, tenant.Default, "valid-id").Return(fruit, nil)
+	r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, domainerror.NewNotFoundError("fruit not found"))
+	r.On("Save", mock.Anything, mock.Anything).Return(nil)
+	return r
+}
+
+func bulkOperations() []*usecase.BulkFruitOperation {
+	return []*usecase.BulkFruitOperation{
+		{Op: usecase.BulkCreate, Name: "apple", Owner: "owner", Quantity: 1, Price: 2.0},
+		{Op: usecase.BulkUpdate, ID: "missing-id", Quantity: 2, Price: 3.0},
+		{Op: usecase.BulkUpdate, ID: "valid-id", Quantity: 2, Price: 3.0},
+	}
+}
+
+func outcomesOf(output *usecase.BulkFruitUseCaseOutputDTO) []usecase.BulkOutcome {
+	var outcomes []usecase.BulkOutcome
+	for _, item := range output.Items {
+		outcomes = append(outcomes, item.Outcome)
+	}
+
+	return outcomes
+}
+
+func TestNewBulkFruitUseCase(t *testing.T) {
+	u := bulkUseCase(&mocks.FruitRepositoryMock{}, nil)
+	assert.NotNil(t, u)
+}
+
+func TestBulkFruitUseCase_Execute(t *testing.T) {
+	t.Run("With invalid batch", func(t *testing.T) {
+		u := bulkUseCase(&mocks.FruitRepositoryMock{}, nil)
+
+		output, err := u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "a batch must hold between 1 and 1000 operations")
+
+		output, err = u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{
+			Operations: []*usecase.BulkFruitOperation{{Op: "upsert"}},
+			Atomic:     true,
+		})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "op must be one of create, update, delete; atomic batches need a repository with transactions")
+		assert.Equal(t, domainerror.ViolationsOf(err)[0].Field, "operations[0].op")
+	})
+
+	t.Run("Apply every operation when not atomic", func(t *testing.T) {
+		r := bulkRepository(t)
+		u := bulkUseCase(r, nil)
+
+		output, err := u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{Operations: bulkOperations()})
+
+		assert.Nil(t, err)
+		assert.Equal(t, outcomesOf(output), []usecase.BulkOutcome{usecase.BulkSucceeded, usecase.BulkFailed, usecase.BulkSucceeded})
+		assert.Equal(t, output.Succeeded, 2)
+		assert.Equal(t, output.Failed, 1)
+		assert.NotEmpty(t, output.Items[0].ID)
+		assert.EqualError(t, output.Items[1].Err, "fruit not found")
+		assert.Equal(t, output.Items[2].ID, "valid-id")
+		r.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("Stop at the first failure when atomic", func(t *testing.T) {
+		r := bulkRepository(t)
+		transactor := &mocks.TransactorMock{}
+		transactor.On("InTransaction", mock.Anything).Return(nil)
+		u := bulkUseCase(r, transactor)
+
+		output, err := u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{Operations: bulkOperations(), Atomic: true})
+
+		assert.Nil(t, err)
+		assert.Equal(t, outcomesOf(output), []usecase.BulkOutcome{usecase.BulkRolledBack, usecase.BulkFailed, usecase.BulkSkipped})
+		assert.Equal(t, output.Succeeded, 0)
+		assert.Equal(t, output.Failed, 3)
+		assert.EqualError(t, output.Items[1].Err, "fruit not found")
+		r.AssertNumberOfCalls(t, "Save", 1)
+		transactor.AssertNumberOfCalls(t, "InTransaction", 1)
+	})
+
+	t.Run("Apply every operation when atomic", func(t *testing.T) {
+		r := bulkRepository(t)
+		transactor := &mocks.TransactorMock{}
+		transactor.On("InTransaction", mock.Anything).Return(nil)
+		u := bulkUseCase(r, transactor)
+
+		operations := bulkOperations()
+		operations[1].Op, operations[1].ID = usecase.BulkDelete, "valid-id"
+
+		output, err := u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{Operations: operations[:2], Atomic: true})
+
+		assert.Nil(t, err)
+		assert.Equal(t, outcomesOf(output), []usecase.BulkOutcome{usecase.BulkSucceeded, usecase.BulkSucceeded})
+		assert.Equal(t, output.Succeeded, 2)
+		assert.Equal(t, output.Failed, 0)
+		r.AssertNumberOfCalls(t, "Save", 2)
+	})
+
+	t.Run("Fail if transaction fail", func(t *testing.T) {
+		transactor := &mocks.TransactorMock{}
+		transactor.On("InTransaction", mock.Anything).Return(errors.New("fail to begin transaction"))
+		u := bulkUseCase(&mocks.FruitRepositoryMock{}, transactor)
+
+		output, err := u.Execute(context.Background(), &usecase.BulkFruitUseCaseInputDTO{Operations: bulkOperations(), Atomic: true})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fail to begin transaction")
+	})
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
 	"time"
 )
 
@@ -42,12 +45,16 @@ func (cf *CreateFruitUseCase) Execute(ctx context.Context, i *CreateFruitUseCase
 		return nil, err
 	}
 
+	fruit.Tenant = tenant.FromContext(ctx)
+
 	err = cf.repository.Save(ctx, fruit)
 
 	if err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).WithFields(logrus.Fields{"fruit_id": fruit.ID, "owner": fruit.Owner}).Info("fruit created")
+
 	return &CreateFruitUseCaseOutputDTO{
 		ID:        fruit.ID,
 		CreatedAt: fruit.CreatedAt,
@@ -56,7 +63,7 @@ func (cf *CreateFruitUseCase) Execute(ctx context.Context, i *CreateFruitUseCase
 		Owner:     fruit.Owner,
 		Quantity:  fruit.Quantity,
 		Price:     fruit.Price,
-		Status:    fruit.Status,
+		Status:    string(fruit.Status),
 	}, nil
 
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -74,7 +76,9 @@ func TestCreateFruitUseCase_Execute(t *testing.T) {
 
 	t.Run("With Valid Params", func(t *testing.T) {
 		repository := &mocks.FruitRepositoryMock{}
-		repository.On("Save", mock.Anything, mock.Anything).Return(nil)
+		repository.On("Save", mock.Anything, mock.MatchedBy(func(f *entity.Fruit) bool {
+			return f.Tenant == "acme"
+		})).Return(nil)
 
 		u := usecase.NewCreateFruitUseCase(repository)
 
@@ -85,7 +89,7 @@ func TestCreateFruitUseCase_Execute(t *testing.T) {
 			Price:    100.0,
 		}
 
-		output, err := u.Execute(context.Background(), input)
+		output, err := u.Execute(tenant.WithTenant(context.Background(), "acme"), input)
 
 		repository.AssertNumberOfCalls(t, "Save", 1)
 
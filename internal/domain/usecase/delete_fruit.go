@@ -2,12 +2,17 @@ package usecase
 
 import (
 	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
 	"time"
 )
 
 type DeleteFruitUseCase struct {
 	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
 }
 
 type DeleteFruitUseCaseInputDTO struct {
@@ -25,28 +30,37 @@ type DeleteFruitUseCaseOutputDTO struct {
 	Status    string
 }
 
-func NewDeleteFruitUseCase(r protocol.FruitRepository) protocol.UseCase[*DeleteFruitUseCaseInputDTO, *DeleteFruitUseCaseOutputDTO] {
+func NewDeleteFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*DeleteFruitUseCaseInputDTO, *DeleteFruitUseCaseOutputDTO] {
 	return &DeleteFruitUseCase{
-		r,
+		repository: r,
+		authorizer: a,
 	}
 }
 
 func (dfu DeleteFruitUseCase) Execute(ctx context.Context, input *DeleteFruitUseCaseInputDTO) (*DeleteFruitUseCaseOutputDTO, error) {
 
-	fruit, err := dfu.repository.Get(ctx, input.ID)
+	fruit, err := dfu.repository.Get(ctx, tenant.FromContext(ctx), input.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	fruit.Status = "podrido"
-	fruit.UpdatedAt = time.Now()
+	if err := dfu.authorizer.Authorize(ctx, auth.ActionDelete, fruit); err != nil {
+		return nil, err
+	}
+
+	err = fruit.TransitionTo(entity.StatusPodrido)
+	if err != nil {
+		return nil, err
+	}
 
 	err = dfu.repository.Save(ctx, fruit)
 	if err != nil {
 		return nil, err
 	}
 
+	logger.FromContext(ctx).WithField("fruit_id", fruit.ID).Info("fruit deleted")
+
 	return &DeleteFruitUseCaseOutputDTO{
 		ID:        fruit.ID,
 		CreatedAt: fruit.CreatedAt,
@@ -55,6 +69,6 @@ func (dfu DeleteFruitUseCase) Execute(ctx context.Context, input *DeleteFruitUse
 		Owner:     fruit.Owner,
 		Quantity:  fruit.Quantity,
 		Price:     fruit.Price,
-		Status:    fruit.Status,
+		Status:    string(fruit.Status),
 	}, nil
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -14,15 +15,15 @@ import (
 
 func TestNewDeleteFruitUseCase(t *testing.T) {
 	r := &mocks.FruitRepositoryMock{}
-	u := usecase.NewDeleteFruitUseCase(r)
+	u := usecase.NewDeleteFruitUseCase(r, allowed())
 	assert.NotNil(t, u)
 }
 
 func TestDeleteFruitUseCase_Execute(t *testing.T) {
 	t.Run("With not found fruit", func(t *testing.T) {
 		r := &mocks.FruitRepositoryMock{}
-		r.On("Get", mock.Anything, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
-		u := usecase.NewDeleteFruitUseCase(r)
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
+		u := usecase.NewDeleteFruitUseCase(r, allowed())
 
 		var output, err = u.Execute(context.Background(), &usecase.DeleteFruitUseCaseInputDTO{
 			ID: "invalid-id",
@@ -32,6 +33,31 @@ func TestDeleteFruitUseCase_Execute(t *testing.T) {
 		assert.EqualError(t, err, "fruit not found")
 	})
 
+	t.Run("With already rotten fruit", func(t *testing.T) {
+		fruitMock := &entity.Fruit{
+			ID:        "valid-id",
+			Name:      "name",
+			Owner:     "owner",
+			Price:     10.0,
+			Quantity:  10,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Status:    "podrido",
+		}
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewDeleteFruitUseCase(r, allowed())
+
+		var output, err = u.Execute(context.Background(), &usecase.DeleteFruitUseCaseInputDTO{
+			ID: fruitMock.ID,
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit cannot go from podrido to podrido")
+		r.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
 	t.Run("When save method fails", func(t *testing.T) {
 		fruitMock := &entity.Fruit{
 			ID:        "valid-id",
@@ -45,9 +71,9 @@ func TestDeleteFruitUseCase_Execute(t *testing.T) {
 		}
 
 		r := &mocks.FruitRepositoryMock{}
-		r.On("Get", mock.Anything, mock.Anything).Return(fruitMock, nil)
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
 		r.On("Save", mock.Anything, mock.Anything).Return(errors.New("fail to save"))
-		u := usecase.NewDeleteFruitUseCase(r)
+		u := usecase.NewDeleteFruitUseCase(r, allowed())
 
 		var output, err = u.Execute(context.Background(), &usecase.DeleteFruitUseCaseInputDTO{
 			ID: fruitMock.ID,
@@ -72,9 +98,9 @@ func TestDeleteFruitUseCase_Execute(t *testing.T) {
 		fruitMockCopy := *fruitMock
 
 		r := &mocks.FruitRepositoryMock{}
-		r.On("Get", mock.Anything, mock.Anything).Return(&fruitMockCopy, nil)
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&fruitMockCopy, nil)
 		r.On("Save", mock.Anything, mock.Anything).Return(nil)
-		u := usecase.NewDeleteFruitUseCase(r)
+		u := usecase.NewDeleteFruitUseCase(r, allowed())
 
 		output, err := u.Execute(context.Background(), &usecase.DeleteFruitUseCaseInputDTO{
 			ID: fruitMock.ID,
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// exportPageSize the fruits read from the repository at once, an export never holds more
+const exportPageSize = 100
+
+type ExportFruitsUseCase struct {
+	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+}
+
+type ExportFruitsUseCaseInputDTO struct {
+	// Criteria the fruits to export and their order, as searched, the paging fields being ignored and the fuzzy match
+	// refused. Fruits are exported by creation date unless a sort is given.
+	Criteria *SearchFruitUseCaseInputDTO
+	// Write called with every exported fruit in order, the export stops on the first error it returns
+	Write func(fruit *SearchFruitUseCaseOutputResult) error
+}
+
+type ExportFruitsUseCaseOutputDTO struct {
+	Exported int
+}
+
+func NewExportFruitsUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*ExportFruitsUseCaseInputDTO, *ExportFruitsUseCaseOutputDTO] {
+	return &ExportFruitsUseCase{
+		repository: r,
+		authorizer: a,
+	}
+}
+
+// Execute hand every fruit matching the criteria to Write, a page at a time. Pages follow each other by cursor, so
+// fruits saved during the export don't shift them and no fruit is written twice.
+func (efu *ExportFruitsUseCase) Execute(ctx context.Context, input *ExportFruitsUseCaseInputDTO) (*ExportFruitsUseCaseOutputDTO, error) {
+	criteria := input.Criteria
+	if criteria == nil {
+		criteria = &SearchFruitUseCaseInputDTO{}
+	}
+
+	var violations domainerror.Violations
+	sort := validateCriteria(criteria, &violations)
+	// fuzzy matches are ranked all at once, so every page would score the whole inventory again
+	if protocol.FruitNameMatch(criteria.Match) == protocol.MatchFuzzy {
+		violations.Add("match", "exports only match names by contains")
+	}
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	filter, err := searchFilter(ctx, efu.authorizer, criteria, sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// cursors need an order to page through
+	if len(filter.Sort) == 0 {
+		filter.Sort = defaultCursorSort
+	}
+	filter.SkipTotal = true
+
+	output := &ExportFruitsUseCaseOutputDTO{}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		result, err := efu.repository.Search(ctx, filter, 1, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, fruit := range result.Results {
+			if err := input.Write(searchResultOf(fruit, result.Scores, i)); err != nil {
+				return nil, err
+			}
+			output.Exported++
+		}
+
+		if !result.HasMore || len(result.Results) == 0 {
+			break
+		}
+
+		last := result.Results[len(result.Results)-1]
+		filter.Cursor = &protocol.FruitSearchCursor{Sort: filter.Sort, Position: protocol.PositionOf(last)}
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"exported": output.Exported}).Info("fruits exported")
+
+	return output, nil
+}
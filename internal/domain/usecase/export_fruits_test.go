@@ -0,0 +1,125 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+// firstPage match the search of the first page, the ones after it being taken from a cursor
+func firstPage(f *protocol.FruitSearchFilter) bool {
+	return f.Cursor == nil
+}
+
+func TestNewExportFruitsUseCase(t *testing.T) {
+	u := usecase.NewExportFruitsUseCase(&mocks.FruitRepositoryMock{}, allowed())
+	assert.NotNil(t, u)
+}
+
+func TestExportFruitsUseCase_Execute(t *testing.T) {
+	banana, _ := entity.NewFruit("banana", "owner", 1, 10.0)
+	uva, _ := entity.NewFruit("uva", "owner", 2, 20.0)
+	pera, _ := entity.NewFruit("pera", "owner", 3, 30.0)
+
+	t.Run("With invalid criteria", func(t *testing.T) {
+		u := usecase.NewExportFruitsUseCase(&mocks.FruitRepositoryMock{}, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{Statuses: []string{"ripe"}, Sort: "color"},
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "status must be one of comestible, reserved, sold, podrido, discarded; sort field must be one of name, price, quantity, createdAt, updatedAt")
+	})
+
+	t.Run("With fuzzy match", func(t *testing.T) {
+		u := usecase.NewExportFruitsUseCase(&mocks.FruitRepositoryMock{}, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{Name: "banana", Match: "fuzzy"},
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "exports only match names by contains")
+	})
+
+	t.Run("Write every page in order", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.MatchedBy(firstPage), 1, 100).Return(&protocol.FruitSearchResult{
+			Paging:  &protocol.FruitSearchResultPaging{Total: 3, Offset: 1, Limit: 100},
+			Results: []*entity.Fruit{banana, uva},
+			HasMore: true,
+		}, nil).Once()
+		r.On("Search", mock.Anything, mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool {
+			return f.Cursor != nil && f.Cursor.Position.ID == uva.ID && !f.Cursor.Backward
+		}), 1, 100).Return(searchResultOf(pera), nil).Once()
+		u := usecase.NewExportFruitsUseCase(r, allowed())
+
+		var written []string
+		output, err := u.Execute(context.Background(), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{Name: "a", Offset: -1, Limit: 1000},
+			Write: func(fruit *usecase.SearchFruitUseCaseOutputResult) error {
+				written = append(written, fruit.Name)
+				return nil
+			},
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, output.Exported, 3)
+		assert.Equal(t, written, []string{"banana", "uva", "pera"})
+
+		r.AssertNumberOfCalls(t, "Search", 2)
+		filter := r.Calls[0].Arguments.Get(1).(*protocol.FruitSearchFilter)
+		assert.Equal(t, filter.Name, "a")
+		assert.Equal(t, filter.Sort, []protocol.FruitSortKey{{Field: protocol.SortByCreatedAt}})
+		assert.True(t, filter.SkipTotal)
+	})
+
+	t.Run("Stop when write fails", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.Anything, 1, 100).Return(&protocol.FruitSearchResult{
+			Paging:  &protocol.FruitSearchResultPaging{Total: 3, Offset: 1, Limit: 100},
+			Results: []*entity.Fruit{banana, uva},
+			HasMore: true,
+		}, nil)
+		u := usecase.NewExportFruitsUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{Sort: "-price"},
+			Write: func(fruit *usecase.SearchFruitUseCaseOutputResult) error {
+				return errors.New("connection reset")
+			},
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "connection reset")
+		r.AssertNumberOfCalls(t, "Search", 1)
+		filter := r.Calls[0].Arguments.Get(1).(*protocol.FruitSearchFilter)
+		assert.Equal(t, filter.Sort, []protocol.FruitSortKey{{Field: protocol.SortByPrice, Descending: true}})
+	})
+
+	t.Run("Only export the caller fruits when reads are scoped", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool { return f.Owner == "clerk" }), 1, 100).Return(searchResultOf(), nil)
+		u := usecase.NewExportFruitsUseCase(r, auth.NewOwnerAuthorizer(true))
+
+		output, err := u.Execute(as("clerk"), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{},
+			Write:    func(*usecase.SearchFruitUseCaseOutputResult) error { return nil },
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Exported, 0)
+
+		_, err = u.Execute(as("clerk"), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: &usecase.SearchFruitUseCaseInputDTO{Owner: "ruan"},
+		})
+		assert.EqualError(t, err, "only admins can search the fruits of other owners")
+	})
+}
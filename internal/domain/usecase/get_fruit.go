@@ -2,12 +2,15 @@ package usecase
 
 import (
 	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"time"
 )
 
 type GetFruitUseCase struct {
 	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
 }
 
 type GetFruitUseCaseInputDTO struct {
@@ -23,22 +26,28 @@ type GetFruitUseCaseOutputDTO struct {
 	Quantity  int
 	Price     float64
 	Status    string
+	Version   int
 }
 
-func NewGetFruitUseCase(r protocol.FruitRepository) protocol.UseCase[*GetFruitUseCaseInputDTO, *GetFruitUseCaseOutputDTO] {
+func NewGetFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*GetFruitUseCaseInputDTO, *GetFruitUseCaseOutputDTO] {
 	return &GetFruitUseCase{
-		r,
+		repository: r,
+		authorizer: a,
 	}
 }
 
 func (g GetFruitUseCase) Execute(ctx context.Context, input *GetFruitUseCaseInputDTO) (*GetFruitUseCaseOutputDTO, error) {
 
-	fruit, err := g.repository.Get(ctx, input.ID)
+	fruit, err := g.repository.Get(ctx, tenant.FromContext(ctx), input.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := g.authorizer.Authorize(ctx, auth.ActionRead, fruit); err != nil {
+		return nil, err
+	}
+
 	return &GetFruitUseCaseOutputDTO{
 		ID:        fruit.ID,
 		CreatedAt: fruit.CreatedAt,
@@ -47,6 +56,7 @@ func (g GetFruitUseCase) Execute(ctx context.Context, input *GetFruitUseCaseInpu
 		Owner:     fruit.Owner,
 		Quantity:  fruit.Quantity,
 		Price:     fruit.Price,
-		Status:    fruit.Status,
+		Status:    string(fruit.Status),
+		Version:   fruit.Version,
 	}, nil
 }
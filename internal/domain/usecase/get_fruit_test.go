@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -14,15 +15,15 @@ import (
 
 func TestNewGetFruitUseCase(t *testing.T) {
 	r := &mocks.FruitRepositoryMock{}
-	u := usecase.NewGetFruitUseCase(r)
+	u := usecase.NewGetFruitUseCase(r, allowed())
 	assert.NotNil(t, u)
 }
 
 func TestGetFruitUseCase_Execute(t *testing.T) {
 	t.Run("With not found fruit", func(t *testing.T) {
 		r := &mocks.FruitRepositoryMock{}
-		r.On("Get", mock.Anything, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
-		u := usecase.NewGetFruitUseCase(r)
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
+		u := usecase.NewGetFruitUseCase(r, allowed())
 
 		output, err := u.Execute(context.Background(), &usecase.GetFruitUseCaseInputDTO{
 			ID: "invalid-id",
@@ -45,8 +46,8 @@ func TestGetFruitUseCase_Execute(t *testing.T) {
 		}
 
 		r := &mocks.FruitRepositoryMock{}
-		r.On("Get", mock.Anything, mock.Anything).Return(fruitMock, nil)
-		u := usecase.NewGetFruitUseCase(r)
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewGetFruitUseCase(r, allowed())
 
 		output, err := u.Execute(context.Background(), &usecase.GetFruitUseCaseInputDTO{
 			ID: fruitMock.ID,
@@ -60,7 +61,7 @@ func TestGetFruitUseCase_Execute(t *testing.T) {
 		assert.Equal(t, output.Owner, fruitMock.Owner)
 		assert.Equal(t, output.Quantity, fruitMock.Quantity)
 		assert.Equal(t, output.Price, fruitMock.Price)
-		assert.Equal(t, output.Status, fruitMock.Status)
+		assert.Equal(t, output.Status, string(fruitMock.Status))
 	})
 
 }
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxImportRows the most rows an import can hold
+const MaxImportRows = 10000
+
+const importLookupPageSize = 100
+
+// ImportMode what an import does with the rows naming a fruit the owner already has
+type ImportMode string
+
+const (
+	// ImportCreate create a fruit for every row, even when the owner has one with the same name
+	ImportCreate ImportMode = "create"
+	// ImportUpsert update the quantity and price of the owner fruit with the same name, creating it when there is none
+	ImportUpsert ImportMode = "upsert"
+)
+
+// ImportAction what an import did with a row, or would do in a dry run
+type ImportAction string
+
+const (
+	ImportCreated ImportAction = "created"
+	ImportUpdated ImportAction = "updated"
+	ImportFailed  ImportAction = "failed"
+)
+
+type ImportFruitsUseCase struct {
+	repository protocol.FruitRepository
+	create     protocol.UseCase[*CreateFruitUseCaseInputDTO, *CreateFruitUseCaseOutputDTO]
+	update     protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO]
+}
+
+// ImportFruitRow a row of an import file, its values as written in the file
+type ImportFruitRow struct {
+	Line     int // in the file, counting the header
+	Name     string
+	Quantity string
+	Price    string
+}
+
+type ImportFruitsUseCaseInputDTO struct {
+	Rows  []*ImportFruitRow
+	Owner string // of the imported fruits
+	Mode  ImportMode
+	// DryRun validate the rows and tell what would be done with them, without changing any fruit
+	DryRun bool
+}
+
+type ImportFruitsUseCaseOutputRow struct {
+	Line   int
+	Action ImportAction
+	ID     string // of the fruit created or updated, empty for fruits a dry run would create
+	// Err why the row was refused, when it was
+	Err error
+}
+
+// ImportFruitsUseCaseOutputDTO the outcome of every row, in the order they were given
+type ImportFruitsUseCaseOutputDTO struct {
+	Rows    []*ImportFruitsUseCaseOutputRow
+	Created int
+	Updated int
+	Failed  int
+	DryRun  bool
+}
+
+// NewImportFruitsUseCase import rows through the create and update use cases, r being used to find the fruits
+// upserted rows name
+func NewImportFruitsUseCase(
+	r protocol.FruitRepository,
+	c protocol.UseCase[*CreateFruitUseCaseInputDTO, *CreateFruitUseCaseOutputDTO],
+	u protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO],
+) protocol.UseCase[*ImportFruitsUseCaseInputDTO, *ImportFruitsUseCaseOutputDTO] {
+	return &ImportFruitsUseCase{
+		repository: r,
+		create:     c,
+		update:     u,
+	}
+}
+
+// Execute import every valid row, a refused row doesn't stop the others
+func (ifu *ImportFruitsUseCase) Execute(ctx context.Context, input *ImportFruitsUseCaseInputDTO) (*ImportFruitsUseCaseOutputDTO, error) {
+	if err := ifu.validateInput(input); err != nil {
+		return nil, err
+	}
+
+	output := &ImportFruitsUseCaseOutputDTO{DryRun: input.DryRun}
+	// lines of the names already upserted, a fruit is only upserted once per import
+	upserted := map[string]int{}
+
+	for _, row := range input.Rows {
+		result := &ImportFruitsUseCaseOutputRow{Line: row.Line}
+		output.Rows = append(output.Rows, result)
+
+		err := ifu.importRow(ctx, input, row, upserted, result)
+		if err != nil {
+			logger.FromContext(ctx).WithError(err).WithField("line", row.Line).Debug("fruit import row refused")
+			result.Action, result.Err = ImportFailed, err
+		}
+
+		switch result.Action {
+		case ImportCreated:
+			output.Created++
+		case ImportUpdated:
+			output.Updated++
+		default:
+			output.Failed++
+		}
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{
+		"owner":   input.Owner,
+		"rows":    len(input.Rows),
+		"created": output.Created,
+		"updated": output.Updated,
+		"failed":  output.Failed,
+		"dry_run": input.DryRun,
+	}).Info("fruits imported")
+
+	return output, nil
+}
+
+func (ifu *ImportFruitsUseCase) importRow(ctx context.Context, input *ImportFruitsUseCaseInputDTO, row *ImportFruitRow, upserted map[string]int, result *ImportFruitsUseCaseOutputRow) error {
+	fruit, err := parseImportRow(row, input.Owner)
+	if err != nil {
+		return err
+	}
+
+	var existing *entity.Fruit
+	if input.Mode == ImportUpsert {
+		key := strings.ToLower(fruit.Name)
+		if line, ok := upserted[key]; ok {
+			return domainerror.NewConflictError(fmt.Sprintf("%s was already imported on line %d", fruit.Name, line))
+		}
+		upserted[key] = row.Line
+
+		if existing, err = ifu.findFruit(ctx, fruit.Owner, fruit.Name); err != nil {
+			return err
+		}
+	}
+
+	if existing == nil {
+		result.Action = ImportCreated
+		if input.DryRun {
+			return nil
+		}
+
+		output, err := ifu.create.Execute(ctx, &CreateFruitUseCaseInputDTO{
+			Name:     fruit.Name,
+			Owner:    fruit.Owner,
+			Quantity: fruit.Quantity,
+			Price:    fruit.Price,
+		})
+		if err != nil {
+			return err
+		}
+
+		result.ID = output.ID
+		return nil
+	}
+
+	result.Action, result.ID = ImportUpdated, existing.ID
+	if input.DryRun {
+		return nil
+	}
+
+	_, err = ifu.update.Execute(ctx, &UpdateFruitUseCaseInputDTO{
+		ID:       existing.ID,
+		Quantity: fruit.Quantity,
+		Price:    fruit.Price,
+	})
+	return err
+}
+
+// findFruit return the fruit of owner on the shelf named name, ignoring case, nil when there is none
+func (ifu *ImportFruitsUseCase) findFruit(ctx context.Context, owner string, name string) (*entity.Fruit, error) {
+	filter := &protocol.FruitSearchFilter{
+		Tenant:   tenant.FromContext(ctx),
+		Name:     name,
+		Owner:    owner,
+		Statuses: []entity.FruitStatus{entity.StatusComestible, entity.StatusReserved},
+	}
+
+	var found []*entity.Fruit
+
+	// names are searched by substring, the exact ones are picked here
+	for page := 1; ; page++ {
+		result, err := ifu.repository.Search(ctx, filter, page, importLookupPageSize)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fruit := range result.Results {
+			if strings.EqualFold(fruit.Name, name) {
+				found = append(found, fruit)
+			}
+		}
+
+		if page*importLookupPageSize >= result.Paging.Total {
+			break
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return nil, nil
+	case 1:
+		return found[0], nil
+	default:
+		return nil, domainerror.NewConflictError(fmt.Sprintf("%d fruits are named %s, cannot tell which one to update", len(found), name))
+	}
+}
+
+// parseImportRow read the fruit row describes, refusing it unless the fruit is valid
+func parseImportRow(row *ImportFruitRow, owner string) (*entity.Fruit, error) {
+	var violations domainerror.Violations
+
+	fruit := &entity.Fruit{Name: strings.TrimSpace(row.Name), Owner: owner}
+
+	quantity, err := strconv.Atoi(strings.TrimSpace(row.Quantity))
+	if err != nil && strings.TrimSpace(row.Quantity) != "" {
+		violations.Add("quantity", "quantity must be a whole number")
+	}
+	fruit.Quantity = quantity
+
+	price, err := strconv.ParseFloat(strings.TrimSpace(row.Price), 64)
+	if (err != nil && strings.TrimSpace(row.Price) != "") || math.IsNaN(price) || math.IsInf(price, 0) {
+		violations.Add("price", "price must be a number")
+	}
+	fruit.Price = price
+
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	if err := fruit.Validate(); err != nil {
+		return nil, err
+	}
+
+	return fruit, nil
+}
+
+func (*ImportFruitsUseCase) validateInput(i *ImportFruitsUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
+	if len(i.Rows) == 0 || len(i.Rows) > MaxImportRows {
+		violations.Add("rows", fmt.Sprintf("an import must hold between 1 and %d rows", MaxImportRows))
+	}
+
+	if i.Owner == "" {
+		violations.Add("owner", "owner is required")
+	}
+
+	if i.Mode != ImportCreate && i.Mode != ImportUpsert {
+		violations.Add("mode", fmt.Sprintf("mode must be one of %s, %s", ImportCreate, ImportUpsert))
+	}
+
+	return violations.Err()
+}
@@ -0,0 +1,175 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+// importUseCase import rows through the real use cases over r
+func importUseCase(r protocol.FruitRepository) protocol.UseCase[*usecase.ImportFruitsUseCaseInputDTO, *usecase.ImportFruitsUseCaseOutputDTO] {
+	return usecase.NewImportFruitsUseCase(r, usecase.NewCreateFruitUseCase(r), usecase.NewUpdateFruitUseCase(r, allowed()))
+}
+
+func importRows() []*usecase.ImportFruitRow {
+	return []*usecase.ImportFruitRow{
+		{Line: 2, Name: " banana ", Quantity: "10", Price: "2.5"},
+		{Line: 3, Name: "uva", Quantity: "many", Price: "NaN"},
+		{Line: 4, Name: "pera", Quantity: "", Price: "1"},
+		{Line: 5, Name: "Banana", Quantity: "3", Price: "4"},
+	}
+}
+
+func actionsOf(output *usecase.ImportFruitsUseCaseOutputDTO) []usecase.ImportAction {
+	var actions []usecase.ImportAction
+	for _, row := range output.Rows {
+		actions = append(actions, row.Action)
+	}
+
+	return actions
+}
+
+func TestNewImportFruitsUseCase(t *testing.T) {
+	u := importUseCase(&mocks.FruitRepositoryMock{})
+	assert.NotNil(t, u)
+}
+
+func TestImportFruitsUseCase_Execute(t *testing.T) {
+	t.Run("With invalid input", func(t *testing.T) {
+		u := importUseCase(&mocks.FruitRepositoryMock{})
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{Mode: "merge"})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "an import must hold between 1 and 10000 rows; owner is required; mode must be one of create, upsert")
+	})
+
+	t.Run("Create the valid rows", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Save", mock.Anything, mock.Anything).Return(nil)
+		u := importUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:  importRows(),
+			Owner: "owner",
+			Mode:  usecase.ImportCreate,
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, actionsOf(output), []usecase.ImportAction{usecase.ImportCreated, usecase.ImportFailed, usecase.ImportFailed, usecase.ImportCreated})
+		assert.Equal(t, output.Created, 2)
+		assert.Equal(t, output.Failed, 2)
+		assert.NotEmpty(t, output.Rows[0].ID)
+		assert.Equal(t, output.Rows[1].Line, 3)
+		assert.EqualError(t, output.Rows[1].Err, "quantity must be a whole number; price must be a number")
+		assert.EqualError(t, output.Rows[2].Err, "quantity must be greater than zero")
+		assert.Equal(t, domainerror.KindOf(output.Rows[2].Err), domainerror.Validation)
+
+		r.AssertNumberOfCalls(t, "Save", 2)
+		saved := r.Calls[0].Arguments.Get(1).(*entity.Fruit)
+		assert.Equal(t, saved.Name, "banana")
+		assert.Equal(t, saved.Owner, "owner")
+	})
+
+	t.Run("Upsert the fruits of the owner", func(t *testing.T) {
+		existing, err := entity.NewFruit("banana", "owner", 1, 1.0)
+		assert.Nil(t, err)
+		other, err := entity.NewFruit("bananada", "owner", 1, 1.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, &protocol.FruitSearchFilter{
+			Tenant:   tenant.Default,
+			Name:     "banana",
+			Owner:    "owner",
+			Statuses: []entity.FruitStatus{entity.StatusComestible, entity.StatusReserved},
+		}, 1, 100).Return(searchResultOf(other, existing), nil)
+		r.On("Get", mock.Anything, tenant.Default, existing.ID).Return(existing, nil)
+		r.On("Save", mock.Anything, mock.Anything).Return(nil)
+		u := importUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:  importRows(),
+			Owner: "owner",
+			Mode:  usecase.ImportUpsert,
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, actionsOf(output), []usecase.ImportAction{usecase.ImportUpdated, usecase.ImportFailed, usecase.ImportFailed, usecase.ImportFailed})
+		assert.Equal(t, output.Rows[0].ID, existing.ID)
+		assert.Equal(t, existing.Quantity, 10)
+		assert.Equal(t, existing.Price, 2.5)
+		assert.EqualError(t, output.Rows[3].Err, "Banana was already imported on line 2")
+		r.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("Refuse ambiguous upserts", func(t *testing.T) {
+		first, _ := entity.NewFruit("banana", "owner", 1, 1.0)
+		second, _ := entity.NewFruit("Banana", "owner", 1, 1.0)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.Anything, 1, 100).Return(searchResultOf(first, second), nil)
+		u := importUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:  importRows()[:1],
+			Owner: "owner",
+			Mode:  usecase.ImportUpsert,
+		})
+
+		assert.Nil(t, err)
+		assert.EqualError(t, output.Rows[0].Err, "2 fruits are named banana, cannot tell which one to update")
+		assert.Equal(t, domainerror.KindOf(output.Rows[0].Err), domainerror.Conflict)
+	})
+
+	t.Run("Change nothing in a dry run", func(t *testing.T) {
+		existing, _ := entity.NewFruit("banana", "owner", 1, 1.0)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool { return f.Name == "banana" }), 1, 100).Return(searchResultOf(existing), nil)
+		r.On("Search", mock.Anything, mock.Anything, 1, 100).Return(searchResultOf(), nil)
+		u := importUseCase(r)
+
+		rows := importRows()
+		rows[3].Name = "maca"
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:   rows,
+			Owner:  "owner",
+			Mode:   usecase.ImportUpsert,
+			DryRun: true,
+		})
+
+		assert.Nil(t, err)
+		assert.True(t, output.DryRun)
+		assert.Equal(t, actionsOf(output), []usecase.ImportAction{usecase.ImportUpdated, usecase.ImportFailed, usecase.ImportFailed, usecase.ImportCreated})
+		assert.Equal(t, output.Rows[0].ID, existing.ID)
+		assert.Empty(t, output.Rows[3].ID)
+		assert.Equal(t, existing.Quantity, 1)
+		r.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Report repository failures on the row", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Save", mock.Anything, mock.Anything).Return(errors.New("repository save fail"))
+		u := importUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:  importRows()[:1],
+			Owner: "owner",
+			Mode:  usecase.ImportCreate,
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, output.Failed, 1)
+		assert.EqualError(t, output.Rows[0].Err, "repository save fail")
+	})
+}
@@ -0,0 +1,264 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"sort"
+	"time"
+)
+
+// PatchFormat the media type a patch is written in
+type PatchFormat string
+
+const (
+	// MergePatch a RFC 7396 JSON Merge Patch, the fields to set, null removing them
+	MergePatch PatchFormat = "application/merge-patch+json"
+	// JSONPatch a RFC 6902 JSON Patch, a list of operations applied in order
+	JSONPatch PatchFormat = "application/json-patch+json"
+)
+
+type PatchFruitUseCase struct {
+	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+}
+
+type PatchFruitUseCaseInputDTO struct {
+	ID     string
+	Format PatchFormat
+	// Patch applied to the fruit document, the fruit as the api serves it
+	Patch []byte
+	// ExpectedVersion when set, the patch is refused unless the fruit is still at this version
+	ExpectedVersion *int
+}
+
+type PatchFruitUseCaseOutputDTO struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Owner     string
+	Quantity  int
+	Price     float64
+	Status    string
+	Version   int
+}
+
+// fruitDocument the fruit patches apply to, with the keys the api serves fruits with
+type fruitDocument struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"date_created"`
+	UpdatedAt time.Time `json:"date_last_updated"`
+	Name      string    `json:"name"`
+	Quantity  int       `json:"quantity"`
+	Price     float64   `json:"price"`
+	Owner     string    `json:"owner"`
+	Status    string    `json:"status"`
+}
+
+func NewPatchFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*PatchFruitUseCaseInputDTO, *PatchFruitUseCaseOutputDTO] {
+	return &PatchFruitUseCase{
+		repository: r,
+		authorizer: a,
+	}
+}
+
+func (pf *PatchFruitUseCase) Execute(ctx context.Context, i *PatchFruitUseCaseInputDTO) (*PatchFruitUseCaseOutputDTO, error) {
+	err := pf.validateInput(i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fruit, err := pf.repository.Get(ctx, tenant.FromContext(ctx), i.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pf.authorizer.Authorize(ctx, auth.ActionUpdate, fruit); err != nil {
+		return nil, err
+	}
+
+	if i.ExpectedVersion != nil && *i.ExpectedVersion != fruit.Version {
+		return nil, domainerror.NewPreconditionFailedError(fmt.Sprintf("fruit is at version %d, not %d", fruit.Version, *i.ExpectedVersion))
+	}
+
+	patched, err := applyPatch(fruit, i.Format, i.Patch)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if patched.Owner != fruit.Owner {
+		if err := pf.authorizer.Authorize(ctx, auth.ActionTransfer, fruit); err != nil {
+			return nil, err
+		}
+	}
+
+	err = fruit.Patch(patched.Name, patched.Owner, patched.Quantity, patched.Price)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = pf.repository.Save(ctx, fruit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"fruit_id": fruit.ID, "version": fruit.Version}).Info("fruit patched")
+
+	return &PatchFruitUseCaseOutputDTO{
+		ID:        fruit.ID,
+		CreatedAt: fruit.CreatedAt,
+		UpdatedAt: fruit.UpdatedAt,
+		Name:      fruit.Name,
+		Owner:     fruit.Owner,
+		Quantity:  fruit.Quantity,
+		Price:     fruit.Price,
+		Status:    string(fruit.Status),
+		Version:   fruit.Version,
+	}, nil
+}
+
+func (*PatchFruitUseCase) validateInput(i *PatchFruitUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
+	if i.ID == "" {
+		violations.Add("id", "id is required")
+	}
+
+	if i.Format != MergePatch && i.Format != JSONPatch {
+		violations.Add("patch", fmt.Sprintf("patch must be a %s or a %s document", MergePatch, JSONPatch))
+	}
+
+	if len(bytes.TrimSpace(i.Patch)) == 0 {
+		violations.Add("patch", "patch is required")
+	}
+
+	return violations.Err()
+}
+
+// applyPatch return the document of fruit once patched, refusing patches that change the fields only the server sets
+func applyPatch(fruit *entity.Fruit, format PatchFormat, patch []byte) (*fruitDocument, error) {
+	original := &fruitDocument{
+		ID:        fruit.ID,
+		CreatedAt: fruit.CreatedAt,
+		UpdatedAt: fruit.UpdatedAt,
+		Name:      fruit.Name,
+		Quantity:  fruit.Quantity,
+		Price:     fruit.Price,
+		Owner:     fruit.Owner,
+		Status:    string(fruit.Status),
+	}
+
+	document, err := json.Marshal(original)
+	if err != nil {
+		return nil, domainerror.NewInternalError("fail to patch fruit", err)
+	}
+
+	switch format {
+	case MergePatch:
+		document, err = jsonpatch.MergePatch(document, patch)
+	case JSONPatch:
+		var operations jsonpatch.Patch
+		if operations, err = jsonpatch.DecodePatch(patch); err == nil {
+			document, err = operations.Apply(document)
+		}
+	}
+
+	var violations domainerror.Violations
+
+	switch {
+	case errors.Is(err, jsonpatch.ErrTestFailed):
+		return nil, domainerror.NewConflictError("patch test operation failed")
+	case err != nil:
+		violations.Add("patch", fmt.Sprintf("patch cannot be applied: %s", err))
+		return nil, violations.Err()
+	}
+
+	patched, err := decodeFruitDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
+	if patched.ID != original.ID {
+		violations.Add("id", "id cannot be changed")
+	}
+
+	if !patched.CreatedAt.Equal(original.CreatedAt) {
+		violations.Add("date_created", "date_created cannot be changed")
+	}
+
+	if !patched.UpdatedAt.Equal(original.UpdatedAt) {
+		violations.Add("date_last_updated", "date_last_updated cannot be changed")
+	}
+
+	if patched.Status != original.Status {
+		violations.Add("status", "status cannot be changed, transition the fruit instead")
+	}
+
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	return patched, nil
+}
+
+// decodeFruitDocument read a patched document, refusing the fields fruits don't have and the values of the wrong type
+func decodeFruitDocument(document []byte) (*fruitDocument, error) {
+	var violations domainerror.Violations
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(document, &fields); err != nil {
+		violations.Add("patch", "patched fruit must be an object")
+		return nil, violations.Err()
+	}
+
+	var patched fruitDocument
+	targets := map[string]interface{}{
+		"id":                &patched.ID,
+		"date_created":      &patched.CreatedAt,
+		"date_last_updated": &patched.UpdatedAt,
+		"name":              &patched.Name,
+		"quantity":          &patched.Quantity,
+		"price":             &patched.Price,
+		"owner":             &patched.Owner,
+		"status":            &patched.Status,
+	}
+
+	names := make([]string, 0, len(fields))
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	// removed fields stay zero, so the fruit validation reports the required ones
+	for _, field := range names {
+		target, ok := targets[field]
+		switch {
+		case !ok:
+			violations.Add(field, fmt.Sprintf("%s is not a fruit field", field))
+		case json.Unmarshal(fields[field], target) != nil:
+			violations.Add(field, fmt.Sprintf("%s has the wrong type", field))
+		}
+	}
+
+	if err := violations.Err(); err != nil {
+		return nil, err
+	}
+
+	return &patched, nil
+}
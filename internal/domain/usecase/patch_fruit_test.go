@@ -0,0 +1,142 @@
+package usecase_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestNewPatchFruitUseCase(t *testing.T) {
+	repository := &mocks.FruitRepositoryMock{}
+	u := usecase.NewPatchFruitUseCase(repository, allowed())
+	assert.NotNil(t, u)
+}
+
+func TestPatchFruitUseCase_Execute(t *testing.T) {
+	// patch a fresh fruit of ruan, as ctx
+	patch := func(ctx context.Context, format usecase.PatchFormat, body string) (*entity.Fruit, *usecase.PatchFruitUseCaseOutputDTO, error) {
+		fruit, err := entity.NewFruit("uva", "ruan", 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Version = 1
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, fruit.ID).Return(fruit, nil)
+		repository.On("Save", mock.Anything, fruit).Return(nil)
+
+		output, err := usecase.NewPatchFruitUseCase(repository, auth.NewOwnerAuthorizer(false)).Execute(ctx, &usecase.PatchFruitUseCaseInputDTO{ID: fruit.ID, Format: format, Patch: []byte(body)})
+		return fruit, output, err
+	}
+
+	t.Run("With invalid input", func(t *testing.T) {
+		u := usecase.NewPatchFruitUseCase(&mocks.FruitRepositoryMock{}, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PatchFruitUseCaseInputDTO{Format: "application/json"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id is required; patch must be a application/merge-patch+json or a application/json-patch+json document; patch is required")
+	})
+
+	t.Run("With merge patch", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.MergePatch, `{"name": "pera", "price": 12.5}`)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Name, "pera")
+		assert.Equal(t, output.Price, 12.5)
+		assert.Equal(t, output.Quantity, 1)
+		assert.Equal(t, output.Owner, "ruan")
+	})
+
+	t.Run("With json patch", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.JSONPatch, `[{"op": "test", "path": "/quantity", "value": 1}, {"op": "replace", "path": "/quantity", "value": 3}, {"op": "copy", "from": "/quantity", "path": "/price"}]`)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Quantity, 3)
+		assert.Equal(t, output.Price, 3.0)
+	})
+
+	t.Run("Validate the patched fruit", func(t *testing.T) {
+		fruit, output, err := patch(as("ruan"), usecase.MergePatch, `{"name": null, "quantity": 0}`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "name is required; quantity must be greater than zero")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+		assert.Equal(t, fruit.Version, 1)
+	})
+
+	t.Run("Refuse changes to immutable fields", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.MergePatch, `{"id": "other", "date_created": "2022-12-01T00:00:00Z", "date_last_updated": null, "status": "sold"}`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id cannot be changed; date_created cannot be changed; date_last_updated cannot be changed; status cannot be changed, transition the fruit instead")
+
+		_, output, err = patch(as("ruan"), usecase.JSONPatch, `[{"op": "remove", "path": "/id"}]`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id cannot be changed")
+	})
+
+	t.Run("Refuse unknown fields and wrong types", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.MergePatch, `{"color": "green", "price": "cheap", "quantity": 1.5}`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "color is not a fruit field; price has the wrong type; quantity has the wrong type")
+	})
+
+	t.Run("Refuse patches that don't apply", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.JSONPatch, `[{"op": "replace", "path": "/color", "value": "green"}]`)
+		assert.Nil(t, output)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+		assert.Equal(t, domainerror.ViolationsOf(err)[0].Field, "patch")
+
+		_, output, err = patch(as("ruan"), usecase.JSONPatch, `{"op": "replace"}`)
+		assert.Nil(t, output)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+
+		_, output, err = patch(as("ruan"), usecase.JSONPatch, `[{"op": "test", "path": "/quantity", "value": 2}]`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "patch test operation failed")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+	})
+
+	t.Run("Let only admins change the owner", func(t *testing.T) {
+		_, output, err := patch(as("ruan"), usecase.MergePatch, `{"owner": "clerk"}`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "only admins can transfer fruits to another owner")
+
+		_, output, err = patch(as("clerk"), usecase.MergePatch, `{"price": 1}`)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "only the owner of the fruit or an admin can update it")
+
+		_, output, err = patch(as("boss", auth.RoleAdmin), usecase.MergePatch, `{"owner": "clerk"}`)
+		assert.Nil(t, err)
+		assert.Equal(t, output.Owner, "clerk")
+	})
+
+	t.Run("Fail on version mismatch", func(t *testing.T) {
+		fruit, err := entity.NewFruit("uva", "ruan", 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Version = 2
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, fruit.ID).Return(fruit, nil)
+
+		expected := 1
+		output, err := usecase.NewPatchFruitUseCase(repository, allowed()).Execute(context.Background(), &usecase.PatchFruitUseCaseInputDTO{ID: fruit.ID, Format: usecase.MergePatch, Patch: []byte(`{"price": 1}`), ExpectedVersion: &expected})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit is at version 2, not 1")
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail if fruit is rotten", func(t *testing.T) {
+		fruit, err := entity.NewFruit("uva", "ruan", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruit.TransitionTo(entity.StatusPodrido))
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, fruit.ID).Return(fruit, nil)
+
+		output, err := usecase.NewPatchFruitUseCase(repository, allowed()).Execute(context.Background(), &usecase.PatchFruitUseCaseInputDTO{ID: fruit.ID, Format: usecase.MergePatch, Patch: []byte(`{"price": 1}`)})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit with status podrido cannot be updated")
+	})
+}
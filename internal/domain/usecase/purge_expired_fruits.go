@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+const purgeExpiredFruitsPageSize = 100
+
+type PurgeExpiredFruitsUseCase struct {
+	repository protocol.FruitRepository
+}
+
+type PurgeExpiredFruitsUseCaseInputDTO struct {
+	DeletedBefore time.Time
+}
+
+type PurgeExpiredFruitsUseCaseOutputDTO struct {
+	Purged []string
+}
+
+func NewPurgeExpiredFruitsUseCase(r protocol.FruitRepository) protocol.UseCase[*PurgeExpiredFruitsUseCaseInputDTO, *PurgeExpiredFruitsUseCaseOutputDTO] {
+	return &PurgeExpiredFruitsUseCase{
+		repository: r,
+	}
+}
+
+// Execute permanently remove every fruit soft deleted before the given instant
+func (pfu *PurgeExpiredFruitsUseCase) Execute(ctx context.Context, i *PurgeExpiredFruitsUseCaseInputDTO) (*PurgeExpiredFruitsUseCaseOutputDTO, error) {
+	if i.DeletedBefore.IsZero() {
+		var violations domainerror.Violations
+		violations.Add("deletedBefore", "deletedBefore is required")
+		return nil, violations.Err()
+	}
+
+	// collect everything before deleting, removing while paging would shift the pages
+	var expired []*entity.Fruit
+
+	// retention applies to every tenant alike
+	filter := &protocol.FruitSearchFilter{
+		AllTenants:    true,
+		Statuses:      []entity.FruitStatus{entity.StatusPodrido, entity.StatusDiscarded},
+		DeletedBefore: &i.DeletedBefore,
+	}
+
+	for page := 1; ; page++ {
+		result, err := pfu.repository.Search(ctx, filter, page, purgeExpiredFruitsPageSize)
+
+		if err != nil {
+			return nil, err
+		}
+
+		expired = append(expired, result.Results...)
+
+		if page*purgeExpiredFruitsPageSize >= result.Paging.Total {
+			break
+		}
+	}
+
+	output := &PurgeExpiredFruitsUseCaseOutputDTO{}
+
+	for _, fruit := range expired {
+		// conditioned on the version read, so a fruit restored since is kept
+		err := pfu.repository.Delete(ctx, fruit.Tenant, fruit.ID, fruit.Version)
+
+		if err != nil {
+			switch domainerror.KindOf(err) {
+			case domainerror.NotFound:
+				// purged by someone else meanwhile
+				continue
+			case domainerror.Conflict:
+				logger.FromContext(ctx).WithFields(logrus.Fields{"tenant": fruit.Tenant, "fruit_id": fruit.ID}).Info("expired fruit changed since read, not purged")
+				continue
+			}
+
+			return output, err
+		}
+
+		output.Purged = append(output.Purged, fruit.ID)
+		logger.FromContext(ctx).WithFields(logrus.Fields{"tenant": fruit.Tenant, "fruit_id": fruit.ID}).Debug("expired fruit purged")
+	}
+
+	return output, nil
+}
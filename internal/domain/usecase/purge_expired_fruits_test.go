@@ -0,0 +1,91 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestNewPurgeExpiredFruitsUseCase(t *testing.T) {
+	r := &mocks.FruitRepositoryMock{}
+	u := usecase.NewPurgeExpiredFruitsUseCase(r)
+	assert.NotNil(t, u)
+}
+
+func searchResultOf(fruits ...*entity.Fruit) *protocol.FruitSearchResult {
+	return &protocol.FruitSearchResult{
+		Paging:  &protocol.FruitSearchResultPaging{Total: len(fruits), Offset: 1, Limit: 100},
+		Results: fruits,
+	}
+}
+
+func expiredFilter() interface{} {
+	return mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool {
+		return f.AllTenants &&
+			assert.ObjectsAreEqual(f.Statuses, []entity.FruitStatus{entity.StatusPodrido, entity.StatusDiscarded}) &&
+			f.DeletedBefore != nil
+	})
+}
+
+func TestPurgeExpiredFruitsUseCase_Execute(t *testing.T) {
+	t.Run("With invalid input", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		u := usecase.NewPurgeExpiredFruitsUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeExpiredFruitsUseCaseInputDTO{})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "deletedBefore is required")
+	})
+
+	t.Run("With search fail", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&protocol.FruitSearchResult{}, errors.New("search failed"))
+		u := usecase.NewPurgeExpiredFruitsUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeExpiredFruitsUseCaseInputDTO{DeletedBefore: time.Now()})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "search failed")
+	})
+
+	t.Run("Purge podrido and discarded fruits", func(t *testing.T) {
+		podrido := &entity.Fruit{Tenant: tenant.Default, ID: "podrido-id", Status: entity.StatusPodrido, Version: 2}
+		discarded := &entity.Fruit{Tenant: "acme", ID: "discarded-id", Status: entity.StatusDiscarded, Version: 3}
+		gone := &entity.Fruit{Tenant: "acme", ID: "gone-id", Status: entity.StatusDiscarded, Version: 2}
+		restored := &entity.Fruit{Tenant: "acme", ID: "restored-id", Status: entity.StatusDiscarded, Version: 2}
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, expiredFilter(), 1, 100).Return(searchResultOf(podrido, discarded, gone, restored), nil)
+		r.On("Delete", mock.Anything, tenant.Default, "podrido-id", 2).Return(nil)
+		r.On("Delete", mock.Anything, "acme", "discarded-id", 3).Return(nil)
+		r.On("Delete", mock.Anything, "acme", "gone-id", 2).Return(domainerror.NewNotFoundError("fruit not found"))
+		r.On("Delete", mock.Anything, "acme", "restored-id", 2).Return(domainerror.NewConflictError("fruit has been modified by another request"))
+		u := usecase.NewPurgeExpiredFruitsUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeExpiredFruitsUseCaseInputDTO{DeletedBefore: time.Now()})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Purged, []string{"podrido-id", "discarded-id"})
+		r.AssertNumberOfCalls(t, "Delete", 4)
+	})
+
+	t.Run("When delete method fails", func(t *testing.T) {
+		podrido := &entity.Fruit{Tenant: tenant.Default, ID: "podrido-id", Status: entity.StatusPodrido}
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, expiredFilter(), 1, 100).Return(searchResultOf(podrido), nil)
+		r.On("Delete", mock.Anything, tenant.Default, "podrido-id", 0).Return(errors.New("fail to delete"))
+		u := usecase.NewPurgeExpiredFruitsUseCase(r)
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeExpiredFruitsUseCaseInputDTO{DeletedBefore: time.Now()})
+		assert.EqualError(t, err, "fail to delete")
+		assert.Len(t, output.Purged, 0)
+	})
+}
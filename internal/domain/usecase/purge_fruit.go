@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+)
+
+type PurgeFruitUseCase struct {
+	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+}
+
+type PurgeFruitUseCaseInputDTO struct {
+	ID string
+}
+
+type PurgeFruitUseCaseOutputDTO struct {
+	ID string
+}
+
+func NewPurgeFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*PurgeFruitUseCaseInputDTO, *PurgeFruitUseCaseOutputDTO] {
+	return &PurgeFruitUseCase{
+		repository: r,
+		authorizer: a,
+	}
+}
+
+func (pfu *PurgeFruitUseCase) Execute(ctx context.Context, i *PurgeFruitUseCaseInputDTO) (*PurgeFruitUseCaseOutputDTO, error) {
+	if i.ID == "" {
+		var violations domainerror.Violations
+		violations.Add("id", "id is required")
+		return nil, violations.Err()
+	}
+
+	fruit, err := pfu.repository.Get(ctx, tenant.FromContext(ctx), i.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pfu.authorizer.Authorize(ctx, auth.ActionPurge, fruit); err != nil {
+		return nil, err
+	}
+
+	if !fruit.Status.IsDeleted() {
+		return nil, domainerror.NewConflictError(fmt.Sprintf("fruit with status %s must be deleted before being purged", fruit.Status))
+	}
+
+	// restored or otherwise saved since read, purging it now would lose that change
+	err = pfu.repository.Delete(ctx, fruit.Tenant, fruit.ID, fruit.Version)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithField("fruit_id", fruit.ID).Info("fruit purged")
+
+	return &PurgeFruitUseCaseOutputDTO{
+		ID: fruit.ID,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestNewPurgeFruitUseCase(t *testing.T) {
+	r := &mocks.FruitRepositoryMock{}
+	u := usecase.NewPurgeFruitUseCase(r, allowed())
+	assert.NotNil(t, u)
+}
+
+func TestPurgeFruitUseCase_Execute(t *testing.T) {
+	t.Run("With invalid input", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id is required")
+	})
+
+	t.Run("With not found fruit", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, domainerror.NewNotFoundError("fruit not found"))
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{ID: "invalid-id"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit not found")
+	})
+
+	t.Run("With not deleted fruit", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{ID: fruitMock.ID})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit with status comestible must be deleted before being purged")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		r.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("When delete method fails", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Delete", mock.Anything, tenant.Default, fruitMock.ID, fruitMock.Version).Return(errors.New("fail to delete"))
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{ID: fruitMock.ID})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fail to delete")
+	})
+
+	t.Run("When fruit was restored since read", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+		fruitMock.Version = 2
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Delete", mock.Anything, tenant.Default, fruitMock.ID, 2).Return(domainerror.NewConflictError("fruit has been modified by another request"))
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{ID: fruitMock.ID})
+		assert.Nil(t, output)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+	})
+
+	t.Run("Successfully", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Delete", mock.Anything, tenant.Default, fruitMock.ID, fruitMock.Version).Return(nil)
+		u := usecase.NewPurgeFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.PurgeFruitUseCaseInputDTO{ID: fruitMock.ID})
+		assert.Nil(t, err)
+		assert.Equal(t, output.ID, fruitMock.ID)
+		r.AssertNumberOfCalls(t, "Delete", 1)
+	})
+}
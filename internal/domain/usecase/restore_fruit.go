@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type RestoreFruitUseCase struct {
+	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+}
+
+type RestoreFruitUseCaseInputDTO struct {
+	ID         string
+	RestoredBy string
+}
+
+type RestoreFruitUseCaseOutputDTO struct {
+	ID         string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	Name       string
+	Owner      string
+	Quantity   int
+	Price      float64
+	Status     string
+	RestoredBy string
+	RestoredAt time.Time
+}
+
+func NewRestoreFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*RestoreFruitUseCaseInputDTO, *RestoreFruitUseCaseOutputDTO] {
+	return &RestoreFruitUseCase{
+		repository: r,
+		authorizer: a,
+	}
+}
+
+func (rfu *RestoreFruitUseCase) Execute(ctx context.Context, i *RestoreFruitUseCaseInputDTO) (*RestoreFruitUseCaseOutputDTO, error) {
+	err := rfu.validateInput(i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fruit, err := rfu.repository.Get(ctx, tenant.FromContext(ctx), i.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rfu.authorizer.Authorize(ctx, auth.ActionRestore, fruit); err != nil {
+		return nil, err
+	}
+
+	err = fruit.Restore(i.RestoredBy)
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = rfu.repository.Save(ctx, fruit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"fruit_id": fruit.ID, "restored_by": fruit.RestoredBy, "status": fruit.Status}).Info("fruit restored")
+
+	return &RestoreFruitUseCaseOutputDTO{
+		ID:         fruit.ID,
+		CreatedAt:  fruit.CreatedAt,
+		UpdatedAt:  fruit.UpdatedAt,
+		Name:       fruit.Name,
+		Owner:      fruit.Owner,
+		Quantity:   fruit.Quantity,
+		Price:      fruit.Price,
+		Status:     string(fruit.Status),
+		RestoredBy: fruit.RestoredBy,
+		RestoredAt: *fruit.RestoredAt,
+	}, nil
+}
+
+func (*RestoreFruitUseCase) validateInput(i *RestoreFruitUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
+	if i.ID == "" {
+		violations.Add("id", "id is required")
+	}
+
+	if i.RestoredBy == "" {
+		violations.Add("restoredBy", "restoredBy is required")
+	}
+
+	return violations.Err()
+}
@@ -0,0 +1,90 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestNewRestoreFruitUseCase(t *testing.T) {
+	r := &mocks.FruitRepositoryMock{}
+	u := usecase.NewRestoreFruitUseCase(r, allowed())
+	assert.NotNil(t, u)
+}
+
+func TestRestoreFruitUseCase_Execute(t *testing.T) {
+	t.Run("With invalid input", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		u := usecase.NewRestoreFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.RestoreFruitUseCaseInputDTO{})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id is required; restoredBy is required")
+	})
+
+	t.Run("With purged fruit", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, domainerror.NewNotFoundError("fruit not found"))
+		u := usecase.NewRestoreFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.RestoreFruitUseCaseInputDTO{ID: "purged-id", RestoredBy: "clerk"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit not found")
+	})
+
+	t.Run("With not deleted fruit", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewRestoreFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.RestoreFruitUseCaseInputDTO{ID: fruitMock.ID, RestoredBy: "clerk"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit with status comestible is not deleted")
+		r.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When save method fails", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Save", mock.Anything, mock.Anything).Return(errors.New("fail to save"))
+		u := usecase.NewRestoreFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.RestoreFruitUseCaseInputDTO{ID: fruitMock.ID, RestoredBy: "clerk"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fail to save")
+	})
+
+	t.Run("Successfully", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Save", mock.Anything, mock.Anything).Return(nil)
+		u := usecase.NewRestoreFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.RestoreFruitUseCaseInputDTO{ID: fruitMock.ID, RestoredBy: "clerk"})
+
+		r.AssertNumberOfCalls(t, "Save", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, output.ID, fruitMock.ID)
+		assert.Equal(t, output.Status, "comestible")
+		assert.Equal(t, output.RestoredBy, "clerk")
+		assert.False(t, output.RestoredAt.IsZero())
+	})
+}
@@ -2,18 +2,45 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"strings"
 	"time"
 )
 
+// defaultCursorSort the order cursors page through when no sort is given, close to the insertion order
+var defaultCursorSort = []protocol.FruitSortKey{{Field: protocol.SortByCreatedAt}}
+
 type SearchFruitUseCase struct {
 	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+	cursors    protocol.FruitCursorCodec
 }
 
+// SearchFruitUseCaseInputDTO the search criteria, every filter is optional and nil ranges are open ended
 type SearchFruitUseCaseInputDTO struct {
-	Name   string
-	Status string
+	Name string
+	// Match how Name is matched, "contains" by default or "fuzzy" to ignore accents, tolerate typos and rank by relevance
+	Match         string
+	Statuses      []string
+	Owner         string
+	MinPrice      *float64
+	MaxPrice      *float64
+	MinQuantity   *int
+	MaxQuantity   *int
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	UpdatedAfter  *time.Time
+	UpdatedBefore *time.Time
+	// Sort comma separated fields to order by, each descending when prefixed by "-", e.g. "-price,name"
+	Sort string
+	// Cursor page with cursors instead of page numbers when set: empty for the first page, then the next or prev
+	// cursor of the previous response. Offset is ignored, the sort defaults to the cursor one and the total is
+	// left at zero.
+	Cursor *string
 	Offset int
 	Limit  int
 }
@@ -33,50 +60,74 @@ type SearchFruitUseCaseOutputResult struct {
 	Quantity  int
 	Price     float64
 	Status    string
+	// Score the relevance of the fruit to a fuzzy name, from 0 to 1, zero otherwise
+	Score float64
+}
+
+// SearchFruitUseCaseOutputCursors the cursors of the pages around the results, empty when there is no such page
+type SearchFruitUseCaseOutputCursors struct {
+	Next string
+	Prev string
 }
 
 type SearchFruitUseCaseOutputDTO struct {
 	Paging  *SearchFruitUseCaseOutputPaging
 	Results []*SearchFruitUseCaseOutputResult
+	// Cursors only set when paging with cursors
+	Cursors *SearchFruitUseCaseOutputCursors
 }
 
-func NewSearchFruitUseCase(r protocol.FruitRepository) protocol.UseCase[*SearchFruitUseCaseInputDTO, *SearchFruitUseCaseOutputDTO] {
+func NewSearchFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer, c protocol.FruitCursorCodec) protocol.UseCase[*SearchFruitUseCaseInputDTO, *SearchFruitUseCaseOutputDTO] {
 	return &SearchFruitUseCase{
-		r,
+		repository: r,
+		authorizer: a,
+		cursors:    c,
 	}
 }
 
 func (sfu *SearchFruitUseCase) Execute(ctx context.Context, input *SearchFruitUseCaseInputDTO) (*SearchFruitUseCaseOutputDTO, error) {
-	err := sfu.validateInput(input)
+	sort, err := sfu.validateInput(input)
 
 	if err != nil {
 		return nil, err
 	}
 
-	filter := &protocol.FruitSearchFilter{
-		Name:   input.Name,
-		Status: input.Status,
+	filter, err := searchFilter(ctx, sfu.authorizer, input, sort)
+
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := sfu.repository.Search(ctx, filter, input.Offset, input.Limit)
+	offset := input.Offset
+	if input.Cursor != nil {
+		filter.Cursor, filter.Sort, err = sfu.decodeCursor(*input.Cursor, filter.Tenant, sort)
+		if err != nil {
+			return nil, err
+		}
+		// the first page, repositories then page from the cursor alone
+		offset = 1
+		// cursor pages are not numbered, counting every match on each of them would be wasted
+		filter.SkipTotal = true
+	}
+
+	result, err := sfu.repository.Search(ctx, filter, offset, input.Limit)
 
 	if err != nil {
 		return nil, err
 	}
 
+	var cursors *SearchFruitUseCaseOutputCursors
+	if input.Cursor != nil {
+		cursors, err = sfu.encodeCursors(filter, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var mappedResult []*SearchFruitUseCaseOutputResult
 
-	for _, r := range result.Results {
-		mappedResult = append(mappedResult, &SearchFruitUseCaseOutputResult{
-			ID:        r.ID,
-			CreatedAt: r.CreatedAt,
-			UpdatedAt: r.UpdatedAt,
-			Name:      r.Name,
-			Owner:     r.Owner,
-			Quantity:  r.Quantity,
-			Price:     r.Price,
-			Status:    r.Status,
-		})
+	for i, r := range result.Results {
+		mappedResult = append(mappedResult, searchResultOf(r, result.Scores, i))
 	}
 
 	return &SearchFruitUseCaseOutputDTO{
@@ -86,25 +137,251 @@ func (sfu *SearchFruitUseCase) Execute(ctx context.Context, input *SearchFruitUs
 			Limit:  result.Paging.Limit,
 		},
 		Results: mappedResult,
+		Cursors: cursors,
+	}, nil
+}
+
+// searchFilter the repository filter of the input criteria, restricted to the fruits the caller may read
+func searchFilter(ctx context.Context, authorizer protocol.Authorizer, input *SearchFruitUseCaseInputDTO, sort []protocol.FruitSortKey) (*protocol.FruitSearchFilter, error) {
+	owner, err := authorizer.ReadScope(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if owner == "" {
+		owner = input.Owner
+	} else if input.Owner != "" && input.Owner != owner {
+		return nil, domainerror.NewForbiddenError("only admins can search the fruits of other owners")
+	}
+
+	statuses := make([]entity.FruitStatus, len(input.Statuses))
+	for i, status := range input.Statuses {
+		statuses[i] = entity.FruitStatus(status)
+	}
+
+	return &protocol.FruitSearchFilter{
+		Tenant:        tenant.FromContext(ctx),
+		Name:          input.Name,
+		Match:         protocol.FruitNameMatch(input.Match),
+		Statuses:      statuses,
+		Owner:         owner,
+		MinPrice:      input.MinPrice,
+		MaxPrice:      input.MaxPrice,
+		MinQuantity:   input.MinQuantity,
+		MaxQuantity:   input.MaxQuantity,
+		CreatedAfter:  input.CreatedAfter,
+		CreatedBefore: input.CreatedBefore,
+		UpdatedAfter:  input.UpdatedAfter,
+		UpdatedBefore: input.UpdatedBefore,
+		Sort:          sort,
 	}, nil
 }
 
-func (sfu *SearchFruitUseCase) validateInput(i *SearchFruitUseCaseInputDTO) error {
-	if i.Name == "" {
-		return errors.New("name is required")
+// searchResultOf map the i-th fruit of a search, along with its score when the search has scores
+func searchResultOf(f *entity.Fruit, scores []float64, i int) *SearchFruitUseCaseOutputResult {
+	var score float64
+	if i < len(scores) {
+		score = scores[i]
+	}
+
+	return &SearchFruitUseCaseOutputResult{
+		ID:        f.ID,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+		Name:      f.Name,
+		Owner:     f.Owner,
+		Quantity:  f.Quantity,
+		Price:     f.Price,
+		Status:    string(f.Status),
+		Score:     score,
+	}
+}
+
+// decodeCursor return the position token points to and the sort to page with, token is empty for the first page
+func (sfu *SearchFruitUseCase) decodeCursor(token string, tenantID string, sort []protocol.FruitSortKey) (*protocol.FruitSearchCursor, []protocol.FruitSortKey, error) {
+	if token == "" {
+		if len(sort) == 0 {
+			sort = defaultCursorSort
+		}
+		return nil, sort, nil
+	}
+
+	cursor, err := sfu.cursors.Decode(token)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var violations domainerror.Violations
+	switch {
+	case cursor.Position.Tenant != tenantID:
+		violations.Add("cursor", "invalid cursor")
+	case len(sort) > 0 && !sameSort(sort, cursor.Sort):
+		violations.Add("cursor", "cursor was taken with another sort")
+	}
+
+	if err := violations.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return cursor, cursor.Sort, nil
+}
+
+// encodeCursors point to the pages before and after the results, when there are any
+func (sfu *SearchFruitUseCase) encodeCursors(filter *protocol.FruitSearchFilter, result *protocol.FruitSearchResult) (*SearchFruitUseCaseOutputCursors, error) {
+	cursors := &SearchFruitUseCaseOutputCursors{}
+	if len(result.Results) == 0 {
+		return cursors, nil
+	}
+
+	backward := filter.Cursor != nil && filter.Cursor.Backward
+	hasNext := result.HasMore || backward
+	hasPrev := filter.Cursor != nil && (!backward || result.HasMore)
+
+	var err error
+	if hasNext {
+		last := result.Results[len(result.Results)-1]
+		cursors.Next, err = sfu.cursors.Encode(&protocol.FruitSearchCursor{Sort: filter.Sort, Position: protocol.PositionOf(last)})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if hasPrev {
+		first := result.Results[0]
+		cursors.Prev, err = sfu.cursors.Encode(&protocol.FruitSearchCursor{Sort: filter.Sort, Position: protocol.PositionOf(first), Backward: true})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return cursors, nil
+}
+
+func sameSort(a []protocol.FruitSortKey, b []protocol.FruitSortKey) bool {
+	if len(a) != len(b) {
+		return false
 	}
 
-	if i.Status == "" {
-		return errors.New("status is required")
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
 	}
 
-	if i.Offset <= 0 {
-		return errors.New("offset must be greater than 0")
+	return true
+}
+
+// validateInput check every criteria and the paging, returning the parsed sort keys
+func (sfu *SearchFruitUseCase) validateInput(i *SearchFruitUseCaseInputDTO) ([]protocol.FruitSortKey, error) {
+	var violations domainerror.Violations
+
+	sort := validateCriteria(i, &violations)
+
+	if i.Cursor == nil && i.Offset <= 0 {
+		violations.Add("offset", "offset must be greater than 0")
 	}
 
 	if i.Limit < 1 || i.Limit > 100 {
-		return errors.New("limit must be a number between 1 and 100")
+		violations.Add("limit", "limit must be a number between 1 and 100")
+	}
+
+	return sort, violations.Err()
+}
+
+// validateCriteria check the filters and the sort of a search, adding the problems to violations, and return the
+// parsed sort keys
+func validateCriteria(i *SearchFruitUseCaseInputDTO, violations *domainerror.Violations) []protocol.FruitSortKey {
+	switch protocol.FruitNameMatch(i.Match) {
+	case "", protocol.MatchContains:
+	case protocol.MatchFuzzy:
+		if strings.TrimSpace(i.Name) == "" {
+			violations.Add("name", "name is required by the fuzzy match")
+		}
+	default:
+		matches := make([]string, len(protocol.FruitNameMatches))
+		for i, match := range protocol.FruitNameMatches {
+			matches[i] = string(match)
+		}
+		violations.Add("match", fmt.Sprintf("match must be one of %s", strings.Join(matches, ", ")))
+	}
+
+	for _, status := range i.Statuses {
+		if _, err := entity.ParseFruitStatus(status); err != nil {
+			violations.Add("status", err.Error())
+			break
+		}
+	}
+
+	if i.MinPrice != nil && i.MaxPrice != nil && *i.MinPrice > *i.MaxPrice {
+		violations.Add("min_price", "min_price cannot be greater than max_price")
+	}
+
+	if i.MinQuantity != nil && i.MaxQuantity != nil && *i.MinQuantity > *i.MaxQuantity {
+		violations.Add("min_quantity", "min_quantity cannot be greater than max_quantity")
+	}
+
+	if i.CreatedAfter != nil && i.CreatedBefore != nil && !i.CreatedAfter.Before(*i.CreatedBefore) {
+		violations.Add("created_after", "created_after must be before created_before")
+	}
+
+	if i.UpdatedAfter != nil && i.UpdatedBefore != nil && !i.UpdatedAfter.Before(*i.UpdatedBefore) {
+		violations.Add("updated_after", "updated_after must be before updated_before")
+	}
+
+	sort, err := parseSort(i.Sort)
+	if err != nil {
+		violations.Add("sort", err.Error())
+	}
+
+	return sort
+}
+
+// parseSort parse comma separated sort fields, refusing the fields out of protocol.FruitSortFields and repeated ones
+func parseSort(value string) ([]protocol.FruitSortKey, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var keys []protocol.FruitSortKey
+	seen := map[protocol.FruitSortField]bool{}
+
+	for _, term := range strings.Split(value, ",") {
+		term = strings.TrimSpace(term)
+		key := protocol.FruitSortKey{}
+
+		if strings.HasPrefix(term, "-") {
+			key.Descending = true
+			term = term[1:]
+		}
+
+		key.Field = protocol.FruitSortField(term)
+
+		if !isSortField(key.Field) {
+			fields := make([]string, len(protocol.FruitSortFields))
+			for i, field := range protocol.FruitSortFields {
+				fields[i] = string(field)
+			}
+			return nil, fmt.Errorf("sort field must be one of %s", strings.Join(fields, ", "))
+		}
+
+		if seen[key.Field] {
+			return nil, fmt.Errorf("sort field %s is repeated", key.Field)
+		}
+
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func isSortField(field protocol.FruitSortField) bool {
+	for _, f := range protocol.FruitSortFields {
+		if f == field {
+			return true
+		}
 	}
 
-	return nil
+	return false
 }
@@ -4,50 +4,127 @@ import (
 	"context"
 	"errors"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"testing"
+	"time"
 )
 
 func TestNewSearchFruitUseCase(t *testing.T) {
 	r := &mocks.FruitRepositoryMock{}
-	u := usecase.NewSearchFruitUseCase(r)
+	u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
 	assert.NotNil(t, u)
 }
 
 func TestSearchFruitUseCase_Execute(t *testing.T) {
 	t.Run("With invalid input", func(t *testing.T) {
 		r := &mocks.FruitRepositoryMock{}
-		u := usecase.NewSearchFruitUseCase(r)
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
+
+		minPrice, maxPrice := 10.0, 5.0
+		minQuantity, maxQuantity := 3, 1
+		after := time.Now()
+		before := after.Add(-time.Hour)
 
 		input := &usecase.SearchFruitUseCaseInputDTO{
-			Name:   "",
-			Status: "",
-			Offset: 0,
-			Limit:  0,
+			Statuses:      []string{"comestible", "status"},
+			MinPrice:      &minPrice,
+			MaxPrice:      &maxPrice,
+			MinQuantity:   &minQuantity,
+			MaxQuantity:   &maxQuantity,
+			CreatedAfter:  &after,
+			CreatedBefore: &before,
+			UpdatedAfter:  &after,
+			UpdatedBefore: &before,
+			Offset:        0,
+			Limit:         0,
 		}
 
 		output, err := u.Execute(context.Background(), input)
 		assert.Nil(t, output)
-		assert.EqualError(t, err, "name is required")
+		assert.EqualError(t, err, "status must be one of comestible, reserved, sold, podrido, discarded; min_price cannot be greater than max_price; min_quantity cannot be greater than max_quantity; created_after must be before created_before; updated_after must be before updated_before; offset must be greater than 0; limit must be a number between 1 and 100")
+		assert.Len(t, domainerror.ViolationsOf(err), 7)
+
+		input = &usecase.SearchFruitUseCaseInputDTO{Sort: "-price,color", Offset: 1, Limit: 10}
+		output, err = u.Execute(context.Background(), input)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "sort field must be one of name, price, quantity, createdAt, updatedAt")
 
-		input.Name = "name"
+		input = &usecase.SearchFruitUseCaseInputDTO{Sort: "price,-price", Offset: 1, Limit: 10}
 		output, err = u.Execute(context.Background(), input)
 		assert.Nil(t, output)
-		assert.EqualError(t, err, "status is required")
+		assert.EqualError(t, err, "sort field price is repeated")
 
-		input.Status = "status"
+		input = &usecase.SearchFruitUseCaseInputDTO{Name: "uva", Match: "exact", Offset: 1, Limit: 10}
 		output, err = u.Execute(context.Background(), input)
 		assert.Nil(t, output)
-		assert.EqualError(t, err, "offset must be greater than 0")
+		assert.EqualError(t, err, "match must be one of contains, fuzzy")
 
-		input.Offset = 1
+		input = &usecase.SearchFruitUseCaseInputDTO{Name: " ", Match: "fuzzy", Offset: 1, Limit: 10}
+		output, err = u.Execute(context.Background(), input)
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "name is required by the fuzzy match")
+
+		input = &usecase.SearchFruitUseCaseInputDTO{Offset: 1}
 		output, err = u.Execute(context.Background(), input)
 		assert.Nil(t, output)
 		assert.EqualError(t, err, "limit must be a number between 1 and 100")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+	})
+
+	t.Run("Without filters", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{}}, 1, 10).Return(&protocol.FruitSearchResult{Paging: &protocol.FruitSearchResultPaging{}}, nil)
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
+
+		_, err := u.Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Offset: 1, Limit: 10})
+		assert.Nil(t, err)
+		r.AssertExpectations(t)
+	})
+
+	t.Run("With every filter", func(t *testing.T) {
+		minPrice, maxPrice := 1.0, 5.0
+		minQuantity, maxQuantity := 1, 3
+		createdAfter := time.Now().Add(-time.Hour)
+		createdBefore := time.Now()
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, &protocol.FruitSearchFilter{
+			Tenant:        tenant.Default,
+			Name:          "uva",
+			Statuses:      []entity.FruitStatus{entity.StatusComestible, entity.StatusReserved},
+			Owner:         "ruan",
+			MinPrice:      &minPrice,
+			MaxPrice:      &maxPrice,
+			MinQuantity:   &minQuantity,
+			MaxQuantity:   &maxQuantity,
+			CreatedAfter:  &createdAfter,
+			CreatedBefore: &createdBefore,
+			Sort:          []protocol.FruitSortKey{{Field: protocol.SortByPrice, Descending: true}, {Field: protocol.SortByName}},
+		}, 1, 10).Return(&protocol.FruitSearchResult{Paging: &protocol.FruitSearchResultPaging{}}, nil)
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
+
+		_, err := u.Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{
+			Name:          "uva",
+			Statuses:      []string{"comestible", "reserved"},
+			Owner:         "ruan",
+			MinPrice:      &minPrice,
+			MaxPrice:      &maxPrice,
+			MinQuantity:   &minQuantity,
+			MaxQuantity:   &maxQuantity,
+			CreatedAfter:  &createdAfter,
+			CreatedBefore: &createdBefore,
+			Sort:          " -price, name ",
+			Offset:        1,
+			Limit:         10,
+		})
+		assert.Nil(t, err)
+		r.AssertExpectations(t)
 	})
 
 	t.Run("With search fail", func(t *testing.T) {
@@ -55,13 +132,13 @@ func TestSearchFruitUseCase_Execute(t *testing.T) {
 		r := &mocks.FruitRepositoryMock{}
 		r.On("Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(&protocol.FruitSearchResult{}, errors.New("search failed"))
 
-		u := usecase.NewSearchFruitUseCase(r)
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
 
 		input := &usecase.SearchFruitUseCaseInputDTO{
-			Name:   "fruit",
-			Status: "comestible",
-			Offset: 1,
-			Limit:  10,
+			Name:     "fruit",
+			Statuses: []string{"comestible"},
+			Offset:   1,
+			Limit:    10,
 		}
 
 		output, err := u.Execute(context.Background(), input)
@@ -91,13 +168,13 @@ func TestSearchFruitUseCase_Execute(t *testing.T) {
 		r := &mocks.FruitRepositoryMock{}
 		r.On("Search", mock.Anything, mock.Anything, mock.Anything, mock.Anything).Return(searchResult, nil)
 
-		u := usecase.NewSearchFruitUseCase(r)
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
 
 		input := &usecase.SearchFruitUseCaseInputDTO{
-			Name:   "fruit",
-			Status: "comestible",
-			Offset: 1,
-			Limit:  10,
+			Name:     "fruit",
+			Statuses: []string{"comestible"},
+			Offset:   1,
+			Limit:    10,
 		}
 
 		output, err := u.Execute(context.Background(), input)
@@ -109,4 +186,114 @@ func TestSearchFruitUseCase_Execute(t *testing.T) {
 		assert.Len(t, output.Results, 2)
 	})
 
+	t.Run("With fuzzy match", func(t *testing.T) {
+		banana, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		bananada, err := entity.NewFruit("bananada", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "bananna", Match: protocol.MatchFuzzy, Statuses: []entity.FruitStatus{}}, 1, 10).Return(&protocol.FruitSearchResult{
+			Paging:  &protocol.FruitSearchResultPaging{Total: 2, Limit: 10, Offset: 1},
+			Results: []*entity.Fruit{banana, bananada},
+			Scores:  []float64{0.686, 0.6},
+		}, nil)
+
+		u := usecase.NewSearchFruitUseCase(r, allowed(), &mocks.FruitCursorCodecMock{})
+
+		output, err := u.Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Name: "bananna", Match: "fuzzy", Offset: 1, Limit: 10})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Results[0].Score, 0.686)
+		assert.Equal(t, output.Results[1].Score, 0.6)
+		r.AssertExpectations(t)
+	})
+}
+
+func TestSearchFruitUseCase_ExecuteWithCursor(t *testing.T) {
+	banana, err := entity.NewFruit("banana", "ruan", 1, 10.0)
+	assert.Nil(t, err)
+	grape, err := entity.NewFruit("grape", "ruan", 1, 10.0)
+	assert.Nil(t, err)
+
+	byPrice := []protocol.FruitSortKey{{Field: protocol.SortByPrice, Descending: true}}
+	page := &protocol.FruitSearchResult{Paging: &protocol.FruitSearchResultPaging{Total: 5, Limit: 2}, Results: []*entity.Fruit{banana, grape}}
+
+	first := func(f *protocol.FruitSearchFilter) bool { return f.Cursor == nil }
+
+	t.Run("First page", func(t *testing.T) {
+		firstPage := *page
+		firstPage.HasMore = true
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool {
+			return first(f) && f.SkipTotal && assert.ObjectsAreEqual(f.Sort, []protocol.FruitSortKey{{Field: protocol.SortByCreatedAt}})
+		}), 1, 2).Return(&firstPage, nil)
+
+		c := &mocks.FruitCursorCodecMock{}
+		c.On("Encode", &protocol.FruitSearchCursor{Sort: []protocol.FruitSortKey{{Field: protocol.SortByCreatedAt}}, Position: protocol.PositionOf(grape)}).Return("next-token", nil)
+
+		cursor := ""
+		output, err := usecase.NewSearchFruitUseCase(r, allowed(), c).Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Cursor: &cursor, Limit: 2})
+		assert.Nil(t, err)
+		assert.Len(t, output.Results, 2)
+		assert.Equal(t, output.Cursors, &usecase.SearchFruitUseCaseOutputCursors{Next: "next-token"})
+		c.AssertExpectations(t)
+	})
+
+	t.Run("Last page", func(t *testing.T) {
+		taken := &protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.FruitSearchPosition{Tenant: tenant.Default, ID: "apple-id", Price: 12}}
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.MatchedBy(func(f *protocol.FruitSearchFilter) bool {
+			return f.Cursor == taken && assert.ObjectsAreEqual(f.Sort, byPrice)
+		}), 1, 2).Return(page, nil)
+
+		c := &mocks.FruitCursorCodecMock{}
+		c.On("Decode", "token").Return(taken, nil)
+		c.On("Encode", &protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.PositionOf(banana), Backward: true}).Return("prev-token", nil)
+
+		cursor := "token"
+		output, err := usecase.NewSearchFruitUseCase(r, allowed(), c).Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Cursor: &cursor, Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Cursors, &usecase.SearchFruitUseCaseOutputCursors{Prev: "prev-token"})
+		c.AssertExpectations(t)
+	})
+
+	t.Run("Backward to the first page", func(t *testing.T) {
+		taken := &protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.FruitSearchPosition{Tenant: tenant.Default, ID: "apple-id"}, Backward: true}
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Search", mock.Anything, mock.Anything, 1, 2).Return(page, nil)
+
+		c := &mocks.FruitCursorCodecMock{}
+		c.On("Decode", "token").Return(taken, nil)
+		c.On("Encode", &protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.PositionOf(grape)}).Return("next-token", nil)
+
+		cursor := "token"
+		output, err := usecase.NewSearchFruitUseCase(r, allowed(), c).Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Sort: "-price", Cursor: &cursor, Limit: 2})
+		assert.Nil(t, err)
+		assert.Equal(t, output.Cursors, &usecase.SearchFruitUseCaseOutputCursors{Next: "next-token"})
+		c.AssertExpectations(t)
+	})
+
+	t.Run("With cursor of another tenant", func(t *testing.T) {
+		c := &mocks.FruitCursorCodecMock{}
+		c.On("Decode", "token").Return(&protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.FruitSearchPosition{Tenant: "acme"}}, nil)
+
+		cursor := "token"
+		output, err := usecase.NewSearchFruitUseCase(&mocks.FruitRepositoryMock{}, allowed(), c).Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Cursor: &cursor, Limit: 2})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "invalid cursor")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+	})
+
+	t.Run("With cursor of another sort", func(t *testing.T) {
+		c := &mocks.FruitCursorCodecMock{}
+		c.On("Decode", "token").Return(&protocol.FruitSearchCursor{Sort: byPrice, Position: protocol.FruitSearchPosition{Tenant: tenant.Default}}, nil)
+
+		cursor := "token"
+		output, err := usecase.NewSearchFruitUseCase(&mocks.FruitRepositoryMock{}, allowed(), c).Execute(context.Background(), &usecase.SearchFruitUseCaseInputDTO{Sort: "name", Cursor: &cursor, Limit: 2})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "cursor was taken with another sort")
+	})
 }
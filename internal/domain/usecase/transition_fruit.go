@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"time"
+)
+
+type TransitionFruitUseCase struct {
+	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
+}
+
+type TransitionFruitUseCaseInputDTO struct {
+	ID     string
+	Status string
+}
+
+type TransitionFruitUseCaseOutputDTO struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	Owner     string
+	Quantity  int
+	Price     float64
+	Status    string
+}
+
+func NewTransitionFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*TransitionFruitUseCaseInputDTO, *TransitionFruitUseCaseOutputDTO] {
+	return &TransitionFruitUseCase{
+		repository: r,
+		authorizer: a,
+	}
+}
+
+func (tfu *TransitionFruitUseCase) Execute(ctx context.Context, i *TransitionFruitUseCaseInputDTO) (*TransitionFruitUseCaseOutputDTO, error) {
+	err := tfu.validateInput(i)
+
+	if err != nil {
+		return nil, err
+	}
+
+	fruit, err := tfu.repository.Get(ctx, tenant.FromContext(ctx), i.ID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tfu.authorizer.Authorize(ctx, auth.ActionTransition, fruit); err != nil {
+		return nil, err
+	}
+
+	err = fruit.TransitionTo(entity.FruitStatus(i.Status))
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = tfu.repository.Save(ctx, fruit)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"fruit_id": fruit.ID, "from": fruit.PreviousStatus, "to": fruit.Status}).Info("fruit status changed")
+
+	return &TransitionFruitUseCaseOutputDTO{
+		ID:        fruit.ID,
+		CreatedAt: fruit.CreatedAt,
+		UpdatedAt: fruit.UpdatedAt,
+		Name:      fruit.Name,
+		Owner:     fruit.Owner,
+		Quantity:  fruit.Quantity,
+		Price:     fruit.Price,
+		Status:    string(fruit.Status),
+	}, nil
+}
+
+func (*TransitionFruitUseCase) validateInput(i *TransitionFruitUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
+	if i.ID == "" {
+		violations.Add("id", "id is required")
+	}
+
+	if i.Status == "" {
+		violations.Add("status", "status is required")
+	} else if _, err := entity.ParseFruitStatus(i.Status); err != nil {
+		violations.Add("status", err.Error())
+	}
+
+	return violations.Err()
+}
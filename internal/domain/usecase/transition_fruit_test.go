@@ -0,0 +1,92 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestNewTransitionFruitUseCase(t *testing.T) {
+	r := &mocks.FruitRepositoryMock{}
+	u := usecase.NewTransitionFruitUseCase(r, allowed())
+	assert.NotNil(t, u)
+}
+
+func TestTransitionFruitUseCase_Execute(t *testing.T) {
+	t.Run("With invalid input", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		u := usecase.NewTransitionFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "id is required; status is required")
+
+		output, err = u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{ID: "id", Status: "eaten"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "status must be one of comestible, reserved, sold, podrido, discarded")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+	})
+
+	t.Run("With not found fruit", func(t *testing.T) {
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, domainerror.NewNotFoundError("fruit not found"))
+		u := usecase.NewTransitionFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{ID: "invalid-id", Status: "sold"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit not found")
+	})
+
+	t.Run("With illegal transition", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewTransitionFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{ID: fruitMock.ID, Status: "discarded"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit cannot go from comestible to discarded")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		r.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When save method fails", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Save", mock.Anything, mock.Anything).Return(errors.New("fail to save"))
+		u := usecase.NewTransitionFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{ID: fruitMock.ID, Status: "sold"})
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fail to save")
+	})
+
+	t.Run("Successfully", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		r := &mocks.FruitRepositoryMock{}
+		r.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		r.On("Save", mock.Anything, mock.Anything).Return(nil)
+		u := usecase.NewTransitionFruitUseCase(r, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.TransitionFruitUseCaseInputDTO{ID: fruitMock.ID, Status: "reserved"})
+
+		r.AssertNumberOfCalls(t, "Save", 1)
+		assert.Nil(t, err)
+		assert.Equal(t, output.ID, fruitMock.ID)
+		assert.Equal(t, output.Status, "reserved")
+	})
+}
@@ -2,19 +2,27 @@ package usecase
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
 	"time"
 )
 
 type UpdateFruitUseCase struct {
 	repository protocol.FruitRepository
+	authorizer protocol.Authorizer
 }
 
 type UpdateFruitUseCaseInputDTO struct {
 	ID       string
 	Quantity int
 	Price    float64
+	// ExpectedVersion when set, the update is refused unless the fruit is still at this version
+	ExpectedVersion *int
 }
 
 type UpdateFruitUseCaseOutputDTO struct {
@@ -26,11 +34,13 @@ type UpdateFruitUseCaseOutputDTO struct {
 	Quantity  int
 	Price     float64
 	Status    string
+	Version   int
 }
 
-func NewUpdateFruitUseCase(r protocol.FruitRepository) protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO] {
+func NewUpdateFruitUseCase(r protocol.FruitRepository, a protocol.Authorizer) protocol.UseCase[*UpdateFruitUseCaseInputDTO, *UpdateFruitUseCaseOutputDTO] {
 	return &UpdateFruitUseCase{
 		repository: r,
+		authorizer: a,
 	}
 }
 
@@ -41,15 +51,25 @@ func (cf *UpdateFruitUseCase) Execute(ctx context.Context, i *UpdateFruitUseCase
 		return nil, err
 	}
 
-	fruit, err := cf.repository.Get(ctx, i.ID)
+	fruit, err := cf.repository.Get(ctx, tenant.FromContext(ctx), i.ID)
 
 	if err != nil {
 		return nil, err
 	}
 
-	fruit.Quantity = i.Quantity
-	fruit.Price = i.Price
-	fruit.UpdatedAt = time.Now()
+	if err := cf.authorizer.Authorize(ctx, auth.ActionUpdate, fruit); err != nil {
+		return nil, err
+	}
+
+	if i.ExpectedVersion != nil && *i.ExpectedVersion != fruit.Version {
+		return nil, domainerror.NewPreconditionFailedError(fmt.Sprintf("fruit is at version %d, not %d", fruit.Version, *i.ExpectedVersion))
+	}
+
+	err = fruit.Update(i.Quantity, i.Price)
+
+	if err != nil {
+		return nil, err
+	}
 
 	err = cf.repository.Save(ctx, fruit)
 
@@ -57,6 +77,8 @@ func (cf *UpdateFruitUseCase) Execute(ctx context.Context, i *UpdateFruitUseCase
 		return nil, err
 	}
 
+	logger.FromContext(ctx).WithFields(logrus.Fields{"fruit_id": fruit.ID, "version": fruit.Version}).Info("fruit updated")
+
 	return &UpdateFruitUseCaseOutputDTO{
 		ID:        fruit.ID,
 		CreatedAt: fruit.CreatedAt,
@@ -65,23 +87,26 @@ func (cf *UpdateFruitUseCase) Execute(ctx context.Context, i *UpdateFruitUseCase
 		Owner:     fruit.Owner,
 		Quantity:  fruit.Quantity,
 		Price:     fruit.Price,
-		Status:    fruit.Status,
+		Status:    string(fruit.Status),
+		Version:   fruit.Version,
 	}, nil
 
 }
 
 func (*UpdateFruitUseCase) validateInput(i *UpdateFruitUseCaseInputDTO) error {
+	var violations domainerror.Violations
+
 	if i.ID == "" {
-		return errors.New("id is required")
+		violations.Add("id", "id is required")
 	}
 
 	if i.Quantity <= 0 {
-		return errors.New("quantity must be greater than zero")
+		violations.Add("quantity", "quantity must be greater than zero")
 	}
 
 	if i.Price <= 0 {
-		return errors.New("price must be greater than zero")
+		violations.Add("price", "price must be greater than zero")
 	}
 
-	return nil
+	return violations.Err()
 }
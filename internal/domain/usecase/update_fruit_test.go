@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	"github.com/ruancaetano/go-gin-fruits/internal/mocks"
 	"github.com/stretchr/testify/assert"
@@ -14,7 +16,7 @@ import (
 
 func TestNewUpdateFruitUseCase(t *testing.T) {
 	repository := &mocks.FruitRepositoryMock{}
-	u := usecase.NewUpdateFruitUseCase(repository)
+	u := usecase.NewUpdateFruitUseCase(repository, allowed())
 	assert.NotNil(t, u)
 }
 
@@ -22,7 +24,7 @@ func TestUpdateFruitUseCase_Execute(t *testing.T) {
 	t.Run("With Invalid Params", func(t *testing.T) {
 		repository := &mocks.FruitRepositoryMock{}
 		repository.On("Save", mock.Anything).Return(nil)
-		u := usecase.NewUpdateFruitUseCase(repository)
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
 
 		input := &usecase.UpdateFruitUseCaseInputDTO{
 			ID:       "",
@@ -47,8 +49,8 @@ func TestUpdateFruitUseCase_Execute(t *testing.T) {
 
 	t.Run("Fail if fruit not found", func(t *testing.T) {
 		repository := &mocks.FruitRepositoryMock{}
-		repository.On("Get", mock.Anything, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
-		u := usecase.NewUpdateFruitUseCase(repository)
+		repository.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&entity.Fruit{}, errors.New("fruit not found"))
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
 
 		output, err := u.Execute(context.Background(), &usecase.UpdateFruitUseCaseInputDTO{
 			ID:       "not-found-id",
@@ -61,14 +63,57 @@ func TestUpdateFruitUseCase_Execute(t *testing.T) {
 		assert.EqualError(t, err, "fruit not found")
 	})
 
+	t.Run("Fail if fruit is rotten", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, fruitMock.TransitionTo(entity.StatusPodrido))
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
+
+		output, err := u.Execute(context.Background(), &usecase.UpdateFruitUseCaseInputDTO{
+			ID:       fruitMock.ID,
+			Price:    20.0,
+			Quantity: 1,
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit with status podrido cannot be updated")
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Fail if fruit version does not match", func(t *testing.T) {
+		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		fruitMock.Version = 2
+
+		repository := &mocks.FruitRepositoryMock{}
+		repository.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
+
+		expectedVersion := 1
+		output, err := u.Execute(context.Background(), &usecase.UpdateFruitUseCaseInputDTO{
+			ID:              fruitMock.ID,
+			Price:           20.0,
+			Quantity:        1,
+			ExpectedVersion: &expectedVersion,
+		})
+
+		assert.Nil(t, output)
+		assert.EqualError(t, err, "fruit is at version 2, not 1")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.PreconditionFailed)
+		repository.AssertNotCalled(t, "Save", mock.Anything, mock.Anything)
+	})
+
 	t.Run("Fail if repository save fail", func(t *testing.T) {
 		fruitMock, err := entity.NewFruit("fruit", "owner", 1, 10.0)
 		assert.Nil(t, err)
 
 		repository := &mocks.FruitRepositoryMock{}
-		repository.On("Get", mock.Anything, mock.Anything).Return(fruitMock, nil)
+		repository.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(fruitMock, nil)
 		repository.On("Save", mock.Anything, mock.Anything).Return(errors.New("repository save fail"))
-		u := usecase.NewUpdateFruitUseCase(repository)
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
 
 		output, err := u.Execute(context.Background(), &usecase.UpdateFruitUseCaseInputDTO{
 			ID:       fruitMock.ID,
@@ -88,15 +133,17 @@ func TestUpdateFruitUseCase_Execute(t *testing.T) {
 		assert.Nil(t, err)
 
 		repository := &mocks.FruitRepositoryMock{}
-		repository.On("Get", mock.Anything, mock.Anything).Return(&fruitMockCopy, nil)
+		repository.On("Get", mock.Anything, tenant.Default, mock.Anything).Return(&fruitMockCopy, nil)
 		repository.On("Save", mock.Anything, mock.Anything).Return(nil)
 
-		u := usecase.NewUpdateFruitUseCase(repository)
+		u := usecase.NewUpdateFruitUseCase(repository, allowed())
 
+		expectedVersion := fruitMock.Version
 		input := &usecase.UpdateFruitUseCaseInputDTO{
-			ID:       fruitMock.ID,
-			Quantity: 100,
-			Price:    100.0,
+			ID:              fruitMock.ID,
+			Quantity:        100,
+			Price:           100.0,
+			ExpectedVersion: &expectedVersion,
 		}
 
 		output, err := u.Execute(context.Background(), input)
@@ -111,6 +158,6 @@ func TestUpdateFruitUseCase_Execute(t *testing.T) {
 		assert.Equal(t, output.Owner, fruitMock.Owner)
 		assert.Equal(t, output.Quantity, 100)
 		assert.Equal(t, output.Price, 100.0)
-		assert.Equal(t, output.Status, fruitMock.Status)
+		assert.Equal(t, output.Status, string(fruitMock.Status))
 	})
 }
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+const APIKeyHeader = "X-API-Key"
+
+type APIKey struct {
+	Key     string
+	Subject string
+	Roles   []string
+	Tenant  string
+}
+
+// APIKeyAuthenticator authenticate requests by a static key sent in the X-API-Key header
+type APIKeyAuthenticator struct {
+	// keys indexed by the key sha256, so looking a key up takes the same time whatever its prefix
+	keys map[[sha256.Size]byte]*auth.Principal
+}
+
+func NewAPIKeyAuthenticator(keys []APIKey) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{
+		keys: make(map[[sha256.Size]byte]*auth.Principal, len(keys)),
+	}
+
+	for i, k := range keys {
+		if k.Key == "" || k.Subject == "" {
+			return nil, fmt.Errorf("api key #%d needs a key and a subject", i+1)
+		}
+
+		sum := sha256.Sum256([]byte(k.Key))
+		if _, ok := a.keys[sum]; ok {
+			return nil, fmt.Errorf("api key #%d is duplicated", i+1)
+		}
+
+		a.keys[sum] = &auth.Principal{Subject: k.Subject, Roles: k.Roles, Tenant: k.Tenant, Method: auth.MethodAPIKey}
+	}
+
+	return a, nil
+}
+
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	principal, ok := a.keys[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, domainerror.NewUnauthenticatedError("invalid api key")
+	}
+
+	return principal, nil
+}
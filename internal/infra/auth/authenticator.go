@@ -0,0 +1,28 @@
+package auth
+
+import (
+	"net/http"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+// Authenticator verify the credentials of a request. It returns a nil principal and no error when the request
+// carries none of the credentials it understands, and an Unauthenticated error when they are invalid.
+type Authenticator interface {
+	Authenticate(r *http.Request) (*auth.Principal, error)
+}
+
+// Chain try every authenticator in order, the first one recognizing the request credentials decides
+type Chain []Authenticator
+
+func (ch Chain) Authenticate(r *http.Request) (*auth.Principal, error) {
+	for _, a := range ch {
+		principal, err := a.Authenticate(r)
+		if err != nil || principal != nil {
+			return principal, err
+		}
+	}
+
+	return nil, domainerror.NewUnauthenticatedError("missing credentials")
+}
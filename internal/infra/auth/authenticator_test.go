@@ -0,0 +1,123 @@
+package auth_test
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type authenticatorStub struct {
+	principal *auth.Principal
+	err       error
+}
+
+func (a *authenticatorStub) Authenticate(*http.Request) (*auth.Principal, error) {
+	return a.principal, a.err
+}
+
+func TestChain_Authenticate(t *testing.T) {
+	ruan := &auth.Principal{Subject: "ruan"}
+	skip := &authenticatorStub{}
+
+	t.Run("With recognized credentials", func(t *testing.T) {
+		chain := infraauth.Chain{skip, &authenticatorStub{principal: ruan}, &authenticatorStub{err: domainerror.NewUnauthenticatedError("never reached")}}
+
+		principal, err := chain.Authenticate(httptest.NewRequest("GET", "/fruits", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, principal, ruan)
+	})
+
+	t.Run("With invalid credentials", func(t *testing.T) {
+		chain := infraauth.Chain{&authenticatorStub{err: domainerror.NewUnauthenticatedError("invalid api key")}, &authenticatorStub{principal: ruan}}
+
+		principal, err := chain.Authenticate(httptest.NewRequest("GET", "/fruits", nil))
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, "invalid api key")
+	})
+
+	t.Run("Without credentials", func(t *testing.T) {
+		principal, err := infraauth.Chain{skip}.Authenticate(httptest.NewRequest("GET", "/fruits", nil))
+		assert.Nil(t, principal)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+		assert.EqualError(t, err, "missing credentials")
+	})
+}
+
+func TestAPIKeyAuthenticator(t *testing.T) {
+	a, err := infraauth.NewAPIKeyAuthenticator([]infraauth.APIKey{
+		{Key: "s3cr3t", Subject: "ruan", Roles: []string{auth.RoleAdmin}},
+		{Key: "other", Subject: "clerk", Tenant: "acme"},
+	})
+	assert.Nil(t, err)
+
+	authenticate := func(key string) (*auth.Principal, error) {
+		r := httptest.NewRequest("GET", "/fruits", nil)
+		if key != "" {
+			r.Header.Set(infraauth.APIKeyHeader, key)
+		}
+		return a.Authenticate(r)
+	}
+
+	principal, err := authenticate("s3cr3t")
+	assert.Nil(t, err)
+	assert.Equal(t, principal.Subject, "ruan")
+	assert.True(t, principal.HasRole(auth.RoleAdmin))
+	assert.Equal(t, principal.Method, auth.MethodAPIKey)
+
+	principal, err = authenticate("other")
+	assert.Nil(t, err)
+	assert.Equal(t, principal.Tenant, "acme")
+
+	principal, err = authenticate("")
+	assert.Nil(t, err)
+	assert.Nil(t, principal)
+
+	principal, err = authenticate("s3cr3")
+	assert.Nil(t, principal)
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+
+	_, err = infraauth.NewAPIKeyAuthenticator([]infraauth.APIKey{{Key: "s3cr3t", Subject: "ruan"}, {Key: "s3cr3t", Subject: "clerk"}})
+	assert.EqualError(t, err, "api key #2 is duplicated")
+
+	_, err = infraauth.NewAPIKeyAuthenticator([]infraauth.APIKey{{Key: "s3cr3t"}})
+	assert.EqualError(t, err, "api key #1 needs a key and a subject")
+}
+
+func TestDevAuthenticator(t *testing.T) {
+	a := infraauth.NewDevAuthenticator(true)
+
+	r := httptest.NewRequest("POST", "/fruits", nil)
+	r.Header.Set("x-owner", "ruan")
+	r.Header.Set("x-roles", "clerk, admin")
+
+	principal, err := a.Authenticate(r)
+	assert.Nil(t, err)
+	assert.Equal(t, principal.Subject, "ruan")
+	assert.Equal(t, principal.Roles, []string{"clerk", auth.RoleAdmin})
+	assert.Equal(t, principal.Method, auth.MethodDev)
+
+	r = httptest.NewRequest("POST", "/fruits/some-uuid/restore", nil)
+	r.Header.Set("x-user", "clerk")
+
+	principal, err = a.Authenticate(r)
+	assert.Nil(t, err)
+	assert.Equal(t, principal.Subject, "clerk")
+	assert.Empty(t, principal.Roles)
+
+	t.Run("Without trusted roles", func(t *testing.T) {
+		a := infraauth.NewDevAuthenticator(false)
+
+		r := httptest.NewRequest("DELETE", "/admin/fruits/some-uuid", nil)
+		r.Header.Set("x-owner", "ruan")
+		r.Header.Set("x-roles", "admin")
+
+		principal, err := a.Authenticate(r)
+		assert.Nil(t, err)
+		assert.Equal(t, principal.Subject, "ruan")
+		assert.Empty(t, principal.Roles)
+	})
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+)
+
+// DevAuthenticator trust whoever the request claims to be, for local development with authentication disabled:
+// the subject is taken from the x-owner header, or x-user. Callers have no role, unless trustRoles takes them from
+// the comma separated x-roles header, letting anyone act as an admin.
+type DevAuthenticator struct {
+	trustRoles bool
+}
+
+func NewDevAuthenticator(trustRoles bool) *DevAuthenticator {
+	return &DevAuthenticator{trustRoles: trustRoles}
+}
+
+func (a *DevAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	subject := r.Header.Get("x-owner")
+	if subject == "" {
+		subject = r.Header.Get("x-user")
+	}
+
+	var roles []string
+	if a.trustRoles {
+		for _, role := range strings.Split(r.Header.Get("x-roles"), ",") {
+			if role = strings.TrimSpace(role); role != "" {
+				roles = append(roles, role)
+			}
+		}
+	}
+
+	return &auth.Principal{Subject: subject, Roles: roles, Method: auth.MethodDev}, nil
+}
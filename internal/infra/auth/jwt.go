@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+type JWTOptions struct {
+	// Secret verify HS256 tokens, HS256 is refused when empty
+	Secret []byte
+	// Keys verify RS256 tokens by their kid header, RS256 is refused when empty
+	Keys map[string]*rsa.PublicKey
+	// Issuer and Audience, when set, must match the iss and aud claims
+	Issuer   string
+	Audience string
+}
+
+// JWTAuthenticator authenticate requests by a bearer token signed with HS256 or RS256 and carrying an exp claim,
+// taking the subject from the sub claim and the roles from the roles claim
+type JWTAuthenticator struct {
+	options JWTOptions
+	parser  *jwt.Parser
+}
+
+type tokenClaims struct {
+	jwt.RegisteredClaims
+	Roles  []string `json:"roles,omitempty"`
+	Tenant string   `json:"tenant,omitempty"`
+}
+
+func NewJWTAuthenticator(options JWTOptions) (*JWTAuthenticator, error) {
+	var methods []string
+	if len(options.Secret) > 0 {
+		methods = append(methods, jwt.SigningMethodHS256.Alg())
+	}
+	if len(options.Keys) > 0 {
+		methods = append(methods, jwt.SigningMethodRS256.Alg())
+	}
+
+	if len(methods) == 0 {
+		return nil, errors.New("jwt authentication needs a secret or signing keys")
+	}
+
+	return &JWTAuthenticator{
+		options: options,
+		parser:  jwt.NewParser(jwt.WithValidMethods(methods)),
+	}, nil
+}
+
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*auth.Principal, error) {
+	header := r.Header.Get("Authorization")
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") {
+		return nil, nil
+	}
+
+	claims := &tokenClaims{}
+	if _, err := a.parser.ParseWithClaims(strings.TrimSpace(token), claims, a.key); err != nil {
+		return nil, domainerror.NewUnauthenticatedError("invalid bearer token")
+	}
+
+	// the parser only checks exp when present, a token without one would never expire
+	if claims.ExpiresAt == nil {
+		return nil, domainerror.NewUnauthenticatedError("bearer token has no expiry")
+	}
+
+	if claims.Subject == "" {
+		return nil, domainerror.NewUnauthenticatedError("bearer token has no subject")
+	}
+
+	if a.options.Issuer != "" && !claims.VerifyIssuer(a.options.Issuer, true) {
+		return nil, domainerror.NewUnauthenticatedError("bearer token has an unexpected issuer")
+	}
+
+	if a.options.Audience != "" && !claims.VerifyAudience(a.options.Audience, true) {
+		return nil, domainerror.NewUnauthenticatedError("bearer token has an unexpected audience")
+	}
+
+	return &auth.Principal{Subject: claims.Subject, Roles: claims.Roles, Tenant: claims.Tenant, Method: auth.MethodJWT}, nil
+}
+
+// key pick the key verifying token, the parser has already refused the methods that are not configured
+func (a *JWTAuthenticator) key(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		return a.options.Secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	if key, ok := a.options.Keys[kid]; ok {
+		return key, nil
+	}
+
+	// a token without kid is accepted only when there is no doubt about the key
+	if kid == "" && len(a.options.Keys) == 1 {
+		for _, key := range a.options.Keys {
+			return key, nil
+		}
+	}
+
+	return nil, errors.New("unknown signing key")
+}
@@ -0,0 +1,164 @@
+package auth_test
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/stretchr/testify/assert"
+	"math/big"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var secret = []byte("a-secret-long-enough-for-hs256!!")
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	assert.Nil(t, err)
+
+	return signed
+}
+
+func bearer(a *infraauth.JWTAuthenticator, token string) (*auth.Principal, error) {
+	r := httptest.NewRequest("GET", "/fruits", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	return a.Authenticate(r)
+}
+
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	entries := ""
+	for kid, key := range keys {
+		if entries != "" {
+			entries += ","
+		}
+		entries += fmt.Sprintf(
+			`{"kty":"RSA","use":"sig","alg":"RS256","kid":%q,"n":%q,"e":%q}`,
+			kid,
+			base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		)
+	}
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"keys":[`+entries+`,{"kty":"EC","kid":"ec","crv":"P-256"}]}`), 0o600))
+
+	return path
+}
+
+func TestJWTAuthenticator_HS256(t *testing.T) {
+	a, err := infraauth.NewJWTAuthenticator(infraauth.JWTOptions{Secret: secret, Issuer: "fruits-idp", Audience: "fruits"})
+	assert.Nil(t, err)
+
+	valid := jwt.MapClaims{"sub": "ruan", "roles": []string{auth.RoleAdmin}, "tenant": "acme", "iss": "fruits-idp", "aud": "fruits", "exp": time.Now().Add(time.Hour).Unix()}
+
+	t.Run("With valid token", func(t *testing.T) {
+		principal, err := bearer(a, sign(t, jwt.SigningMethodHS256, secret, "", valid))
+		assert.Nil(t, err)
+		assert.Equal(t, principal.Subject, "ruan")
+		assert.True(t, principal.HasRole(auth.RoleAdmin))
+		assert.Equal(t, principal.Tenant, "acme")
+		assert.Equal(t, principal.Method, auth.MethodJWT)
+	})
+
+	t.Run("Without bearer token", func(t *testing.T) {
+		r := httptest.NewRequest("GET", "/fruits", nil)
+		r.Header.Set("Authorization", "Basic cnVhbjpzM2NyM3Q=")
+
+		principal, err := a.Authenticate(r)
+		assert.Nil(t, err)
+		assert.Nil(t, principal)
+	})
+
+	exp := time.Now().Add(time.Hour).Unix()
+	invalid := map[string]string{
+		"wrong secret":   sign(t, jwt.SigningMethodHS256, []byte("another-secret-long-enough-hs256"), "", valid),
+		"expired":        sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "ruan", "iss": "fruits-idp", "aud": "fruits", "exp": time.Now().Add(-time.Minute).Unix()}),
+		"no subject":     sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"iss": "fruits-idp", "aud": "fruits", "exp": exp}),
+		"wrong issuer":   sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "ruan", "iss": "evil", "aud": "fruits", "exp": exp}),
+		"wrong audience": sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "ruan", "iss": "fruits-idp", "aud": "other", "exp": exp}),
+		"unsigned":       sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", valid),
+		"malformed":      "not.a.token",
+		"unexpected alg": sign(t, jwt.SigningMethodHS512, secret, "", valid),
+		"empty token":    "",
+	}
+
+	for name, token := range invalid {
+		t.Run("With "+name, func(t *testing.T) {
+			principal, err := bearer(a, token)
+			assert.Nil(t, principal)
+			assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+		})
+	}
+	t.Run("Without expiry", func(t *testing.T) {
+		principal, err := bearer(a, sign(t, jwt.SigningMethodHS256, secret, "", jwt.MapClaims{"sub": "ruan", "iss": "fruits-idp", "aud": "fruits"}))
+		assert.Nil(t, principal)
+		assert.EqualError(t, err, "bearer token has no expiry")
+	})
+}
+
+func TestJWTAuthenticator_RS256(t *testing.T) {
+	current, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	previous, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+	unknown, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.Nil(t, err)
+
+	keys, err := infraauth.LoadJWKS(writeJWKS(t, map[string]*rsa.PublicKey{"current": &current.PublicKey, "previous": &previous.PublicKey}))
+	assert.Nil(t, err)
+	assert.Len(t, keys, 2)
+
+	a, err := infraauth.NewJWTAuthenticator(infraauth.JWTOptions{Keys: keys})
+	assert.Nil(t, err)
+
+	claims := jwt.MapClaims{"sub": "ruan", "exp": time.Now().Add(time.Hour).Unix()}
+
+	principal, err := bearer(a, sign(t, jwt.SigningMethodRS256, previous, "previous", claims))
+	assert.Nil(t, err)
+	assert.Equal(t, principal.Subject, "ruan")
+
+	_, err = bearer(a, sign(t, jwt.SigningMethodRS256, current, "previous", claims))
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+
+	_, err = bearer(a, sign(t, jwt.SigningMethodRS256, unknown, "unknown", claims))
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+
+	_, err = bearer(a, sign(t, jwt.SigningMethodRS256, current, "", claims))
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated, "kid is required with several keys")
+
+	// HS256 is not configured, a token signed with the public key as hmac secret must not pass
+	_, err = bearer(a, sign(t, jwt.SigningMethodHS256, current.PublicKey.N.Bytes(), "current", claims))
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Unauthenticated)
+}
+
+func TestLoadJWKS(t *testing.T) {
+	_, err := infraauth.LoadJWKS(filepath.Join(t.TempDir(), "missing.json"))
+	assert.ErrorContains(t, err, "fail to read jwks file")
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"EC","kid":"ec"}]}`), 0o600))
+	_, err = infraauth.LoadJWKS(path)
+	assert.ErrorContains(t, err, "no rsa signing key")
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{"keys":[{"kty":"RSA","kid":"bad","n":"!!","e":"AQAB"}]}`), 0o600))
+	_, err = infraauth.LoadJWKS(path)
+	assert.ErrorContains(t, err, `invalid key "bad"`)
+}
+
+func TestNewJWTAuthenticator_WithoutKeys(t *testing.T) {
+	_, err := infraauth.NewJWTAuthenticator(infraauth.JWTOptions{})
+	assert.EqualError(t, err, "jwt authentication needs a secret or signing keys")
+}
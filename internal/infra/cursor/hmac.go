@@ -0,0 +1,120 @@
+package cursor
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+)
+
+// payload the compact JSON form of a cursor
+type payload struct {
+	Sort      []sortKey `json:"s"`
+	Backward  bool      `json:"b,omitempty"`
+	Name      string    `json:"n"`
+	Price     float64   `json:"p"`
+	Quantity  int       `json:"q"`
+	CreatedAt int64     `json:"c"`
+	UpdatedAt int64     `json:"u"`
+	Tenant    string    `json:"t"`
+	ID        string    `json:"i"`
+}
+
+type sortKey struct {
+	Field      protocol.FruitSortField `json:"f"`
+	Descending bool                    `json:"d,omitempty"`
+}
+
+// HMACCodec encode cursors as base64url JSON signed with HMAC-SHA256, so clients can hold them but not forge them
+type HMACCodec struct {
+	secret []byte
+}
+
+func NewHMACCodec(secret []byte) *HMACCodec {
+	return &HMACCodec{
+		secret: secret,
+	}
+}
+
+func (hc *HMACCodec) Encode(cursor *protocol.FruitSearchCursor) (string, error) {
+	p := payload{
+		Backward:  cursor.Backward,
+		Name:      cursor.Position.Name,
+		Price:     cursor.Position.Price,
+		Quantity:  cursor.Position.Quantity,
+		CreatedAt: cursor.Position.CreatedAt.UnixNano(),
+		UpdatedAt: cursor.Position.UpdatedAt.UnixNano(),
+		Tenant:    cursor.Position.Tenant,
+		ID:        cursor.Position.ID,
+	}
+
+	for _, key := range cursor.Sort {
+		p.Sort = append(p.Sort, sortKey{Field: key.Field, Descending: key.Descending})
+	}
+
+	body, err := json.Marshal(p)
+	if err != nil {
+		return "", domainerror.NewInternalError("fail to encode cursor", err)
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(body)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(hc.sign(encoded)), nil
+}
+
+func (hc *HMACCodec) Decode(token string) (*protocol.FruitSearchCursor, error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, invalidCursorError()
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, hc.sign(encoded)) {
+		return nil, invalidCursorError()
+	}
+
+	body, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, invalidCursorError()
+	}
+
+	var p payload
+	if err := json.Unmarshal(body, &p); err != nil {
+		return nil, invalidCursorError()
+	}
+
+	cursor := &protocol.FruitSearchCursor{
+		Backward: p.Backward,
+		Position: protocol.FruitSearchPosition{
+			Name:      p.Name,
+			Price:     p.Price,
+			Quantity:  p.Quantity,
+			CreatedAt: time.Unix(0, p.CreatedAt),
+			UpdatedAt: time.Unix(0, p.UpdatedAt),
+			Tenant:    p.Tenant,
+			ID:        p.ID,
+		},
+	}
+
+	for _, key := range p.Sort {
+		cursor.Sort = append(cursor.Sort, protocol.FruitSortKey{Field: key.Field, Descending: key.Descending})
+	}
+
+	return cursor, nil
+}
+
+func (hc *HMACCodec) sign(encoded string) []byte {
+	mac := hmac.New(sha256.New, hc.secret)
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+func invalidCursorError() error {
+	var violations domainerror.Violations
+	violations.Add("cursor", "invalid cursor")
+	return violations.Err()
+}
@@ -0,0 +1,59 @@
+package cursor_test
+
+import (
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/cursor"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHMACCodec(t *testing.T) {
+	codec := cursor.NewHMACCodec([]byte(strings.Repeat("s", 32)))
+	taken := &protocol.FruitSearchCursor{
+		Sort: []protocol.FruitSortKey{{Field: protocol.SortByPrice, Descending: true}, {Field: protocol.SortByName}},
+		Position: protocol.FruitSearchPosition{
+			Name:      "uva",
+			Price:     10.5,
+			Quantity:  3,
+			CreatedAt: time.Date(2022, time.December, 1, 10, 30, 0, 42, time.UTC),
+			UpdatedAt: time.Date(2022, time.December, 2, 10, 30, 0, 0, time.UTC),
+			Tenant:    "acme",
+			ID:        "uva-id",
+		},
+		Backward: true,
+	}
+
+	t.Run("Round trip", func(t *testing.T) {
+		token, err := codec.Encode(taken)
+		assert.Nil(t, err)
+
+		decoded, err := codec.Decode(token)
+		assert.Nil(t, err)
+		assert.Equal(t, decoded.Sort, taken.Sort)
+		assert.Equal(t, decoded.Backward, taken.Backward)
+		assert.Equal(t, decoded.Position.ID, taken.Position.ID)
+		assert.Equal(t, decoded.Position.Tenant, taken.Position.Tenant)
+		assert.Equal(t, decoded.Position.Price, taken.Position.Price)
+		assert.True(t, decoded.Position.CreatedAt.Equal(taken.Position.CreatedAt))
+		assert.True(t, decoded.Position.UpdatedAt.Equal(taken.Position.UpdatedAt))
+	})
+
+	t.Run("Refuse forged tokens", func(t *testing.T) {
+		token, err := codec.Encode(taken)
+		assert.Nil(t, err)
+		encoded, signature, _ := strings.Cut(token, ".")
+
+		other, err := cursor.NewHMACCodec([]byte(strings.Repeat("o", 32))).Encode(taken)
+		assert.Nil(t, err)
+
+		for _, forged := range []string{"", "garbage", encoded, encoded + "x." + signature, "e30." + signature, other} {
+			decoded, err := codec.Decode(forged)
+			assert.Nil(t, decoded)
+			assert.EqualError(t, err, "invalid cursor")
+			assert.Equal(t, domainerror.KindOf(err), domainerror.Validation)
+		}
+	})
+}
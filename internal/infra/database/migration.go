@@ -0,0 +1,99 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type Migration struct {
+	Version     int
+	Description string
+	Statements  []string
+}
+
+const createMigrationsTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	version     INTEGER NOT NULL PRIMARY KEY,
+	description TEXT    NOT NULL,
+	applied_at  INTEGER NOT NULL
+)`
+
+// Migrate apply every migration with a version greater than the current schema version,
+// each one inside its own transaction, recording it in the schema_migrations table
+func Migrate(ctx context.Context, db *sql.DB, migrations []Migration) error {
+	pending := make([]Migration, len(migrations))
+	copy(pending, migrations)
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Version < pending[j].Version
+	})
+
+	for i, m := range pending {
+		if m.Version <= 0 {
+			return fmt.Errorf("invalid migration version %d", m.Version)
+		}
+		if i > 0 && pending[i-1].Version == m.Version {
+			return fmt.Errorf("duplicated migration version %d", m.Version)
+		}
+	}
+
+	if _, err := db.ExecContext(ctx, createMigrationsTable); err != nil {
+		return fmt.Errorf("fail to create schema_migrations table: %w", err)
+	}
+
+	current, err := CurrentVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range pending {
+		if m.Version <= current {
+			continue
+		}
+
+		if err := apply(ctx, db, m); err != nil {
+			return fmt.Errorf("fail to apply migration %d (%s): %w", m.Version, m.Description, err)
+		}
+	}
+
+	return nil
+}
+
+// CurrentVersion return the greatest applied migration version, zero when none was applied
+func CurrentVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var version sql.NullInt64
+
+	err := db.QueryRowContext(ctx, "SELECT MAX(version) FROM schema_migrations").Scan(&version)
+	if err != nil {
+		return 0, fmt.Errorf("fail to read schema version: %w", err)
+	}
+
+	return int(version.Int64), nil
+}
+
+func apply(ctx context.Context, db *sql.DB, m Migration) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, statement := range m.Statements {
+		if _, err := tx.ExecContext(ctx, statement); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		"INSERT INTO schema_migrations (version, description, applied_at) VALUES (?, ?, ?)",
+		m.Version, m.Description, time.Now().UnixNano(),
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,117 @@
+package database_test
+
+import (
+	"context"
+	"database/sql"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/database"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func openMemoryDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", ":memory:")
+	assert.Nil(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	migrations := []database.Migration{
+		{
+			Version:     2,
+			Description: "add color",
+			Statements:  []string{"ALTER TABLE things ADD COLUMN color TEXT"},
+		},
+		{
+			Version:     1,
+			Description: "create things",
+			Statements:  []string{"CREATE TABLE things (id TEXT PRIMARY KEY)"},
+		},
+	}
+
+	t.Run("Apply pending migrations in version order", func(t *testing.T) {
+		db := openMemoryDB(t)
+
+		err := database.Migrate(context.Background(), db, migrations)
+		assert.Nil(t, err)
+
+		version, err := database.CurrentVersion(context.Background(), db)
+		assert.Nil(t, err)
+		assert.Equal(t, version, 2)
+
+		_, err = db.Exec("INSERT INTO things (id, color) VALUES ('a', 'red')")
+		assert.Nil(t, err)
+	})
+
+	t.Run("Skip already applied migrations", func(t *testing.T) {
+		db := openMemoryDB(t)
+
+		err := database.Migrate(context.Background(), db, migrations[1:])
+		assert.Nil(t, err)
+
+		err = database.Migrate(context.Background(), db, migrations)
+		assert.Nil(t, err)
+
+		var applied int
+		err = db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied)
+		assert.Nil(t, err)
+		assert.Equal(t, applied, 2)
+	})
+
+	t.Run("Rollback failed migration", func(t *testing.T) {
+		db := openMemoryDB(t)
+
+		err := database.Migrate(context.Background(), db, []database.Migration{
+			migrations[1],
+			{Version: 2, Description: "broken", Statements: []string{"CREATE TABLE broken (id TEXT)", "INVALID SQL"}},
+		})
+		assert.Error(t, err)
+
+		version, err := database.CurrentVersion(context.Background(), db)
+		assert.Nil(t, err)
+		assert.Equal(t, version, 1)
+
+		_, err = db.Exec("SELECT * FROM broken")
+		assert.Error(t, err)
+	})
+
+	t.Run("With duplicated versions", func(t *testing.T) {
+		db := openMemoryDB(t)
+
+		err := database.Migrate(context.Background(), db, []database.Migration{migrations[1], migrations[1]})
+		assert.EqualError(t, err, "duplicated migration version 1")
+	})
+}
+
+func TestOpenSQLite(t *testing.T) {
+	db, err := database.OpenSQLite(context.Background(), ":memory:")
+	assert.Nil(t, err)
+	defer db.Close()
+
+	version, err := database.CurrentVersion(context.Background(), db)
+	assert.Nil(t, err)
+	assert.Equal(t, version, len(database.SQLiteMigrations))
+}
+
+func TestSQLiteMigrations_PartitionByTenant(t *testing.T) {
+	db := openMemoryDB(t)
+	ctx := context.Background()
+
+	assert.Nil(t, database.Migrate(ctx, db, database.SQLiteMigrations[:5]))
+	_, err := db.ExecContext(ctx, "INSERT INTO fruits (id, created_at, updated_at, name, quantity, price, owner, status) VALUES ('banana-id', 1, 1, 'banana', 1, 10, 'ruan', 'comestible')")
+	assert.Nil(t, err)
+
+	assert.Nil(t, database.Migrate(ctx, db, database.SQLiteMigrations))
+
+	var tenant string
+	var version int
+	err = db.QueryRowContext(ctx, "SELECT tenant, version FROM fruits WHERE id = 'banana-id'").Scan(&tenant, &version)
+	assert.Nil(t, err)
+	assert.Equal(t, tenant, "default")
+	assert.Equal(t, version, 1)
+
+	// the same id may now live in another tenant
+	_, err = db.ExecContext(ctx, "INSERT INTO fruits (tenant, id, created_at, updated_at, name, quantity, price, owner, status) VALUES ('acme', 'banana-id', 1, 1, 'banana', 1, 10, 'ruan', 'comestible')")
+	assert.Nil(t, err)
+}
@@ -0,0 +1,116 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLiteMigrations the versioned schema of the fruits database, append new versions, never edit applied ones
+var SQLiteMigrations = []Migration{
+	{
+		Version:     1,
+		Description: "create fruits table",
+		Statements: []string{
+			`CREATE TABLE fruits (
+				id         TEXT    NOT NULL PRIMARY KEY,
+				created_at INTEGER NOT NULL,
+				updated_at INTEGER NOT NULL,
+				name       TEXT    NOT NULL,
+				quantity   INTEGER NOT NULL,
+				price      REAL    NOT NULL,
+				owner      TEXT    NOT NULL,
+				status     TEXT    NOT NULL
+			)`,
+		},
+	},
+	{
+		Version:     2,
+		Description: "index fruits by status and owner",
+		Statements: []string{
+			"CREATE INDEX idx_fruits_status ON fruits (status)",
+			"CREATE INDEX idx_fruits_owner ON fruits (owner)",
+		},
+	},
+	{
+		Version:     3,
+		Description: "track fruit restores",
+		Statements: []string{
+			"ALTER TABLE fruits ADD COLUMN previous_status TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE fruits ADD COLUMN restored_by TEXT NOT NULL DEFAULT ''",
+			"ALTER TABLE fruits ADD COLUMN restored_at INTEGER",
+		},
+	},
+	{
+		Version:     4,
+		Description: "track fruit soft deletes",
+		Statements: []string{
+			"ALTER TABLE fruits ADD COLUMN deleted_at INTEGER",
+			"CREATE INDEX idx_fruits_deleted_at ON fruits (deleted_at)",
+		},
+	},
+	{
+		Version:     5,
+		Description: "version fruits for optimistic locking",
+		Statements: []string{
+			"ALTER TABLE fruits ADD COLUMN version INTEGER NOT NULL DEFAULT 1",
+		},
+	},
+	{
+		Version:     6,
+		Description: "partition fruits by tenant",
+		// sqlite can't change a primary key in place, the table is rebuilt keeping the rows order
+		Statements: []string{
+			`CREATE TABLE fruits_by_tenant (
+				tenant          TEXT    NOT NULL DEFAULT 'default',
+				id              TEXT    NOT NULL,
+				created_at      INTEGER NOT NULL,
+				updated_at      INTEGER NOT NULL,
+				name            TEXT    NOT NULL,
+				quantity        INTEGER NOT NULL,
+				price           REAL    NOT NULL,
+				owner           TEXT    NOT NULL,
+				status          TEXT    NOT NULL,
+				previous_status TEXT    NOT NULL DEFAULT '',
+				restored_by     TEXT    NOT NULL DEFAULT '',
+				restored_at     INTEGER,
+				deleted_at      INTEGER,
+				version         INTEGER NOT NULL DEFAULT 1,
+				PRIMARY KEY (tenant, id)
+			)`,
+			`INSERT INTO fruits_by_tenant (id, created_at, updated_at, name, quantity, price, owner, status, previous_status, restored_by, restored_at, deleted_at, version)
+				SELECT id, created_at, updated_at, name, quantity, price, owner, status, previous_status, restored_by, restored_at, deleted_at, version
+				FROM fruits ORDER BY rowid`,
+			"DROP TABLE fruits",
+			"ALTER TABLE fruits_by_tenant RENAME TO fruits",
+			"CREATE INDEX idx_fruits_status ON fruits (tenant, status)",
+			"CREATE INDEX idx_fruits_owner ON fruits (tenant, owner)",
+			"CREATE INDEX idx_fruits_deleted_at ON fruits (deleted_at)",
+		},
+	},
+}
+
+// OpenSQLite open the sqlite database pointed by dsn and migrate it to the latest schema version
+func OpenSQLite(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("fail to open sqlite database: %w", err)
+	}
+
+	// sqlite allows a single writer, and each connection to ":memory:" is a brand-new database
+	db.SetMaxOpenConns(1)
+
+	if err := db.PingContext(ctx); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("fail to connect to sqlite database: %w", err)
+	}
+
+	if err := Migrate(ctx, db, SQLiteMigrations); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
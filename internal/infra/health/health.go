@@ -0,0 +1,143 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusUp   Status = "up"
+	StatusDown Status = "down"
+)
+
+// Check report whether a dependency is usable, it must give up once ctx is done
+type Check func(ctx context.Context) error
+
+type ComponentReport struct {
+	Status   Status `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+type Report struct {
+	Status     Status                     `json:"status"`
+	Draining   bool                       `json:"draining,omitempty"`
+	Components map[string]ComponentReport `json:"components"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker the registry of the dependencies the api needs to serve traffic, safe for concurrent use
+type Checker struct {
+	timeout  time.Duration
+	draining atomic.Bool
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// NewChecker build a registry giving every check up to timeout to answer
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{
+		timeout: timeout,
+	}
+}
+
+// Register add a dependency checked on every readiness probe, under name
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+	sort.Slice(c.checks, func(i, j int) bool {
+		return c.checks[i].name < c.checks[j].name
+	})
+}
+
+// Drain flag the server as shutting down, readiness fails from now on so no new traffic is routed to it
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready run every registered check concurrently. The report is down when the server is draining or any check fails.
+func (c *Checker) Ready(ctx context.Context) *Report {
+	c.mu.RLock()
+	checks := c.checks
+	c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	components := make([]ComponentReport, len(checks))
+
+	var wg sync.WaitGroup
+	for i, nc := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			components[i] = run(ctx, check)
+		}(i, nc.check)
+	}
+	wg.Wait()
+
+	report := &Report{
+		Status:     StatusUp,
+		Draining:   c.draining.Load(),
+		Components: make(map[string]ComponentReport, len(checks)),
+	}
+
+	if report.Draining {
+		report.Status = StatusDown
+	}
+
+	for i, nc := range checks {
+		report.Components[nc.name] = components[i]
+		if components[i].Status == StatusDown {
+			report.Status = StatusDown
+		}
+	}
+
+	return report
+}
+
+// run call check, giving up on it once ctx is done even if it ignores ctx
+func run(ctx context.Context, check Check) ComponentReport {
+	start := time.Now()
+
+	result := make(chan error, 1)
+	go func() {
+		result <- check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = errors.New("check timed out")
+	}
+
+	report := ComponentReport{
+		Status:   StatusUp,
+		Duration: time.Since(start).String(),
+	}
+
+	if err != nil {
+		report.Status = StatusDown
+		report.Error = err.Error()
+	}
+
+	return report
+}
@@ -0,0 +1,78 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/health"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func up(context.Context) error {
+	return nil
+}
+
+func TestChecker_Ready(t *testing.T) {
+	t.Run("Without checks", func(t *testing.T) {
+		report := health.NewChecker(time.Second).Ready(context.Background())
+
+		assert.Equal(t, report.Status, health.StatusUp)
+		assert.Empty(t, report.Components)
+	})
+
+	t.Run("With every check up", func(t *testing.T) {
+		c := health.NewChecker(time.Second)
+		c.Register("repository", up)
+		c.Register("cache", up)
+
+		report := c.Ready(context.Background())
+
+		assert.Equal(t, report.Status, health.StatusUp)
+		assert.Len(t, report.Components, 2)
+		assert.Equal(t, report.Components["repository"].Status, health.StatusUp)
+		assert.Empty(t, report.Components["repository"].Error)
+	})
+
+	t.Run("With failing check", func(t *testing.T) {
+		c := health.NewChecker(time.Second)
+		c.Register("repository", func(context.Context) error {
+			return errors.New("database is locked")
+		})
+		c.Register("cache", up)
+
+		report := c.Ready(context.Background())
+
+		assert.Equal(t, report.Status, health.StatusDown)
+		assert.Equal(t, report.Components["repository"].Status, health.StatusDown)
+		assert.Equal(t, report.Components["repository"].Error, "database is locked")
+		assert.Equal(t, report.Components["cache"].Status, health.StatusUp)
+	})
+
+	t.Run("With check ignoring the timeout", func(t *testing.T) {
+		c := health.NewChecker(20 * time.Millisecond)
+		c.Register("repository", func(context.Context) error {
+			time.Sleep(time.Second)
+			return nil
+		})
+
+		start := time.Now()
+		report := c.Ready(context.Background())
+
+		assert.Less(t, time.Since(start), 500*time.Millisecond)
+		assert.Equal(t, report.Status, health.StatusDown)
+		assert.Equal(t, report.Components["repository"].Error, "check timed out")
+	})
+
+	t.Run("While draining", func(t *testing.T) {
+		c := health.NewChecker(time.Second)
+		c.Register("repository", up)
+		c.Drain()
+
+		report := c.Ready(context.Background())
+
+		assert.Equal(t, report.Status, health.StatusDown)
+		assert.True(t, report.Draining)
+		assert.Equal(t, report.Components["repository"].Status, health.StatusUp)
+	})
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+)
+
+// countTimeout bound the repository query run on every scrape
+const countTimeout = 5 * time.Second
+
+// FruitStatusCollector report the number of fruits per status, counted by the repository at scrape time
+type FruitStatusCollector struct {
+	counter protocol.FruitStatusCounter
+	desc    *prometheus.Desc
+}
+
+func NewFruitStatusCollector(counter protocol.FruitStatusCounter) *FruitStatusCollector {
+	return &FruitStatusCollector{
+		counter: counter,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "by_status"),
+			"Fruits in the repository, by status.",
+			[]string{"status"},
+			nil,
+		),
+	}
+}
+
+func (fsc *FruitStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- fsc.desc
+}
+
+func (fsc *FruitStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), countTimeout)
+	defer cancel()
+
+	counts, err := fsc.counter.CountByStatus(ctx)
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(fsc.desc, err)
+		return
+	}
+
+	// every status is reported, so a status without fruits reads 0 instead of vanishing
+	for _, status := range entity.FruitStatuses {
+		ch <- prometheus.MustNewConstMetric(fsc.desc, prometheus.GaugeValue, float64(counts[status]), string(status))
+	}
+}
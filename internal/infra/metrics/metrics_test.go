@@ -0,0 +1,103 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/metrics"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type useCaseStub struct {
+	err error
+}
+
+func (u *useCaseStub) Execute(_ context.Context, input string) (string, error) {
+	return strings.ToUpper(input), u.err
+}
+
+type statusCounterStub struct {
+	counts map[entity.FruitStatus]int
+	err    error
+}
+
+func (s *statusCounterStub) CountByStatus(_ context.Context) (map[entity.FruitStatus]int, error) {
+	return s.counts, s.err
+}
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	rr := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, rr.Code, http.StatusOK)
+
+	return rr.Body.String()
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	m := metrics.New()
+
+	r := gin.New()
+	r.Use(m.Middleware())
+	r.GET("/fruits/:id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	for _, path := range []string{"/fruits/a", "/fruits/b", "/unknown"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `fruits_http_requests_total{method="GET",route="/fruits/:id",status="200"} 2`)
+	assert.Contains(t, body, `fruits_http_requests_total{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, body, `fruits_http_request_duration_seconds_count{method="GET",route="/fruits/:id"} 2`)
+}
+
+func TestInstrumentUseCase(t *testing.T) {
+	m := metrics.New()
+
+	ok := metrics.InstrumentUseCase[string, string](m, "get_fruit", &useCaseStub{})
+	output, err := ok.Execute(context.Background(), "uva")
+	assert.Nil(t, err)
+	assert.Equal(t, output, "UVA")
+
+	failing := metrics.InstrumentUseCase[string, string](m, "update_fruit", &useCaseStub{err: domainerror.NewNotFoundError("fruit not found")})
+	_, err = failing.Execute(context.Background(), "uva")
+	assert.EqualError(t, err, "fruit not found")
+	_, _ = failing.Execute(context.Background(), "uva")
+
+	body := scrape(t, m)
+	assert.Contains(t, body, `fruits_usecase_executions_total{use_case="get_fruit"} 1`)
+	assert.Contains(t, body, `fruits_usecase_executions_total{use_case="update_fruit"} 2`)
+	assert.Contains(t, body, `fruits_usecase_errors_total{kind="not_found",use_case="update_fruit"} 2`)
+	assert.NotContains(t, body, `fruits_usecase_errors_total{kind="not_found",use_case="get_fruit"}`)
+	assert.Contains(t, body, `fruits_usecase_duration_seconds_count{use_case="get_fruit"} 1`)
+}
+
+func TestFruitStatusCollector(t *testing.T) {
+	t.Run("Report every status", func(t *testing.T) {
+		m := metrics.New()
+		m.Register(metrics.NewFruitStatusCollector(&statusCounterStub{
+			counts: map[entity.FruitStatus]int{entity.StatusComestible: 3, entity.StatusSold: 1},
+		}))
+
+		body := scrape(t, m)
+		assert.Contains(t, body, `fruits_by_status{status="comestible"} 3`)
+		assert.Contains(t, body, `fruits_by_status{status="sold"} 1`)
+		assert.Contains(t, body, `fruits_by_status{status="podrido"} 0`)
+	})
+
+	t.Run("With repository fail", func(t *testing.T) {
+		m := metrics.New()
+		m.Register(metrics.NewFruitStatusCollector(&statusCounterStub{err: errors.New("database is locked")}))
+
+		body := scrape(t, m)
+		assert.NotContains(t, body, "fruits_by_status{")
+		assert.Contains(t, body, "go_goroutines")
+	})
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+)
+
+type instrumentedUseCase[I any, O any] struct {
+	metrics *Metrics
+	name    string
+	next    protocol.UseCase[I, O]
+}
+
+// InstrumentUseCase decorate u so every execution is counted and timed under name,
+// failures are also counted by domain error kind
+func InstrumentUseCase[I any, O any](m *Metrics, name string, u protocol.UseCase[I, O]) protocol.UseCase[I, O] {
+	return &instrumentedUseCase[I, O]{
+		metrics: m,
+		name:    name,
+		next:    u,
+	}
+}
+
+func (iu *instrumentedUseCase[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	start := time.Now()
+
+	output, err := iu.next.Execute(ctx, input)
+
+	iu.metrics.useCaseExecutions.WithLabelValues(iu.name).Inc()
+	iu.metrics.useCaseDuration.WithLabelValues(iu.name).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		iu.metrics.useCaseErrors.WithLabelValues(iu.name, string(domainerror.KindOf(err))).Inc()
+	}
+
+	return output, err
+}
@@ -0,0 +1,10 @@
+package repository
+
+import (
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+// newStaleFruitError someone else saved the fruit since it was read
+func newStaleFruitError() error {
+	return domainerror.NewConflictError("fruit has been modified by another request")
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"sort"
+)
+
+// fuzzyMatch a fruit found by a fuzzy name search, with its relevance
+type fuzzyMatch struct {
+	fruit *entity.Fruit
+	score float64
+}
+
+// isFuzzy whether filter matches names fuzzily, an empty name filters nothing whatever the match
+func isFuzzy(filter *protocol.FruitSearchFilter) bool {
+	return filter.Match == protocol.MatchFuzzy && filter.Name != ""
+}
+
+// fuzzyPage order matches by filter.Sort, or by decreasing relevance without sort keys, and cut the requested page out.
+// The repositories share it since relevance is computed in process whatever the store.
+func fuzzyPage(matches []fuzzyMatch, filter *protocol.FruitSearchFilter, offset int, limit int) *protocol.FruitSearchResult {
+	sort.Slice(matches, func(i, j int) bool {
+		if len(filter.Sort) == 0 && matches[i].score != matches[j].score {
+			return matches[i].score > matches[j].score
+		}
+
+		return compareFruits(matches[i].fruit, matches[j].fruit, filter.Sort) < 0
+	})
+
+	start, end := window(len(matches), func(i int) *entity.Fruit { return matches[i].fruit }, filter, offset, limit)
+
+	result := &protocol.FruitSearchResult{
+		Paging: &protocol.FruitSearchResultPaging{
+			Total:  total(len(matches), filter),
+			Offset: offset,
+			Limit:  limit,
+		},
+		HasMore: hasMore(len(matches), start, end, filter),
+	}
+
+	for _, match := range matches[start:end] {
+		result.Results = append(result.Results, match.fruit)
+		result.Scores = append(result.Scores, match.score)
+	}
+
+	return result
+}
@@ -2,75 +2,556 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/search"
+	"sort"
 	"strings"
+	"sync"
+	"time"
 )
 
+// memoryRecord a stored fruit plus its insertion sequence, used to keep search results in insertion order
+type memoryRecord struct {
+	seq   uint64
+	fruit *entity.Fruit
+}
+
+// fruitKey identify a stored fruit, ids are only unique within a tenant
+type fruitKey struct {
+	tenant string
+	id     string
+}
+
+// scoped an index key confined to a tenant
+type scoped[K comparable] struct {
+	tenant string
+	key    K
+}
+
+// keySet a set of fruit keys used by the secondary indexes
+type keySet map[fruitKey]struct{}
+
+// FruitMemoryRepository keep fruits in memory, partitioned by tenant, safe for concurrent use.
+// Fruits are copied on the way in and out, so callers never share state with the store.
 type FruitMemoryRepository struct {
-	fruits []*entity.Fruit
+	mu       sync.RWMutex
+	seq      uint64
+	fruits   map[fruitKey]*memoryRecord
+	byTenant map[string]keySet
+	byStatus map[scoped[entity.FruitStatus]]keySet
+	byOwner  map[scoped[string]]keySet
+	// names the words of every fruit name, across tenants, for fuzzy searches
+	names *search.Index[fruitKey]
 }
 
 func NewFruitMemoryRepository() *FruitMemoryRepository {
 	return &FruitMemoryRepository{
-		fruits: []*entity.Fruit{},
+		fruits:   map[fruitKey]*memoryRecord{},
+		byTenant: map[string]keySet{},
+		byStatus: map[scoped[entity.FruitStatus]]keySet{},
+		byOwner:  map[scoped[string]]keySet{},
+		names:    search.NewIndex[fruitKey](),
 	}
 }
 
+// Save insert or update fruit, refusing to overwrite a version other than the one fruit was read at.
+// On success fruit.Version is bumped to the stored version.
 func (fmr *FruitMemoryRepository) Save(_ context.Context, fruit *entity.Fruit) error {
-	for index, f := range fmr.fruits {
-		if f.ID == fruit.ID {
-			fmr.fruits[index] = fruit
-			return nil
-		}
+	fmr.mu.Lock()
+	defer fmr.mu.Unlock()
+
+	key := keyOf(fruit)
+	record, ok := fmr.fruits[key]
+	if err := checkVersion(fruit, ok, record); err != nil {
+		return err
 	}
-	fmr.fruits = append(fmr.fruits, fruit)
+
+	fruit.Version++
+	stored := cloneFruit(fruit)
+
+	if ok {
+		fmr.unindex(record.fruit)
+		record.fruit = stored
+		fmr.index(stored)
+		return nil
+	}
+
+	fmr.seq++
+	fmr.fruits[key] = &memoryRecord{seq: fmr.seq, fruit: stored}
+	fmr.index(stored)
 
 	return nil
 }
 
-func (fmr *FruitMemoryRepository) Get(_ context.Context, id string) (*entity.Fruit, error) {
-	for _, f := range fmr.fruits {
-		if f.ID == id {
-			return f, nil
-		}
+func (fmr *FruitMemoryRepository) Get(_ context.Context, tenant string, id string) (*entity.Fruit, error) {
+	fmr.mu.RLock()
+	defer fmr.mu.RUnlock()
+
+	record, ok := fmr.fruits[fruitKey{tenant: tenant, id: id}]
+	if !ok {
+		return nil, domainerror.NewNotFoundError("fruit not found")
+	}
+
+	return cloneFruit(record.fruit), nil
+}
+
+// Delete permanently remove the fruit, refusing to remove a version other than the one it was read at
+func (fmr *FruitMemoryRepository) Delete(_ context.Context, tenant string, id string, version int) error {
+	fmr.mu.Lock()
+	defer fmr.mu.Unlock()
+
+	key := fruitKey{tenant: tenant, id: id}
+	record, ok := fmr.fruits[key]
+	if !ok {
+		return domainerror.NewNotFoundError("fruit not found")
+	}
+
+	if record.fruit.Version != version {
+		return newStaleFruitError()
 	}
 
-	return nil, errors.New("fruit not found")
+	fmr.unindex(record.fruit)
+	delete(fmr.fruits, key)
+
+	return nil
 }
 
 func (fmr *FruitMemoryRepository) Search(_ context.Context, filter *protocol.FruitSearchFilter, offset int, limit int) (*protocol.FruitSearchResult, error) {
-	var founds []*entity.Fruit
+	fmr.mu.RLock()
+	defer fmr.mu.RUnlock()
+
+	var founds []*memoryRecord
 	var results []*entity.Fruit
 
-	for _, f := range fmr.fruits {
-		if strings.Contains(strings.ToLower(f.Name), strings.ToLower(filter.Name)) && f.Status == filter.Status {
-			founds = append(founds, f)
+	var scores map[fruitKey]float64
+	var scored keySet
+	if isFuzzy(filter) {
+		scores = fmr.names.Search(filter.Name)
+		scored = make(keySet, len(scores))
+		for key := range scores {
+			scored[key] = struct{}{}
 		}
 	}
 
-	if len(founds) > 0 {
-		start := (offset - 1) * limit
-		end := offset * limit
+	found := func(key fruitKey, record *memoryRecord) bool {
+		_, ok := scores[key]
+		return (scores == nil || ok) && matches(record.fruit, filter)
+	}
+
+	// cursor pages only hold the fruits past the cursor, sorting out all but the page and the one after it whenever
+	// twice as many are held, rather than every match
+	n := 0
+	collect := func(record *memoryRecord) {
+		founds = append(founds, record)
+	}
+
+	var past cursorOrder
+	if filter.Cursor != nil && scores == nil {
+		past = pastCursor(filter.Cursor, filter.Sort)
+		collect = func(record *memoryRecord) {
+			n++
+			if !past.after(record.fruit) {
+				return
+			}
+
+			founds = append(founds, record)
+			if len(founds) == 2*(limit+1) {
+				founds = past.first(founds, limit+1)
+			}
+		}
+	}
 
-		if start > len(founds) {
-			start = len(founds)
+	if filter.AllTenants {
+		for key, record := range fmr.fruits {
+			if found(key, record) {
+				collect(record)
+			}
 		}
+	} else {
+		for _, keys := range fmr.candidates(filter, scored) {
+			for key := range keys {
+				if record := fmr.fruits[key]; found(key, record) {
+					collect(record)
+				}
+			}
+		}
+	}
+
+	if scores != nil {
+		fuzzyMatches := make([]fuzzyMatch, len(founds))
+		for i, record := range founds {
+			fuzzyMatches[i] = fuzzyMatch{fruit: record.fruit, score: scores[keyOf(record.fruit)]}
+		}
+
+		result := fuzzyPage(fuzzyMatches, filter, offset, limit)
+		for i, fruit := range result.Results {
+			result.Results[i] = cloneFruit(fruit)
+		}
+
+		return result, nil
+	}
+
+	if filter.Cursor != nil {
+		return cursorPage(past.first(founds, limit+1), n, filter, offset, limit), nil
+	}
 
-		if end > len(founds) {
-			end = len(founds)
+	sort.Slice(founds, func(i, j int) bool {
+		if len(filter.Sort) == 0 {
+			return founds[i].seq < founds[j].seq
 		}
 
-		results = founds[start:end]
+		return compareFruits(founds[i].fruit, founds[j].fruit, filter.Sort) < 0
+	})
+
+	start, end := window(len(founds), func(i int) *entity.Fruit { return founds[i].fruit }, filter, offset, limit)
+	for _, record := range founds[start:end] {
+		results = append(results, cloneFruit(record.fruit))
 	}
 
 	return &protocol.FruitSearchResult{
 		Paging: &protocol.FruitSearchResultPaging{
-			Total:  len(founds),
+			Total:  total(len(founds), filter),
 			Offset: offset,
 			Limit:  limit,
 		},
 		Results: results,
+		HasMore: hasMore(len(founds), start, end, filter),
 	}, nil
 }
+
+// cursorPage the page of the fruits founds holds from the cursor on, at most one more than the limit, n fruits
+// matching the filter in all
+func cursorPage(founds []*memoryRecord, n int, filter *protocol.FruitSearchFilter, offset int, limit int) *protocol.FruitSearchResult {
+	result := &protocol.FruitSearchResult{
+		Paging: &protocol.FruitSearchResultPaging{
+			Total:  total(n, filter),
+			Offset: offset,
+			Limit:  limit,
+		},
+		HasMore: len(founds) > limit,
+	}
+
+	if result.HasMore {
+		founds = founds[:limit]
+	}
+
+	for _, record := range founds {
+		result.Results = append(result.Results, cloneFruit(record.fruit))
+	}
+
+	if filter.Cursor.Backward {
+		reverse(result.Results)
+	}
+
+	return result
+}
+
+// cursorOrder the fruits in the direction a cursor pages, starting from its position
+type cursorOrder struct {
+	position  *entity.Fruit
+	sort      []protocol.FruitSortKey
+	direction int
+}
+
+func pastCursor(cursor *protocol.FruitSearchCursor, sort []protocol.FruitSortKey) cursorOrder {
+	direction := 1
+	if cursor.Backward {
+		direction = -1
+	}
+
+	return cursorOrder{position: fruitAt(cursor.Position), sort: sort, direction: direction}
+}
+
+// after whether f comes after the cursor position in the paging direction
+func (o cursorOrder) after(f *entity.Fruit) bool {
+	return o.direction*compareFruits(f, o.position, o.sort) > 0
+}
+
+// first the n records closest to the cursor, in the paging direction
+func (o cursorOrder) first(records []*memoryRecord, n int) []*memoryRecord {
+	sort.Slice(records, func(i, j int) bool {
+		return o.direction*compareFruits(records[i].fruit, records[j].fruit, o.sort) < 0
+	})
+
+	if len(records) > n {
+		records = records[:n]
+	}
+
+	return records
+}
+
+// window locate the requested page within n sorted fruits, fruit(i) being the ith one, by page number or next to the cursor
+func window(n int, fruit func(i int) *entity.Fruit, filter *protocol.FruitSearchFilter, offset int, limit int) (int, int) {
+	clamp := func(i int) int {
+		if i < 0 {
+			return 0
+		}
+		if i > n {
+			return n
+		}
+		return i
+	}
+
+	if filter.Cursor == nil {
+		return clamp((offset - 1) * limit), clamp(offset * limit)
+	}
+
+	position := fruitAt(filter.Cursor.Position)
+
+	if filter.Cursor.Backward {
+		end := sort.Search(n, func(i int) bool {
+			return compareFruits(fruit(i), position, filter.Sort) >= 0
+		})
+		return clamp(end - limit), end
+	}
+
+	start := sort.Search(n, func(i int) bool {
+		return compareFruits(fruit(i), position, filter.Sort) > 0
+	})
+	return start, clamp(start + limit)
+}
+
+// hasMore whether fruits are left out of the [start, end) page of n in the search direction
+func hasMore(n int, start int, end int, filter *protocol.FruitSearchFilter) bool {
+	if filter.Cursor != nil && filter.Cursor.Backward {
+		return start > 0
+	}
+
+	return end < n
+}
+
+// total the paging total of n matches, zero when the filter skips it
+func total(n int, filter *protocol.FruitSearchFilter) int {
+	if filter.SkipTotal {
+		return 0
+	}
+
+	return n
+}
+
+// fruitAt a fruit holding the sort values of position, to compare stored fruits against
+func fruitAt(position protocol.FruitSearchPosition) *entity.Fruit {
+	return &entity.Fruit{
+		Name:      position.Name,
+		Price:     position.Price,
+		Quantity:  position.Quantity,
+		CreatedAt: position.CreatedAt,
+		UpdatedAt: position.UpdatedAt,
+		Tenant:    position.Tenant,
+		ID:        position.ID,
+	}
+}
+
+// CountByStatus count the fruits of every status, across tenants, straight from the status index
+func (fmr *FruitMemoryRepository) CountByStatus(_ context.Context) (map[entity.FruitStatus]int, error) {
+	fmr.mu.RLock()
+	defer fmr.mu.RUnlock()
+
+	counts := map[entity.FruitStatus]int{}
+	for status, keys := range fmr.byStatus {
+		counts[status.key] += len(keys)
+	}
+
+	return counts, nil
+}
+
+// candidates pick the smallest disjoint index sets holding every fruit of filter.Tenant that may match filter,
+// scored being the fuzzy name matches, if any, across tenants. The caller must hold the lock.
+func (fmr *FruitMemoryRepository) candidates(filter *protocol.FruitSearchFilter, scored keySet) []keySet {
+	best := []keySet{fmr.byTenant[filter.Tenant]}
+	size := len(best[0])
+
+	if len(filter.Statuses) > 0 {
+		var byStatus []keySet
+		var total int
+		for _, status := range uniqueStatuses(filter.Statuses) {
+			keys := fmr.byStatus[scoped[entity.FruitStatus]{filter.Tenant, status}]
+			byStatus = append(byStatus, keys)
+			total += len(keys)
+		}
+
+		if total < size {
+			best, size = byStatus, total
+		}
+	}
+
+	if filter.Owner != "" {
+		if byOwner := fmr.byOwner[scoped[string]{filter.Tenant, filter.Owner}]; len(byOwner) < size {
+			best, size = []keySet{byOwner}, len(byOwner)
+		}
+	}
+
+	if scored != nil && len(scored) < size {
+		best = []keySet{scored}
+	}
+
+	return best
+}
+
+func matches(f *entity.Fruit, filter *protocol.FruitSearchFilter) bool {
+	return (filter.AllTenants || f.Tenant == filter.Tenant) &&
+		hasStatus(f, filter.Statuses) &&
+		(filter.Owner == "" || f.Owner == filter.Owner) &&
+		(isFuzzy(filter) || strings.Contains(strings.ToLower(f.Name), strings.ToLower(filter.Name))) &&
+		(filter.MinPrice == nil || f.Price >= *filter.MinPrice) &&
+		(filter.MaxPrice == nil || f.Price <= *filter.MaxPrice) &&
+		(filter.MinQuantity == nil || f.Quantity >= *filter.MinQuantity) &&
+		(filter.MaxQuantity == nil || f.Quantity <= *filter.MaxQuantity) &&
+		(filter.CreatedAfter == nil || f.CreatedAt.After(*filter.CreatedAfter)) &&
+		(filter.CreatedBefore == nil || f.CreatedAt.Before(*filter.CreatedBefore)) &&
+		(filter.UpdatedAfter == nil || f.UpdatedAt.After(*filter.UpdatedAfter)) &&
+		(filter.UpdatedBefore == nil || f.UpdatedAt.Before(*filter.UpdatedBefore)) &&
+		deletedBefore(f, filter.DeletedBefore)
+}
+
+// compareFruits order a and b by keys, then by tenant and id, so no two fruits are ever equal
+func compareFruits(a *entity.Fruit, b *entity.Fruit, keys []protocol.FruitSortKey) int {
+	for _, key := range keys {
+		var c int
+		switch key.Field {
+		case protocol.SortByName:
+			c = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+		case protocol.SortByPrice:
+			c = compareOrdered(a.Price, b.Price)
+		case protocol.SortByQuantity:
+			c = compareOrdered(a.Quantity, b.Quantity)
+		case protocol.SortByCreatedAt:
+			c = compareOrdered(a.CreatedAt.UnixNano(), b.CreatedAt.UnixNano())
+		case protocol.SortByUpdatedAt:
+			c = compareOrdered(a.UpdatedAt.UnixNano(), b.UpdatedAt.UnixNano())
+		}
+
+		if key.Descending {
+			c = -c
+		}
+
+		if c != 0 {
+			return c
+		}
+	}
+
+	if c := strings.Compare(a.Tenant, b.Tenant); c != 0 {
+		return c
+	}
+
+	return strings.Compare(a.ID, b.ID)
+}
+
+func compareOrdered[T int | int64 | float64](a T, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func hasStatus(f *entity.Fruit, statuses []entity.FruitStatus) bool {
+	if len(statuses) == 0 {
+		return true
+	}
+
+	for _, status := range statuses {
+		if f.Status == status {
+			return true
+		}
+	}
+
+	return false
+}
+
+// uniqueStatuses drop repeated statuses, so their index sets are only visited once
+func uniqueStatuses(statuses []entity.FruitStatus) []entity.FruitStatus {
+	seen := make(map[entity.FruitStatus]bool, len(statuses))
+	unique := make([]entity.FruitStatus, 0, len(statuses))
+	for _, status := range statuses {
+		if !seen[status] {
+			seen[status] = true
+			unique = append(unique, status)
+		}
+	}
+
+	return unique
+}
+
+func checkVersion(fruit *entity.Fruit, exists bool, record *memoryRecord) error {
+	switch {
+	case exists && record.fruit.Version != fruit.Version:
+		return newStaleFruitError()
+	case !exists && fruit.Version != 0:
+		return domainerror.NewNotFoundError("fruit not found")
+	default:
+		return nil
+	}
+}
+
+// index add the fruit to the secondary indexes, the caller must hold the write lock
+func (fmr *FruitMemoryRepository) index(f *entity.Fruit) {
+	addToIndex(fmr.byTenant, f.Tenant, keyOf(f))
+	addToIndex(fmr.byStatus, scoped[entity.FruitStatus]{f.Tenant, f.Status}, keyOf(f))
+	addToIndex(fmr.byOwner, scoped[string]{f.Tenant, f.Owner}, keyOf(f))
+	fmr.names.Put(keyOf(f), f.Name)
+}
+
+// unindex remove the fruit from the secondary indexes, the caller must hold the write lock
+func (fmr *FruitMemoryRepository) unindex(f *entity.Fruit) {
+	removeFromIndex(fmr.byTenant, f.Tenant, keyOf(f))
+	removeFromIndex(fmr.byStatus, scoped[entity.FruitStatus]{f.Tenant, f.Status}, keyOf(f))
+	removeFromIndex(fmr.byOwner, scoped[string]{f.Tenant, f.Owner}, keyOf(f))
+	fmr.names.Remove(keyOf(f))
+}
+
+func keyOf(f *entity.Fruit) fruitKey {
+	return fruitKey{tenant: f.Tenant, id: f.ID}
+}
+
+func addToIndex[K comparable](index map[K]keySet, key K, fruit fruitKey) {
+	keys, ok := index[key]
+	if !ok {
+		keys = keySet{}
+		index[key] = keys
+	}
+
+	keys[fruit] = struct{}{}
+}
+
+func removeFromIndex[K comparable](index map[K]keySet, key K, fruit fruitKey) {
+	keys, ok := index[key]
+	if !ok {
+		return
+	}
+
+	delete(keys, fruit)
+	if len(keys) == 0 {
+		delete(index, key)
+	}
+}
+
+func cloneFruit(f *entity.Fruit) *entity.Fruit {
+	c := *f
+	c.RestoredAt = cloneTime(f.RestoredAt)
+	c.DeletedAt = cloneTime(f.DeletedAt)
+
+	return &c
+}
+
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	c := *t
+	return &c
+}
+
+func deletedBefore(f *entity.Fruit, before *time.Time) bool {
+	if before == nil {
+		return true
+	}
+
+	return f.DeletedAt != nil && f.DeletedAt.Before(*before)
+}
@@ -0,0 +1,429 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFruitMemoryRepository_SaveAndGet(t *testing.T) {
+	t.Run("With not found fruit", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := r.Get(context.Background(), tenant.Default, "invalid-id")
+		assert.Nil(t, fruit)
+		assert.EqualError(t, err, "fruit not found")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+	})
+
+	t.Run("Insert and update fruit", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		err = r.Save(context.Background(), fruit)
+		assert.Nil(t, err)
+
+		fruit.Quantity = 5
+		err = r.Save(context.Background(), fruit)
+		assert.Nil(t, err)
+
+		found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found, fruit)
+	})
+
+	t.Run("Stored fruit is not shared with callers", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+
+		fruit.Quantity = 5
+
+		found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.Quantity, 1)
+
+		found.Quantity = 7
+
+		found, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.Quantity, 1)
+	})
+}
+
+func TestFruitMemoryRepository_Search(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	for _, name := range []string{"banana", "Bananada", "apple", "pineapple", "bananinha"} {
+		fruit, err := entity.NewFruit(name, "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	t.Run("Filter by name and status", func(t *testing.T) {
+		result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "BANAN", Statuses: []entity.FruitStatus{"comestible"}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Len(t, result.Results, 3)
+		assert.Equal(t, result.Results[0].Name, "banana")
+		assert.Equal(t, result.Results[1].Name, "Bananada")
+		assert.Equal(t, result.Results[2].Name, "bananinha")
+
+		result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"podrido"}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 0)
+		assert.Len(t, result.Results, 0)
+	})
+
+	t.Run("Paginate results", func(t *testing.T) {
+		result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"comestible"}}, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Equal(t, result.Paging.Offset, 2)
+		assert.Equal(t, result.Paging.Limit, 2)
+		assert.Len(t, result.Results, 1)
+		assert.Equal(t, result.Results[0].Name, "bananinha")
+
+		result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"comestible"}}, 3, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Len(t, result.Results, 0)
+	})
+}
+
+func TestFruitMemoryRepository_SearchAfterStatusChange(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Save(context.Background(), fruit))
+
+	assert.Nil(t, fruit.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, r.Save(context.Background(), fruit))
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 0)
+
+	result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{entity.StatusPodrido}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 1)
+	assert.Equal(t, result.Results[0].ID, fruit.ID)
+}
+
+func TestFruitMemoryRepository_SaveStaleFruit(t *testing.T) {
+	t.Run("Reject stale update", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+		assert.Equal(t, fruit.Version, 1)
+
+		first, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		second, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+
+		first.Quantity = 2
+		assert.Nil(t, r.Save(context.Background(), first))
+		assert.Equal(t, first.Version, 2)
+
+		second.Quantity = 3
+		err = r.Save(context.Background(), second)
+		assert.EqualError(t, err, "fruit has been modified by another request")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		assert.Equal(t, second.Version, 1)
+
+		found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.Quantity, 2)
+		assert.Equal(t, found.Version, 2)
+	})
+
+	t.Run("Reject duplicated insert", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		duplicated := *fruit
+
+		assert.Nil(t, r.Save(context.Background(), fruit))
+
+		err = r.Save(context.Background(), &duplicated)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+	})
+
+	t.Run("Reject update of missing fruit", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Version = 1
+
+		err = r.Save(context.Background(), fruit)
+		assert.EqualError(t, err, "fruit not found")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+	})
+}
+
+func TestFruitMemoryRepository_Delete(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Save(context.Background(), fruit))
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version-1)
+	assert.EqualError(t, err, "fruit has been modified by another request")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+
+	_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Nil(t, err)
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version)
+	assert.Nil(t, err)
+
+	_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 0)
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version)
+	assert.EqualError(t, err, "fruit not found")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+}
+
+func TestFruitMemoryRepository_SearchDeletedBefore(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	old, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, old.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, r.Save(context.Background(), old))
+
+	cutoff := time.Now()
+
+	recent, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, recent.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, r.Save(context.Background(), recent))
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{entity.StatusPodrido}, DeletedBefore: &cutoff}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 1)
+	assert.Equal(t, result.Results[0].ID, old.ID)
+}
+
+// TestFruitMemoryRepository_ConcurrentAccess is meant to be run with -race
+func TestFruitMemoryRepository_ConcurrentAccess(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+	ctx := context.Background()
+
+	var wg sync.WaitGroup
+	for w := 0; w < 8; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				fruit, err := entity.NewFruit("fruit", fmt.Sprintf("owner-%d", w), 1, 10.0)
+				assert.Nil(t, err)
+				assert.Nil(t, r.Save(ctx, fruit))
+
+				found, err := r.Get(ctx, tenant.Default, fruit.ID)
+				assert.Nil(t, err)
+
+				found.Quantity++
+				assert.Nil(t, found.TransitionTo(entity.StatusReserved))
+				assert.Nil(t, r.Save(ctx, found))
+
+				_, err = r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "fruit", Statuses: []entity.FruitStatus{entity.StatusReserved}}, 1, 10)
+				assert.Nil(t, err)
+
+				if i%2 == 0 {
+					assert.Nil(t, r.Delete(ctx, tenant.Default, found.ID, found.Version))
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "fruit", Statuses: []entity.FruitStatus{entity.StatusReserved}}, 1, 1000)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 8*25)
+}
+
+func TestFruitMemoryRepository_CountByStatus(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	counts, err := r.CountByStatus(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, counts, 0)
+
+	for _, sold := range []bool{false, false, true} {
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		if sold {
+			assert.Nil(t, fruit.TransitionTo(entity.StatusSold))
+		}
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	counts, err = r.CountByStatus(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, counts, map[entity.FruitStatus]int{entity.StatusComestible: 2, entity.StatusSold: 1})
+}
+
+func TestFruitMemoryRepository_SearchByOwner(t *testing.T) {
+	r := repository.NewFruitMemoryRepository()
+
+	for _, owner := range []string{"ruan", "clerk", "ruan"} {
+		fruit, err := entity.NewFruit("banana", owner, 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}, Owner: "ruan"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 2)
+	for _, fruit := range result.Results {
+		assert.Equal(t, fruit.Owner, "ruan")
+	}
+
+	result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}, Owner: "nobody"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 0)
+
+	result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 3)
+}
+
+func TestFruitMemoryRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+
+	saveIn := func(r *repository.FruitMemoryRepository, tenantID string, id string, owner string) *entity.Fruit {
+		fruit, err := entity.NewFruit("banana", owner, 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Tenant = tenantID
+		fruit.ID = id
+		assert.Nil(t, r.Save(ctx, fruit))
+		return fruit
+	}
+
+	t.Run("Get never crosses tenants", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+		saveIn(r, "acme", "banana-id", "ruan")
+
+		found, err := r.Get(ctx, "globex", "banana-id")
+		assert.Nil(t, found)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+		found, err = r.Get(ctx, "acme", "banana-id")
+		assert.Nil(t, err)
+		assert.Equal(t, found.Tenant, "acme")
+	})
+
+	t.Run("Same id in two tenants", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+		saveIn(r, "acme", "banana-id", "ruan")
+		saveIn(r, "globex", "banana-id", "clerk")
+
+		acme, err := r.Get(ctx, "acme", "banana-id")
+		assert.Nil(t, err)
+		assert.Equal(t, acme.Owner, "ruan")
+
+		globex, err := r.Get(ctx, "globex", "banana-id")
+		assert.Nil(t, err)
+		assert.Equal(t, globex.Owner, "clerk")
+	})
+
+	t.Run("Save never overwrites another tenant fruit", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+		saveIn(r, "acme", "banana-id", "ruan")
+
+		// a fruit read in another tenant doesn't exist there, updating it must not reach acme
+		stale := &entity.Fruit{Tenant: "globex", ID: "banana-id", Name: "banana", Owner: "mallory", Status: entity.StatusComestible, Version: 1}
+		err := r.Save(ctx, stale)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+		found, err := r.Get(ctx, "acme", "banana-id")
+		assert.Nil(t, err)
+		assert.Equal(t, found.Owner, "ruan")
+	})
+
+	t.Run("Search never crosses tenants", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+		saveIn(r, "acme", "acme-1", "ruan")
+		saveIn(r, "acme", "acme-2", "clerk")
+		saveIn(r, "globex", "globex-1", "ruan")
+
+		result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: "acme", Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 2)
+		for _, fruit := range result.Results {
+			assert.Equal(t, fruit.Tenant, "acme")
+		}
+
+		result, err = r.Search(ctx, &protocol.FruitSearchFilter{Tenant: "globex", Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}, Owner: "ruan"}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 1)
+		assert.Equal(t, result.Results[0].ID, "globex-1")
+
+		result, err = r.Search(ctx, &protocol.FruitSearchFilter{Tenant: "initech", Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 0)
+
+		result, err = r.Search(ctx, &protocol.FruitSearchFilter{AllTenants: true, Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+	})
+
+	t.Run("Delete never crosses tenants", func(t *testing.T) {
+		r := repository.NewFruitMemoryRepository()
+		saveIn(r, "acme", "banana-id", "ruan")
+
+		err := r.Delete(ctx, "globex", "banana-id", 1)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+		_, err = r.Get(ctx, "acme", "banana-id")
+		assert.Nil(t, err)
+
+		assert.Nil(t, r.Delete(ctx, "acme", "banana-id", 1))
+
+		result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: "acme", Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 0)
+	})
+}
+
+func TestFruitMemoryRepository_SearchFilters(t *testing.T) {
+	testSearchFilters(t, repository.NewFruitMemoryRepository())
+}
+
+func TestFruitMemoryRepository_SearchSort(t *testing.T) {
+	testSearchSort(t, repository.NewFruitMemoryRepository())
+}
+
+func TestFruitMemoryRepository_SearchCursor(t *testing.T) {
+	testSearchCursor(t, repository.NewFruitMemoryRepository())
+}
+
+func TestFruitMemoryRepository_SearchFuzzy(t *testing.T) {
+	testSearchFuzzy(t, repository.NewFruitMemoryRepository())
+}
@@ -0,0 +1,357 @@
+package repository_test
+
+import (
+	"context"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testSearchFilters check every search filter against r, shared by the repository implementations
+func testSearchFilters(t *testing.T, r protocol.FruitRepository) {
+	ctx := context.Background()
+	epoch := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []struct {
+		name     string
+		owner    string
+		quantity int
+		price    float64
+		status   entity.FruitStatus
+		age      int // days after epoch the fruit was created, and updated a day later
+	}{
+		{"banana", "ruan", 1, 2.5, entity.StatusComestible, 0},
+		{"apple", "ruan", 5, 10, entity.StatusReserved, 1},
+		{"grape", "clerk", 10, 7.5, entity.StatusSold, 2},
+		{"melon", "clerk", 20, 15, entity.StatusComestible, 3},
+	}
+
+	for _, s := range seed {
+		fruit, err := entity.NewFruit(s.name, s.owner, s.quantity, s.price)
+		assert.Nil(t, err)
+		fruit.Status = s.status
+		fruit.CreatedAt = epoch.AddDate(0, 0, s.age)
+		fruit.UpdatedAt = fruit.CreatedAt.AddDate(0, 0, 1)
+		assert.Nil(t, r.Save(ctx, fruit))
+	}
+
+	float := func(f float64) *float64 { return &f }
+	integer := func(i int) *int { return &i }
+	day := func(d int) *time.Time {
+		t := epoch.AddDate(0, 0, d)
+		return &t
+	}
+
+	cases := map[string]struct {
+		filter protocol.FruitSearchFilter
+		names  []string
+	}{
+		"without filters":       {protocol.FruitSearchFilter{}, []string{"banana", "apple", "grape", "melon"}},
+		"by statuses":           {protocol.FruitSearchFilter{Statuses: []entity.FruitStatus{entity.StatusComestible, entity.StatusSold}}, []string{"banana", "grape", "melon"}},
+		"by repeated statuses":  {protocol.FruitSearchFilter{Statuses: []entity.FruitStatus{entity.StatusReserved, entity.StatusReserved}}, []string{"apple"}},
+		"by owner":              {protocol.FruitSearchFilter{Owner: "clerk"}, []string{"grape", "melon"}},
+		"by owner and status":   {protocol.FruitSearchFilter{Owner: "clerk", Statuses: []entity.FruitStatus{entity.StatusComestible}}, []string{"melon"}},
+		"by price range":        {protocol.FruitSearchFilter{MinPrice: float(7.5), MaxPrice: float(10)}, []string{"apple", "grape"}},
+		"by minimum price":      {protocol.FruitSearchFilter{MinPrice: float(10)}, []string{"apple", "melon"}},
+		"by quantity range":     {protocol.FruitSearchFilter{MinQuantity: integer(5), MaxQuantity: integer(10)}, []string{"apple", "grape"}},
+		"by maximum quantity":   {protocol.FruitSearchFilter{MaxQuantity: integer(1)}, []string{"banana"}},
+		"by creation range":     {protocol.FruitSearchFilter{CreatedAfter: day(0), CreatedBefore: day(3)}, []string{"apple", "grape"}},
+		"by last update":        {protocol.FruitSearchFilter{UpdatedAfter: day(3)}, []string{"melon"}},
+		"by update range":       {protocol.FruitSearchFilter{UpdatedAfter: day(0), UpdatedBefore: day(2)}, []string{"banana"}},
+		"by name and ranges":    {protocol.FruitSearchFilter{Name: "e", MinPrice: float(8), MaxQuantity: integer(10)}, []string{"apple"}},
+		"with nothing matching": {protocol.FruitSearchFilter{Owner: "ruan", MinQuantity: integer(10)}, nil},
+	}
+
+	for name, c := range cases {
+		t.Run("Search "+name, func(t *testing.T) {
+			filter := c.filter
+			filter.Tenant = tenant.Default
+
+			result, err := r.Search(ctx, &filter, 1, 10)
+			assert.Nil(t, err)
+			assert.Equal(t, result.Paging.Total, len(c.names))
+
+			var names []string
+			for _, fruit := range result.Results {
+				names = append(names, fruit.Name)
+			}
+			assert.Equal(t, names, c.names)
+		})
+	}
+}
+
+// testSearchSort check the search ordering of r, shared by the repository implementations
+func testSearchSort(t *testing.T, r protocol.FruitRepository) {
+	ctx := context.Background()
+	epoch := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []struct {
+		name     string
+		quantity int
+		price    float64
+	}{
+		{"melon", 2, 10},
+		{"Apple", 5, 2.5},
+		{"banana", 1, 10},
+		{"grape", 5, 7.5},
+		{"apple", 3, 2.5},
+		{"kiwi", 5, 10},
+	}
+
+	for i, s := range seed {
+		fruit, err := entity.NewFruit(s.name, "ruan", s.quantity, s.price)
+		assert.Nil(t, err)
+		fruit.CreatedAt = epoch.AddDate(0, 0, len(seed)-i)
+		fruit.UpdatedAt = epoch.AddDate(0, 0, i)
+		assert.Nil(t, r.Save(ctx, fruit))
+	}
+
+	search := func(sort []protocol.FruitSortKey, offset int, limit int) []*entity.Fruit {
+		result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort}, offset, limit)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, len(seed))
+		return result.Results
+	}
+
+	namesOf := func(fruits []*entity.Fruit) []string {
+		var names []string
+		for _, fruit := range fruits {
+			names = append(names, fruit.Name)
+		}
+		return names
+	}
+
+	t.Run("Without sort keep insertion order", func(t *testing.T) {
+		assert.Equal(t, namesOf(search(nil, 1, 10)), []string{"melon", "Apple", "banana", "grape", "apple", "kiwi"})
+	})
+
+	t.Run("Sort by creation and last update", func(t *testing.T) {
+		assert.Equal(t, namesOf(search([]protocol.FruitSortKey{{Field: protocol.SortByCreatedAt}}, 1, 10)), []string{"kiwi", "apple", "grape", "banana", "Apple", "melon"})
+		assert.Equal(t, namesOf(search([]protocol.FruitSortKey{{Field: protocol.SortByUpdatedAt, Descending: true}}, 1, 10)), []string{"kiwi", "apple", "grape", "banana", "Apple", "melon"})
+	})
+
+	t.Run("Sort by several keys", func(t *testing.T) {
+		fruits := search([]protocol.FruitSortKey{{Field: protocol.SortByPrice, Descending: true}, {Field: protocol.SortByName}}, 1, 10)
+		assert.Equal(t, namesOf(fruits), []string{"banana", "kiwi", "melon", "grape", fruits[4].Name, fruits[5].Name})
+
+		// names are compared case insensitively, the id breaks the tie
+		assert.Equal(t, strings.ToLower(fruits[4].Name), "apple")
+		assert.Equal(t, strings.ToLower(fruits[5].Name), "apple")
+		assert.Less(t, fruits[4].ID, fruits[5].ID)
+
+		fruits = search([]protocol.FruitSortKey{{Field: protocol.SortByQuantity, Descending: true}, {Field: protocol.SortByPrice}}, 1, 10)
+		assert.Equal(t, namesOf(fruits), []string{"Apple", "grape", "kiwi", "apple", "melon", "banana"})
+	})
+
+	t.Run("Pages never overlap", func(t *testing.T) {
+		sort := []protocol.FruitSortKey{{Field: protocol.SortByPrice}}
+		all := search(sort, 1, 10)
+
+		var paged []*entity.Fruit
+		for page := 1; page <= 3; page++ {
+			paged = append(paged, search(sort, page, 2)...)
+		}
+
+		assert.Equal(t, paged, all)
+	})
+}
+
+// testSearchCursor check paging r with cursors, forward then backward, shared by the repository implementations
+func testSearchCursor(t *testing.T, r protocol.FruitRepository) {
+	ctx := context.Background()
+	epoch := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+
+	seed := []struct {
+		name  string
+		price float64
+	}{
+		{"melon", 10},
+		{"Apple", 2.5},
+		{"banana", 10},
+		{"grape", 7.5},
+		{"apple", 2.5},
+		{"kiwi", 10},
+		{"pear", 5},
+	}
+
+	for i, s := range seed {
+		fruit, err := entity.NewFruit(s.name, "ruan", 1, s.price)
+		assert.Nil(t, err)
+		fruit.CreatedAt = epoch.AddDate(0, 0, i)
+		fruit.UpdatedAt = fruit.CreatedAt
+		assert.Nil(t, r.Save(ctx, fruit))
+	}
+
+	sorts := map[string][]protocol.FruitSortKey{
+		"creation":    {{Field: protocol.SortByCreatedAt}},
+		"mixed order": {{Field: protocol.SortByPrice, Descending: true}, {Field: protocol.SortByName}},
+	}
+
+	for name, sort := range sorts {
+		for _, limit := range []int{1, 3} {
+			t.Run(fmt.Sprintf("Page by %s, %d at a time", name, limit), func(t *testing.T) {
+				all, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort}, 1, 10)
+				assert.Nil(t, err)
+
+				var forward []*entity.Fruit
+				var cursor *protocol.FruitSearchCursor
+				for {
+					result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, Cursor: cursor}, 1, limit)
+					assert.Nil(t, err)
+					assert.Equal(t, result.Paging.Total, len(seed))
+					forward = append(forward, result.Results...)
+
+					if !result.HasMore {
+						break
+					}
+					cursor = &protocol.FruitSearchCursor{Sort: sort, Position: protocol.PositionOf(result.Results[len(result.Results)-1])}
+				}
+				assert.Equal(t, forward, all.Results)
+
+				var backward []*entity.Fruit
+				cursor = &protocol.FruitSearchCursor{Sort: sort, Position: protocol.PositionOf(forward[len(forward)-1]), Backward: true}
+				for {
+					result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, Cursor: cursor}, 1, limit)
+					assert.Nil(t, err)
+					backward = append(result.Results, backward...)
+
+					if !result.HasMore {
+						break
+					}
+					cursor = &protocol.FruitSearchCursor{Sort: sort, Position: protocol.PositionOf(result.Results[0]), Backward: true}
+				}
+				assert.Equal(t, backward, all.Results[:len(seed)-1])
+			})
+		}
+	}
+
+	t.Run("Without total", func(t *testing.T) {
+		sort := sorts["creation"]
+		first, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, SkipTotal: true}, 1, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, first.Paging.Total, 0)
+		assert.Len(t, first.Results, 3)
+		assert.True(t, first.HasMore)
+
+		cursor := &protocol.FruitSearchCursor{Sort: sort, Position: protocol.PositionOf(first.Results[2])}
+		next, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, Cursor: cursor, SkipTotal: true}, 1, 3)
+		assert.Nil(t, err)
+		assert.Equal(t, next.Paging.Total, 0)
+		assert.Len(t, next.Results, 3)
+		assert.True(t, next.HasMore)
+
+		last, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, SkipTotal: true}, 3, 3)
+		assert.Nil(t, err)
+		assert.Len(t, last.Results, 1)
+		assert.False(t, last.HasMore)
+	})
+
+	t.Run("Past the last fruit", func(t *testing.T) {
+		sort := sorts["creation"]
+		last, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort}, 7, 1)
+		assert.Nil(t, err)
+
+		result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: tenant.Default, Sort: sort, Cursor: &protocol.FruitSearchCursor{Sort: sort, Position: protocol.PositionOf(last.Results[0])}}, 1, 3)
+		assert.Nil(t, err)
+		assert.Empty(t, result.Results)
+		assert.False(t, result.HasMore)
+	})
+}
+
+// testSearchFuzzy check fuzzy name searches against r, shared by the repository implementations
+func testSearchFuzzy(t *testing.T, r protocol.FruitRepository) {
+	ctx := context.Background()
+
+	seed := map[string]entity.FruitStatus{
+		"Maçã verde":   entity.StatusComestible,
+		"maca":         entity.StatusSold,
+		"Banana-prata": entity.StatusComestible,
+		"banana":       entity.StatusComestible,
+		"bananada":     entity.StatusComestible,
+		"uva":          entity.StatusComestible,
+	}
+
+	fruits := map[string]*entity.Fruit{}
+	for name, status := range seed {
+		fruit, err := entity.NewFruit("fruit", "ruan", 1, 10)
+		assert.Nil(t, err)
+		// repositories store names as given, so accented and compound ones are indexed as well
+		fruit.Name = name
+		fruit.Status = status
+		assert.Nil(t, r.Save(ctx, fruit))
+		fruits[name] = fruit
+	}
+
+	search := func(filter *protocol.FruitSearchFilter) ([]string, []float64) {
+		filter.Tenant = tenant.Default
+		filter.Match = protocol.MatchFuzzy
+
+		result, err := r.Search(ctx, filter, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, len(result.Results))
+		assert.Len(t, result.Scores, len(result.Results))
+
+		var names []string
+		for _, fruit := range result.Results {
+			names = append(names, fruit.Name)
+		}
+		return names, result.Scores
+	}
+
+	t.Run("Rank by relevance", func(t *testing.T) {
+		names, scores := search(&protocol.FruitSearchFilter{Name: "banana"})
+		assert.Equal(t, names, []string{"banana", "Banana-prata", "bananada"})
+		assert.Equal(t, scores[0], 1.0)
+		assert.Greater(t, scores[1], scores[2])
+	})
+
+	t.Run("Tolerate typos and accents", func(t *testing.T) {
+		names, _ := search(&protocol.FruitSearchFilter{Name: "bananna"})
+		assert.Equal(t, names, []string{"banana", "Banana-prata", "bananada"})
+
+		names, scores := search(&protocol.FruitSearchFilter{Name: "MAÇA"})
+		assert.ElementsMatch(t, names, []string{"Maçã verde", "maca"})
+		assert.Equal(t, names[0], "maca")
+		assert.Greater(t, scores[0], scores[1])
+
+		names, _ = search(&protocol.FruitSearchFilter{Name: "maca verd"})
+		assert.Equal(t, names, []string{"Maçã verde"})
+	})
+
+	t.Run("Combine with other filters and sort", func(t *testing.T) {
+		names, _ := search(&protocol.FruitSearchFilter{Name: "maca", Statuses: []entity.FruitStatus{entity.StatusComestible}})
+		assert.Equal(t, names, []string{"Maçã verde"})
+
+		names, scores := search(&protocol.FruitSearchFilter{Name: "banana", Sort: []protocol.FruitSortKey{{Field: protocol.SortByName, Descending: true}}})
+		assert.Equal(t, names, []string{"bananada", "Banana-prata", "banana"})
+		assert.Equal(t, scores[2], 1.0)
+	})
+
+	t.Run("Without match", func(t *testing.T) {
+		names, _ := search(&protocol.FruitSearchFilter{Name: "kiwi"})
+		assert.Empty(t, names)
+
+		names, _ = search(&protocol.FruitSearchFilter{Name: "?"})
+		assert.Empty(t, names)
+	})
+
+	t.Run("Follow renames and deletes", func(t *testing.T) {
+		uva := fruits["uva"]
+		uva.Name = "bananinha"
+		assert.Nil(t, r.Save(ctx, uva))
+
+		names, _ := search(&protocol.FruitSearchFilter{Name: "bananinha"})
+		assert.Equal(t, names, []string{"bananinha"})
+		names, _ = search(&protocol.FruitSearchFilter{Name: "uva"})
+		assert.Empty(t, names)
+
+		assert.Nil(t, r.Delete(ctx, tenant.Default, uva.ID, uva.Version))
+		names, _ = search(&protocol.FruitSearchFilter{Name: "bananinha"})
+		assert.Empty(t, names)
+	})
+}
@@ -0,0 +1,589 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/search"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/sirupsen/logrus"
+)
+
+const fruitColumns = "tenant, id, created_at, updated_at, name, quantity, price, owner, status, previous_status, restored_by, restored_at, deleted_at, version"
+
+type FruitSQLiteRepository struct {
+	db *sql.DB
+}
+
+// querier run statements, on the database or within a transaction
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txKey the context key of the transaction repository calls join
+type txKey struct{}
+
+func NewFruitSQLiteRepository(db *sql.DB) *FruitSQLiteRepository {
+	return &FruitSQLiteRepository{
+		db: db,
+	}
+}
+
+// InTransaction run fn in a transaction every repository call made with its context joins
+func (fsr *FruitSQLiteRepository) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := fsr.db.BeginTx(ctx, nil)
+	if err != nil {
+		return domainerror.NewInternalError("fail to begin transaction", err)
+	}
+
+	committed := false
+	defer func() {
+		if !committed {
+			_ = tx.Rollback()
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return domainerror.NewInternalError("fail to commit transaction", err)
+	}
+	committed = true
+
+	return nil
+}
+
+// conn the transaction of ctx, if any, otherwise the database
+func (fsr *FruitSQLiteRepository) conn(ctx context.Context) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+
+	return fsr.db
+}
+
+// Save insert or update fruit, refusing to overwrite a version other than the one fruit was read at.
+// On success fruit.Version is bumped to the stored version.
+func (fsr *FruitSQLiteRepository) Save(ctx context.Context, fruit *entity.Fruit) error {
+	var err error
+	if fruit.Version == 0 {
+		err = fsr.insert(ctx, fruit)
+	} else {
+		err = fsr.update(ctx, fruit)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	fruit.Version++
+	logger.FromContext(ctx).WithFields(logrus.Fields{"tenant": fruit.Tenant, "fruit_id": fruit.ID, "version": fruit.Version}).Debug("fruit saved to sqlite")
+
+	return nil
+}
+
+func (fsr *FruitSQLiteRepository) insert(ctx context.Context, fruit *entity.Fruit) error {
+	_, err := fsr.conn(ctx).ExecContext(
+		ctx,
+		"INSERT INTO fruits ("+fruitColumns+") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		fruit.Tenant,
+		fruit.ID,
+		fruit.CreatedAt.UnixNano(),
+		fruit.UpdatedAt.UnixNano(),
+		fruit.Name,
+		fruit.Quantity,
+		fruit.Price,
+		fruit.Owner,
+		fruit.Status,
+		fruit.PreviousStatus,
+		fruit.RestoredBy,
+		nullableTime(fruit.RestoredAt),
+		nullableTime(fruit.DeletedAt),
+		fruit.Version+1,
+	)
+
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey {
+		return newStaleFruitError()
+	}
+
+	if err != nil {
+		return domainerror.NewInternalError("fail to save fruit", err)
+	}
+
+	return nil
+}
+
+func (fsr *FruitSQLiteRepository) update(ctx context.Context, fruit *entity.Fruit) error {
+	result, err := fsr.conn(ctx).ExecContext(
+		ctx,
+		`UPDATE fruits SET
+			updated_at = ?,
+			name = ?,
+			quantity = ?,
+			price = ?,
+			owner = ?,
+			status = ?,
+			previous_status = ?,
+			restored_by = ?,
+			restored_at = ?,
+			deleted_at = ?,
+			version = version + 1
+		WHERE tenant = ? AND id = ? AND version = ?`,
+		fruit.UpdatedAt.UnixNano(),
+		fruit.Name,
+		fruit.Quantity,
+		fruit.Price,
+		fruit.Owner,
+		fruit.Status,
+		fruit.PreviousStatus,
+		fruit.RestoredBy,
+		nullableTime(fruit.RestoredAt),
+		nullableTime(fruit.DeletedAt),
+		fruit.Tenant,
+		fruit.ID,
+		fruit.Version,
+	)
+	if err != nil {
+		return domainerror.NewInternalError("fail to save fruit", err)
+	}
+
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return domainerror.NewInternalError("fail to save fruit", err)
+	}
+
+	if updated > 0 {
+		return nil
+	}
+
+	return fsr.missedVersion(ctx, "save", fruit.Tenant, fruit.ID, fruit.Version)
+}
+
+// missedVersion tell why a statement conditioned on the fruit version matched nothing: either the fruit is gone or
+// its version moved on
+func (fsr *FruitSQLiteRepository) missedVersion(ctx context.Context, operation string, tenant string, id string, version int) error {
+	var exists bool
+	err := fsr.conn(ctx).QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM fruits WHERE tenant = ? AND id = ?)", tenant, id).Scan(&exists)
+	if err != nil {
+		return domainerror.NewInternalError("fail to "+operation+" fruit", err)
+	}
+
+	if !exists {
+		return domainerror.NewNotFoundError("fruit not found")
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"tenant": tenant, "fruit_id": id, "version": version}).Warn("refused to " + operation + " stale fruit")
+
+	return newStaleFruitError()
+}
+
+func (fsr *FruitSQLiteRepository) Get(ctx context.Context, tenant string, id string) (*entity.Fruit, error) {
+	row := fsr.conn(ctx).QueryRowContext(ctx, "SELECT "+fruitColumns+" FROM fruits WHERE tenant = ? AND id = ?", tenant, id)
+
+	fruit, err := scanFruit(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domainerror.NewNotFoundError("fruit not found")
+	}
+
+	if err != nil {
+		return nil, domainerror.NewInternalError("fail to get fruit", err)
+	}
+
+	return fruit, nil
+}
+
+// Delete permanently remove the fruit, refusing to remove a version other than the one it was read at
+func (fsr *FruitSQLiteRepository) Delete(ctx context.Context, tenant string, id string, version int) error {
+	result, err := fsr.conn(ctx).ExecContext(ctx, "DELETE FROM fruits WHERE tenant = ? AND id = ? AND version = ?", tenant, id, version)
+	if err != nil {
+		return domainerror.NewInternalError("fail to delete fruit", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return domainerror.NewInternalError("fail to delete fruit", err)
+	}
+
+	if deleted == 0 {
+		return fsr.missedVersion(ctx, "delete", tenant, id, version)
+	}
+
+	logger.FromContext(ctx).WithFields(logrus.Fields{"tenant": tenant, "fruit_id": id}).Debug("fruit deleted from sqlite")
+
+	return nil
+}
+
+func (fsr *FruitSQLiteRepository) Search(ctx context.Context, filter *protocol.FruitSearchFilter, offset int, limit int) (*protocol.FruitSearchResult, error) {
+	where, args := searchConditions(filter)
+
+	if isFuzzy(filter) {
+		return fsr.searchFuzzy(ctx, filter, where, args, offset, limit)
+	}
+
+	var total int
+	if !filter.SkipTotal {
+		err := fsr.conn(ctx).QueryRowContext(ctx, "SELECT COUNT(*) FROM fruits "+where, args...).Scan(&total)
+		if err != nil {
+			return nil, domainerror.NewInternalError("fail to search fruits", err)
+		}
+	}
+
+	var results []*entity.Fruit
+	var hasMore bool
+
+	if total > 0 || filter.SkipTotal {
+		// one more row than asked tells whether the page is the last one
+		query := "SELECT " + fruitColumns + " FROM fruits "
+		pageArgs := args
+
+		if filter.Cursor == nil {
+			query += where + " ORDER BY " + orderBy(filter.Sort, false) + " LIMIT ? OFFSET ?"
+			pageArgs = append(pageArgs, limit+1, (offset-1)*limit)
+		} else {
+			condition, cursorArgs := keysetCondition(filter.Cursor, filter.Sort)
+			query += and(where, condition) + " ORDER BY " + orderBy(filter.Sort, filter.Cursor.Backward) + " LIMIT ?"
+			pageArgs = append(append(pageArgs, cursorArgs...), limit+1)
+		}
+
+		var err error
+		results, err = fsr.query(ctx, query, pageArgs...)
+		if err != nil {
+			return nil, err
+		}
+
+		hasMore = len(results) > limit
+		if hasMore {
+			results = results[:limit]
+		}
+
+		if filter.Cursor != nil && filter.Cursor.Backward {
+			reverse(results)
+		}
+	}
+
+	return &protocol.FruitSearchResult{
+		Paging: &protocol.FruitSearchResultPaging{
+			Total:  total,
+			Offset: offset,
+			Limit:  limit,
+		},
+		Results: results,
+		HasMore: hasMore,
+	}, nil
+}
+
+// searchFuzzy score the name of every fruit meeting the other criteria, SQLite having no notion of typos or accents
+func (fsr *FruitSQLiteRepository) searchFuzzy(ctx context.Context, filter *protocol.FruitSearchFilter, where string, args []interface{}, offset int, limit int) (*protocol.FruitSearchResult, error) {
+	fruits, err := fsr.query(ctx, "SELECT "+fruitColumns+" FROM fruits "+where, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []fuzzyMatch
+	for _, fruit := range fruits {
+		if score := search.Score(filter.Name, fruit.Name); score > 0 {
+			matches = append(matches, fuzzyMatch{fruit: fruit, score: score})
+		}
+	}
+
+	return fuzzyPage(matches, filter, offset, limit), nil
+}
+
+func (fsr *FruitSQLiteRepository) query(ctx context.Context, query string, args ...interface{}) ([]*entity.Fruit, error) {
+	rows, err := fsr.conn(ctx).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, domainerror.NewInternalError("fail to search fruits", err)
+	}
+	defer rows.Close()
+
+	var fruits []*entity.Fruit
+	for rows.Next() {
+		fruit, err := scanFruit(rows)
+		if err != nil {
+			return nil, domainerror.NewInternalError("fail to search fruits", err)
+		}
+		fruits = append(fruits, fruit)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerror.NewInternalError("fail to search fruits", err)
+	}
+
+	return fruits, nil
+}
+
+func (fsr *FruitSQLiteRepository) CountByStatus(ctx context.Context) (map[entity.FruitStatus]int, error) {
+	rows, err := fsr.conn(ctx).QueryContext(ctx, "SELECT status, COUNT(*) FROM fruits GROUP BY status")
+	if err != nil {
+		return nil, domainerror.NewInternalError("fail to count fruits", err)
+	}
+	defer rows.Close()
+
+	counts := map[entity.FruitStatus]int{}
+	for rows.Next() {
+		var status entity.FruitStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, domainerror.NewInternalError("fail to count fruits", err)
+		}
+		counts[status] = count
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, domainerror.NewInternalError("fail to count fruits", err)
+	}
+
+	return counts, nil
+}
+
+// searchConditions translate filter to a WHERE clause and its arguments
+func searchConditions(filter *protocol.FruitSearchFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	where := func(condition string, arg ...interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, arg...)
+	}
+
+	if !filter.AllTenants {
+		where("tenant = ?", filter.Tenant)
+	}
+
+	if filter.Name != "" && !isFuzzy(filter) {
+		where("instr(lower(name), lower(?)) > 0", filter.Name)
+	}
+
+	if len(filter.Statuses) > 0 {
+		placeholders := make([]string, len(filter.Statuses))
+		statuses := make([]interface{}, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			placeholders[i] = "?"
+			statuses[i] = status
+		}
+		where("status IN ("+strings.Join(placeholders, ", ")+")", statuses...)
+	}
+
+	if filter.Owner != "" {
+		where("owner = ?", filter.Owner)
+	}
+
+	if filter.MinPrice != nil {
+		where("price >= ?", *filter.MinPrice)
+	}
+
+	if filter.MaxPrice != nil {
+		where("price <= ?", *filter.MaxPrice)
+	}
+
+	if filter.MinQuantity != nil {
+		where("quantity >= ?", *filter.MinQuantity)
+	}
+
+	if filter.MaxQuantity != nil {
+		where("quantity <= ?", *filter.MaxQuantity)
+	}
+
+	if filter.CreatedAfter != nil {
+		where("created_at > ?", filter.CreatedAfter.UnixNano())
+	}
+
+	if filter.CreatedBefore != nil {
+		where("created_at < ?", filter.CreatedBefore.UnixNano())
+	}
+
+	if filter.UpdatedAfter != nil {
+		where("updated_at > ?", filter.UpdatedAfter.UnixNano())
+	}
+
+	if filter.UpdatedBefore != nil {
+		where("updated_at < ?", filter.UpdatedBefore.UnixNano())
+	}
+
+	if filter.DeletedBefore != nil {
+		where("deleted_at < ?", filter.DeletedBefore.UnixNano())
+	}
+
+	if len(conditions) == 0 {
+		return "", nil
+	}
+
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// sortColumns the expression each sort field orders by
+var sortColumns = map[protocol.FruitSortField]string{
+	protocol.SortByName:      "lower(name)",
+	protocol.SortByPrice:     "price",
+	protocol.SortByQuantity:  "quantity",
+	protocol.SortByCreatedAt: "created_at",
+	protocol.SortByUpdatedAt: "updated_at",
+}
+
+// orderBy translate keys to an ORDER BY clause ending with the primary key, insertion order without keys.
+// Reversed flip every direction, to read backward from a cursor.
+func orderBy(keys []protocol.FruitSortKey, reversed bool) string {
+	if len(keys) == 0 {
+		return "rowid"
+	}
+
+	terms := make([]string, 0, len(keys)+2)
+	for _, key := range keys {
+		term := sortColumns[key.Field]
+		if key.Descending != reversed {
+			term += " DESC"
+		}
+		terms = append(terms, term)
+	}
+
+	for _, column := range []string{"tenant", "id"} {
+		if reversed {
+			column += " DESC"
+		}
+		terms = append(terms, column)
+	}
+
+	return strings.Join(terms, ", ")
+}
+
+// keysetCondition match the rows ordered by keys after the cursor position, or before it when the cursor is backward.
+// Directions may differ between keys, so the row is compared key by key rather than as a single tuple.
+func keysetCondition(cursor *protocol.FruitSearchCursor, keys []protocol.FruitSortKey) (string, []interface{}) {
+	type term struct {
+		column      string
+		placeholder string
+		value       interface{}
+		descending  bool
+	}
+
+	position := cursor.Position
+	terms := make([]term, 0, len(keys)+2)
+	for _, key := range keys {
+		t := term{column: sortColumns[key.Field], placeholder: "?", descending: key.Descending}
+		switch key.Field {
+		case protocol.SortByName:
+			t.placeholder, t.value = "lower(?)", position.Name
+		case protocol.SortByPrice:
+			t.value = position.Price
+		case protocol.SortByQuantity:
+			t.value = position.Quantity
+		case protocol.SortByCreatedAt:
+			t.value = position.CreatedAt.UnixNano()
+		case protocol.SortByUpdatedAt:
+			t.value = position.UpdatedAt.UnixNano()
+		}
+		terms = append(terms, t)
+	}
+	terms = append(terms, term{"tenant", "?", position.Tenant, false}, term{"id", "?", position.ID, false})
+
+	var alternatives []string
+	var args []interface{}
+	for i, t := range terms {
+		var conjunction []string
+		for _, previous := range terms[:i] {
+			conjunction = append(conjunction, previous.column+" = "+previous.placeholder)
+			args = append(args, previous.value)
+		}
+
+		operator := " > "
+		if t.descending != cursor.Backward {
+			operator = " < "
+		}
+		conjunction = append(conjunction, t.column+operator+t.placeholder)
+		args = append(args, t.value)
+
+		alternatives = append(alternatives, "("+strings.Join(conjunction, " AND ")+")")
+	}
+
+	return "(" + strings.Join(alternatives, " OR ") + ")", args
+}
+
+// and append condition to the where clause
+func and(where string, condition string) string {
+	if where == "" {
+		return "WHERE " + condition
+	}
+
+	return where + " AND " + condition
+}
+
+func reverse(fruits []*entity.Fruit) {
+	for i, j := 0, len(fruits)-1; i < j; i, j = i+1, j-1 {
+		fruits[i], fruits[j] = fruits[j], fruits[i]
+	}
+}
+
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+func scanFruit(row rowScanner) (*entity.Fruit, error) {
+	var fruit entity.Fruit
+	var createdAt, updatedAt int64
+	var restoredAt, deletedAt sql.NullInt64
+
+	err := row.Scan(
+		&fruit.Tenant,
+		&fruit.ID,
+		&createdAt,
+		&updatedAt,
+		&fruit.Name,
+		&fruit.Quantity,
+		&fruit.Price,
+		&fruit.Owner,
+		&fruit.Status,
+		&fruit.PreviousStatus,
+		&fruit.RestoredBy,
+		&restoredAt,
+		&deletedAt,
+		&fruit.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fruit.CreatedAt = time.Unix(0, createdAt)
+	fruit.UpdatedAt = time.Unix(0, updatedAt)
+	fruit.RestoredAt = timeFromNullable(restoredAt)
+	fruit.DeletedAt = timeFromNullable(deletedAt)
+
+	return &fruit, nil
+}
+
+func nullableTime(t *time.Time) sql.NullInt64 {
+	if t == nil {
+		return sql.NullInt64{}
+	}
+
+	return sql.NullInt64{Int64: t.UnixNano(), Valid: true}
+}
+
+func timeFromNullable(n sql.NullInt64) *time.Time {
+	if !n.Valid {
+		return nil
+	}
+
+	t := time.Unix(0, n.Int64)
+	return &t
+}
+
+// Ping check the database is reachable, used by the readiness probe
+func (fsr *FruitSQLiteRepository) Ping(ctx context.Context) error {
+	return fsr.db.PingContext(ctx)
+}
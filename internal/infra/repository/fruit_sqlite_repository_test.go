@@ -0,0 +1,387 @@
+package repository_test
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/database"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/repository"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func newSQLiteRepository(t *testing.T) *repository.FruitSQLiteRepository {
+	db, err := database.OpenSQLite(context.Background(), ":memory:")
+	assert.Nil(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	return repository.NewFruitSQLiteRepository(db)
+}
+
+func TestFruitSQLiteRepository_SaveAndGet(t *testing.T) {
+	t.Run("With not found fruit", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := r.Get(context.Background(), tenant.Default, "invalid-id")
+		assert.Nil(t, fruit)
+		assert.EqualError(t, err, "fruit not found")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+	})
+
+	t.Run("Insert and update fruit", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		err = r.Save(context.Background(), fruit)
+		assert.Nil(t, err)
+
+		fruit.Quantity = 5
+		err = r.Save(context.Background(), fruit)
+		assert.Nil(t, err)
+
+		found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.ID, fruit.ID)
+		assert.Equal(t, found.Name, fruit.Name)
+		assert.Equal(t, found.Owner, fruit.Owner)
+		assert.Equal(t, found.Quantity, 5)
+		assert.Equal(t, found.Price, fruit.Price)
+		assert.Equal(t, found.Status, fruit.Status)
+		assert.True(t, found.CreatedAt.Equal(fruit.CreatedAt))
+		assert.True(t, found.UpdatedAt.Equal(fruit.UpdatedAt))
+	})
+}
+
+func TestFruitSQLiteRepository_SaveRestoredFruit(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, fruit.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, fruit.Restore("clerk"))
+
+	err = r.Save(context.Background(), fruit)
+	assert.Nil(t, err)
+
+	found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, found.Status, entity.StatusComestible)
+	assert.Equal(t, found.PreviousStatus, entity.StatusPodrido)
+	assert.Equal(t, found.RestoredBy, "clerk")
+	assert.True(t, found.RestoredAt.Equal(*fruit.RestoredAt))
+}
+
+func TestFruitSQLiteRepository_Search(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	for _, name := range []string{"banana", "Bananada", "apple", "pineapple", "bananinha"} {
+		fruit, err := entity.NewFruit(name, "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	t.Run("Filter by name and status", func(t *testing.T) {
+		result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "BANAN", Statuses: []entity.FruitStatus{"comestible"}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Len(t, result.Results, 3)
+		assert.Equal(t, result.Results[0].Name, "banana")
+
+		result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"podrido"}}, 1, 10)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 0)
+		assert.Len(t, result.Results, 0)
+	})
+
+	t.Run("Paginate results", func(t *testing.T) {
+		result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"comestible"}}, 2, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Equal(t, result.Paging.Offset, 2)
+		assert.Equal(t, result.Paging.Limit, 2)
+		assert.Len(t, result.Results, 1)
+		assert.Equal(t, result.Results[0].Name, "bananinha")
+
+		result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banan", Statuses: []entity.FruitStatus{"comestible"}}, 3, 2)
+		assert.Nil(t, err)
+		assert.Equal(t, result.Paging.Total, 3)
+		assert.Len(t, result.Results, 0)
+	})
+}
+
+func TestFruitSQLiteRepository_SaveStaleFruit(t *testing.T) {
+	t.Run("Reject stale update", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+		assert.Equal(t, fruit.Version, 1)
+
+		first, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		second, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+
+		first.Quantity = 2
+		assert.Nil(t, r.Save(context.Background(), first))
+		assert.Equal(t, first.Version, 2)
+
+		second.Quantity = 3
+		err = r.Save(context.Background(), second)
+		assert.EqualError(t, err, "fruit has been modified by another request")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+		assert.Equal(t, second.Version, 1)
+
+		found, err := r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.Quantity, 2)
+		assert.Equal(t, found.Version, 2)
+	})
+
+	t.Run("Reject duplicated insert", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		duplicated := *fruit
+
+		assert.Nil(t, r.Save(context.Background(), fruit))
+
+		err = r.Save(context.Background(), &duplicated)
+		assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+	})
+
+	t.Run("Reject update of missing fruit", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Version = 1
+
+		err = r.Save(context.Background(), fruit)
+		assert.EqualError(t, err, "fruit not found")
+		assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+	})
+}
+
+func TestFruitSQLiteRepository_Delete(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Save(context.Background(), fruit))
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version-1)
+	assert.EqualError(t, err, "fruit has been modified by another request")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.Conflict)
+
+	_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Nil(t, err)
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version)
+	assert.Nil(t, err)
+
+	_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+	err = r.Delete(context.Background(), tenant.Default, fruit.ID, fruit.Version)
+	assert.EqualError(t, err, "fruit not found")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+}
+
+func TestFruitSQLiteRepository_SearchDeletedBefore(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	old, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, old.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, r.Save(context.Background(), old))
+
+	cutoff := time.Now()
+
+	recent, err := entity.NewFruit("banana", "owner", 1, 10.0)
+	assert.Nil(t, err)
+	assert.Nil(t, recent.TransitionTo(entity.StatusPodrido))
+	assert.Nil(t, r.Save(context.Background(), recent))
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Statuses: []entity.FruitStatus{entity.StatusPodrido}, DeletedBefore: &cutoff}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 1)
+	assert.Equal(t, result.Results[0].ID, old.ID)
+}
+
+func TestFruitSQLiteRepository_CountByStatus(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	counts, err := r.CountByStatus(context.Background())
+	assert.Nil(t, err)
+	assert.Len(t, counts, 0)
+
+	for _, sold := range []bool{false, false, true} {
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		if sold {
+			assert.Nil(t, fruit.TransitionTo(entity.StatusSold))
+		}
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	counts, err = r.CountByStatus(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, counts, map[entity.FruitStatus]int{entity.StatusComestible: 2, entity.StatusSold: 1})
+}
+
+func TestFruitSQLiteRepository_Ping(t *testing.T) {
+	db, err := database.OpenSQLite(context.Background(), ":memory:")
+	assert.Nil(t, err)
+
+	r := repository.NewFruitSQLiteRepository(db)
+	assert.Nil(t, r.Ping(context.Background()))
+
+	assert.Nil(t, db.Close())
+	assert.NotNil(t, r.Ping(context.Background()))
+}
+
+func TestFruitSQLiteRepository_InTransaction(t *testing.T) {
+	t.Run("Commit when fn succeeds", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		fruit, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		err = r.InTransaction(context.Background(), func(ctx context.Context) error {
+			if err := r.Save(ctx, fruit); err != nil {
+				return err
+			}
+
+			// calls within the transaction see its changes and join it
+			_, err := r.Get(ctx, tenant.Default, fruit.ID)
+			if err != nil {
+				return err
+			}
+			return r.InTransaction(ctx, func(ctx context.Context) error {
+				return r.Delete(ctx, tenant.Default, fruit.ID, fruit.Version)
+			})
+		})
+		assert.Nil(t, err)
+
+		_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.EqualError(t, err, "fruit not found")
+	})
+
+	t.Run("Roll back when fn fails", func(t *testing.T) {
+		r := newSQLiteRepository(t)
+
+		kept, err := entity.NewFruit("banana", "owner", 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), kept))
+
+		fruit, err := entity.NewFruit("apple", "owner", 1, 10.0)
+		assert.Nil(t, err)
+
+		err = r.InTransaction(context.Background(), func(ctx context.Context) error {
+			if err := r.Save(ctx, fruit); err != nil {
+				return err
+			}
+			if err := r.Delete(ctx, tenant.Default, kept.ID, kept.Version); err != nil {
+				return err
+			}
+			return domainerror.NewConflictError("batch failed")
+		})
+		assert.EqualError(t, err, "batch failed")
+
+		_, err = r.Get(context.Background(), tenant.Default, fruit.ID)
+		assert.EqualError(t, err, "fruit not found")
+
+		found, err := r.Get(context.Background(), tenant.Default, kept.ID)
+		assert.Nil(t, err)
+		assert.Equal(t, found.ID, kept.ID)
+	})
+}
+
+func TestFruitSQLiteRepository_SearchByOwner(t *testing.T) {
+	r := newSQLiteRepository(t)
+
+	for _, owner := range []string{"ruan", "clerk", "ruan"} {
+		fruit, err := entity.NewFruit("banana", owner, 1, 10.0)
+		assert.Nil(t, err)
+		assert.Nil(t, r.Save(context.Background(), fruit))
+	}
+
+	result, err := r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}, Owner: "ruan"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 2)
+	for _, fruit := range result.Results {
+		assert.Equal(t, fruit.Owner, "ruan")
+	}
+
+	result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}, Owner: "nobody"}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 0)
+
+	result, err = r.Search(context.Background(), &protocol.FruitSearchFilter{Tenant: tenant.Default, Name: "banana", Statuses: []entity.FruitStatus{"comestible"}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 3)
+}
+
+func TestFruitSQLiteRepository_TenantIsolation(t *testing.T) {
+	ctx := context.Background()
+	r := newSQLiteRepository(t)
+
+	for _, tenantID := range []string{"acme", "globex"} {
+		fruit, err := entity.NewFruit("banana", "ruan", 1, 10.0)
+		assert.Nil(t, err)
+		fruit.Tenant = tenantID
+		fruit.ID = "banana-id"
+		assert.Nil(t, r.Save(ctx, fruit))
+	}
+
+	found, err := r.Get(ctx, "acme", "banana-id")
+	assert.Nil(t, err)
+	assert.Equal(t, found.Tenant, "acme")
+
+	_, err = r.Get(ctx, "initech", "banana-id")
+	assert.Equal(t, domainerror.KindOf(err), domainerror.NotFound)
+
+	found.Quantity = 5
+	assert.Nil(t, r.Save(ctx, found))
+
+	globex, err := r.Get(ctx, "globex", "banana-id")
+	assert.Nil(t, err)
+	assert.Equal(t, globex.Quantity, 1)
+
+	result, err := r.Search(ctx, &protocol.FruitSearchFilter{Tenant: "acme", Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 1)
+	assert.Equal(t, result.Results[0].Quantity, 5)
+
+	result, err = r.Search(ctx, &protocol.FruitSearchFilter{AllTenants: true, Name: "banana", Statuses: []entity.FruitStatus{entity.StatusComestible}}, 1, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, result.Paging.Total, 2)
+
+	assert.Nil(t, r.Delete(ctx, "globex", "banana-id", 1))
+	_, err = r.Get(ctx, "acme", "banana-id")
+	assert.Nil(t, err)
+}
+
+func TestFruitSQLiteRepository_SearchFilters(t *testing.T) {
+	testSearchFilters(t, newSQLiteRepository(t))
+}
+
+func TestFruitSQLiteRepository_SearchSort(t *testing.T) {
+	testSearchSort(t, newSQLiteRepository(t))
+}
+
+func TestFruitSQLiteRepository_SearchCursor(t *testing.T) {
+	testSearchCursor(t, newSQLiteRepository(t))
+}
+
+func TestFruitSQLiteRepository_SearchFuzzy(t *testing.T) {
+	testSearchFuzzy(t, newSQLiteRepository(t))
+}
@@ -0,0 +1,90 @@
+package search
+
+// Index an inverted index from the folded words of texts to the keys of those texts, scoring keys against
+// queries like Score without going through every text. Not safe for concurrent use, callers synchronize.
+type Index[K comparable] struct {
+	postings map[string]map[K]struct{}
+	words    map[K][]string
+}
+
+func NewIndex[K comparable]() *Index[K] {
+	return &Index[K]{
+		postings: map[string]map[K]struct{}{},
+		words:    map[K][]string{},
+	}
+}
+
+// Put index text under key, replacing the text indexed under key before
+func (i *Index[K]) Put(key K, text string) {
+	i.Remove(key)
+
+	words := Tokenize(text)
+	if len(words) == 0 {
+		return
+	}
+
+	i.words[key] = words
+	for _, word := range words {
+		keys, ok := i.postings[word]
+		if !ok {
+			keys = map[K]struct{}{}
+			i.postings[word] = keys
+		}
+		keys[key] = struct{}{}
+	}
+}
+
+func (i *Index[K]) Remove(key K) {
+	for _, word := range i.words[key] {
+		keys := i.postings[word]
+		delete(keys, key)
+		if len(keys) == 0 {
+			delete(i.postings, word)
+		}
+	}
+
+	delete(i.words, key)
+}
+
+// Search score every key whose text matches query, leaving out the ones Score would give 0
+func (i *Index[K]) Search(query string) map[K]float64 {
+	scores := map[K]float64{}
+
+	queryWords := Tokenize(query)
+	if len(queryWords) == 0 {
+		return scores
+	}
+
+	// the vocabulary is matched once per query word, the texts then only look their words up
+	matched := make([]map[string]float64, len(queryWords))
+	var candidates map[K]struct{}
+
+	for qi, queryWord := range queryWords {
+		matched[qi] = map[string]float64{}
+		keys := map[K]struct{}{}
+
+		for word, postings := range i.postings {
+			if s := matchWord(queryWord, word); s > 0 {
+				matched[qi][word] = s
+				for key := range postings {
+					if _, ok := candidates[key]; candidates == nil || ok {
+						keys[key] = struct{}{}
+					}
+				}
+			}
+		}
+
+		candidates = keys
+		if len(candidates) == 0 {
+			return scores
+		}
+	}
+
+	for key := range candidates {
+		scores[key] = score(queryWords, i.words[key], func(qi int, word string) float64 {
+			return matched[qi][word]
+		})
+	}
+
+	return scores
+}
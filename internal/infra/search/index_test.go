@@ -0,0 +1,42 @@
+package search_test
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/search"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestIndex(t *testing.T) {
+	index := search.NewIndex[string]()
+	index.Put("1", "Maçã verde")
+	index.Put("2", "Banana-prata")
+	index.Put("3", "banana")
+	index.Put("4", "uva")
+
+	t.Run("Score like Score", func(t *testing.T) {
+		for _, query := range []string{"banana", "bananna", "banan prata", "maca", "MAÇÃ verd", "uva"} {
+			scores := index.Search(query)
+			for key, text := range map[string]string{"1": "Maçã verde", "2": "Banana-prata", "3": "banana", "4": "uva"} {
+				score, ok := scores[key]
+				assert.Equal(t, ok, search.Score(query, text) > 0, query)
+				assert.Equal(t, score, search.Score(query, text), query)
+			}
+		}
+	})
+
+	t.Run("Without match", func(t *testing.T) {
+		assert.Empty(t, index.Search("kiwi"))
+		assert.Empty(t, index.Search("banana kiwi"))
+		assert.Empty(t, index.Search("!"))
+	})
+
+	t.Run("Replace and remove texts", func(t *testing.T) {
+		index.Put("3", "kiwi")
+		assert.Equal(t, index.Search("banana"), map[string]float64{"2": search.Score("banana", "Banana-prata")})
+		assert.Equal(t, index.Search("kiwi"), map[string]float64{"3": 1})
+
+		index.Remove("3")
+		index.Remove("404")
+		assert.Empty(t, index.Search("kiwi"))
+	})
+}
@@ -0,0 +1,159 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Fold lowercase s and strip its diacritics, so "Maçã" and "maca" are folded alike
+func Fold(s string) string {
+	// transformers keep state, so each call needs its own chain
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+
+	return strings.ToLower(folded)
+}
+
+// Tokenize split s into its folded words, anything but letters and digits separating them
+func Tokenize(s string) []string {
+	return strings.FieldsFunc(Fold(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// Distance the Levenshtein distance between a and b, counted in runes
+func Distance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(rb)]
+}
+
+// maxEdits the typos tolerated in a query word, none in short words where any edit gives another word
+func maxEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 3:
+		return 0
+	case n <= 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// matchWord score how well a folded query word matches a folded word of a text, from 0 (no match) to 1 (same word).
+// Prefixes score above typos, so "banan" ranks banana before "banal".
+func matchWord(query string, word string) float64 {
+	if query == word {
+		return 1
+	}
+
+	q, w := len([]rune(query)), len([]rune(word))
+
+	if q >= 2 && strings.HasPrefix(word, query) {
+		return 0.5 + 0.4*float64(q)/float64(w)
+	}
+
+	edits := maxEdits(query)
+	if edits == 0 || abs(q-w) > edits {
+		return 0
+	}
+
+	d := Distance(query, word)
+	if d > edits {
+		return 0
+	}
+
+	return 0.8 * (1 - float64(d)/float64(max(q, w)))
+}
+
+// score combine the best match of every query word among words, zero unless every query word matches.
+// Texts with words the query doesn't mention score slightly lower, so "apple" ranks apple before "green apple".
+func score(query []string, words []string, match func(qi int, word string) float64) float64 {
+	if len(query) == 0 || len(words) == 0 {
+		return 0
+	}
+
+	var total float64
+	for qi := range query {
+		var best float64
+		for _, word := range words {
+			if s := match(qi, word); s > best {
+				best = s
+			}
+		}
+
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+
+	coverage := float64(len(query)) / float64(len(words))
+	if coverage > 1 {
+		coverage = 1
+	}
+
+	relevance := total / float64(len(query)) * (0.9 + 0.1*coverage)
+	return math.Round(relevance*1000) / 1000
+}
+
+// Score the relevance of text to query, from 0 when some query word is missing from text to 1 for the same words
+func Score(query string, text string) float64 {
+	words := Tokenize(query)
+	return score(words, Tokenize(text), func(qi int, word string) float64 {
+		return matchWord(words[qi], word)
+	})
+}
+
+func min(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
+
+func max(a int, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,47 @@
+package search_test
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/search"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFold(t *testing.T) {
+	assert.Equal(t, search.Fold("Maçã"), "maca")
+	assert.Equal(t, search.Fold("PITAYA"), "pitaya")
+	assert.Equal(t, search.Fold("Açaí"), search.Fold("acai"))
+}
+
+func TestTokenize(t *testing.T) {
+	assert.Equal(t, search.Tokenize("Banana-prata, maçã  VERDE 2"), []string{"banana", "prata", "maca", "verde", "2"})
+	assert.Empty(t, search.Tokenize(" -- "))
+}
+
+func TestDistance(t *testing.T) {
+	assert.Equal(t, search.Distance("banana", "banana"), 0)
+	assert.Equal(t, search.Distance("bananna", "banana"), 1)
+	assert.Equal(t, search.Distance("kiwi", "kiw"), 1)
+	assert.Equal(t, search.Distance("uva", "pera"), 3)
+	assert.Equal(t, search.Distance("", "uva"), 3)
+	assert.Equal(t, search.Distance("maçã", "maca"), 2)
+}
+
+func TestScore(t *testing.T) {
+	assert.Equal(t, search.Score("banana", "Banana"), 1.0)
+	assert.Equal(t, search.Score("maca", "Maçã"), 1.0)
+
+	// typos and prefixes match, below the exact word
+	typo := search.Score("bananna", "banana")
+	prefix := search.Score("banan", "banana")
+	assert.Greater(t, typo, 0.0)
+	assert.Greater(t, prefix, typo)
+	assert.Less(t, prefix, 1.0)
+
+	// every word of the query must match, extra words in the text cost a little
+	assert.Equal(t, search.Score("banana prata", "banana"), 0.0)
+	assert.Greater(t, search.Score("banana", "banana"), search.Score("banana", "banana prata"))
+
+	// short words tolerate no typo
+	assert.Equal(t, search.Score("uvo", "uva"), 0.0)
+	assert.Equal(t, search.Score("", "uva"), 0.0)
+}
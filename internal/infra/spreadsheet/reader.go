@@ -0,0 +1,328 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/search"
+)
+
+// Format the kind of file fruit rows are read from
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+)
+
+// MaxFileSize the largest file fruit rows are read from
+const MaxFileSize = 10 << 20
+
+const (
+	// maxUnzipSize the most a xlsx file may hold once uncompressed. Far above what the rows of an import take, it
+	// keeps a zip bomb from exhausting the memory.
+	maxUnzipSize = 10 * MaxFileSize
+	// maxUnzipXMLSize the largest sheet unzipped in memory, larger ones being unzipped to a temporary file
+	maxUnzipXMLSize = MaxFileSize
+)
+
+var errFileTooLarge = fmt.Errorf("file must be at most %d MiB", MaxFileSize>>20)
+
+// FruitRow a row of fruit, its values as written in the file
+type FruitRow struct {
+	Line     int // in the file, counting the header
+	Name     string
+	Quantity string
+	Price    string
+}
+
+// Fields the fruit fields columns can be mapped to
+var Fields = []string{"name", "quantity", "price"}
+
+// DefaultColumns the headers recognized for each fruit field when no mapping is given
+var DefaultColumns = map[string]string{
+	"name":       "name",
+	"fruit":      "name",
+	"nome":       "name",
+	"fruta":      "name",
+	"quantity":   "quantity",
+	"qty":        "quantity",
+	"quantidade": "quantity",
+	"price":      "price",
+	"unit price": "price",
+	"preco":      "price",
+}
+
+// FormatOf tell the format of a file from the extension of its name
+func FormatOf(filename string) (Format, error) {
+	switch format := Format(strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))); format {
+	case CSV, XLSX:
+		return format, nil
+	default:
+		return "", invalidFile(fmt.Sprintf("file must be a %s or a %s file", CSV, XLSX))
+	}
+}
+
+// ParseColumns read a mapping written as "Header=field,Other header=field", the fields being the Fields ones
+func ParseColumns(s string) (map[string]string, error) {
+	columns := map[string]string{}
+
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		header, field, ok := strings.Cut(pair, "=")
+		field = strings.TrimSpace(field)
+		if !ok || strings.TrimSpace(header) == "" || !isField(field) {
+			return nil, invalidColumns(fmt.Sprintf("%q must map a header to one of %s", pair, strings.Join(Fields, ", ")))
+		}
+
+		columns[header] = field
+	}
+
+	if len(columns) == 0 {
+		return nil, invalidColumns("columns must map at least one header")
+	}
+
+	return columns, nil
+}
+
+// ReadFruitRows read the fruits of a csv file, or of the first sheet of a xlsx file, the first row holding the headers.
+// columns map headers to fruit fields, headers being compared ignoring case, accents and surrounding spaces; nil maps
+// the DefaultColumns. Columns mapped to no field are ignored, and so are blank rows.
+// Files larger than MaxFileSize are refused, and reading stops as soon as the file holds more than maxRows rows.
+func ReadFruitRows(r io.Reader, format Format, columns map[string]string, maxRows int) ([]*FruitRow, error) {
+	if columns == nil {
+		columns = DefaultColumns
+	}
+
+	r = &cappedReader{r: r}
+
+	var lines func(yield func(line int, cells []string) error) error
+	switch format {
+	case CSV:
+		lines = csvLines(r)
+	case XLSX:
+		lines = xlsxLines(r)
+	default:
+		return nil, invalidFile(fmt.Sprintf("file must be a %s or a %s file", CSV, XLSX))
+	}
+
+	var (
+		rows  []*FruitRow
+		index map[string]int
+	)
+
+	err := lines(func(line int, cells []string) error {
+		if index == nil {
+			var err error
+			index, err = indexHeaders(cells, columns)
+			return err
+		}
+
+		if isBlank(cells) {
+			return nil
+		}
+
+		if len(rows) == maxRows {
+			return invalidFile(fmt.Sprintf("file must hold at most %d rows", maxRows))
+		}
+
+		rows = append(rows, &FruitRow{
+			Line:     line,
+			Name:     cell(cells, index["name"]),
+			Quantity: cell(cells, index["quantity"]),
+			Price:    cell(cells, index["price"]),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if index == nil {
+		return nil, invalidFile("file is empty")
+	}
+
+	return rows, nil
+}
+
+// indexHeaders find the column of each fruit field, refusing headers missing a field or mapping one twice
+func indexHeaders(headers []string, columns map[string]string) (map[string]int, error) {
+	fields := make(map[string]string, len(columns))
+	for header, field := range columns {
+		fields[normalize(header)] = field
+	}
+
+	index := map[string]int{}
+	var problems []string
+
+	for i, header := range headers {
+		field, ok := fields[normalize(header)]
+		if !ok {
+			continue
+		}
+
+		if previous, ok := index[field]; ok {
+			problems = append(problems, fmt.Sprintf("columns %q and %q are both mapped to %s", headers[previous], header, field))
+			continue
+		}
+		index[field] = i
+	}
+
+	var missing []string
+	for _, field := range Fields {
+		if _, ok := index[field]; !ok {
+			missing = append(missing, field)
+		}
+	}
+
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("no column is mapped to %s", strings.Join(missing, ", ")))
+	}
+
+	if len(problems) > 0 {
+		return nil, invalidFile(strings.Join(problems, ", "))
+	}
+
+	return index, nil
+}
+
+func csvLines(r io.Reader) func(yield func(line int, cells []string) error) error {
+	return func(yield func(line int, cells []string) error) error {
+		reader := csv.NewReader(r)
+		// rows may leave trailing cells out
+		reader.FieldsPerRecord = -1
+
+		for {
+			record, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if errors.Is(err, errFileTooLarge) {
+				return invalidFile(err.Error())
+			}
+			if err != nil {
+				return invalidFile(fmt.Sprintf("file is not a valid csv file: %s", err))
+			}
+
+			line, _ := reader.FieldPos(0)
+			if err := yield(line, record); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func xlsxLines(r io.Reader) func(yield func(line int, cells []string) error) error {
+	return func(yield func(line int, cells []string) error) error {
+		f, err := excelize.OpenReader(r, excelize.Options{UnzipSizeLimit: maxUnzipSize, UnzipXMLSizeLimit: maxUnzipXMLSize})
+		switch {
+		case errors.Is(err, errFileTooLarge):
+			return invalidFile(err.Error())
+		case err != nil && strings.HasPrefix(err.Error(), "unzip size exceeds"):
+			return invalidFile(fmt.Sprintf("file must be at most %d MiB once uncompressed", maxUnzipSize>>20))
+		case err != nil:
+			return invalidFile("file is not a valid xlsx file")
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil
+		}
+
+		rows, err := f.Rows(sheets[0])
+		if err != nil {
+			return invalidFile("file is not a valid xlsx file")
+		}
+		defer rows.Close()
+
+		for line := 1; rows.Next(); line++ {
+			// raw values, so numbers aren't read as the sheet formats them, like "$ 1.50"
+			cells, err := rows.Columns(excelize.Options{RawCellValue: true})
+			if err != nil {
+				return invalidFile("file is not a valid xlsx file")
+			}
+
+			if err := yield(line, cells); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// cappedReader fail reads past MaxFileSize, rather than silently truncating the file like io.LimitReader
+type cappedReader struct {
+	r    io.Reader
+	read int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.read > MaxFileSize {
+		return 0, errFileTooLarge
+	}
+
+	n, err := c.r.Read(p)
+	c.read += int64(n)
+	if c.read > MaxFileSize {
+		return n, errFileTooLarge
+	}
+
+	return n, err
+}
+
+// normalize fold header and collapse its spaces, dropping the byte order mark spreadsheet apps write before csv files
+func normalize(header string) string {
+	return strings.Join(strings.Fields(search.Fold(strings.TrimPrefix(header, "\ufeff"))), " ")
+}
+
+func isField(s string) bool {
+	for _, field := range Fields {
+		if s == field {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isBlank(cells []string) bool {
+	for _, c := range cells {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+func cell(cells []string, i int) string {
+	if i >= len(cells) {
+		return ""
+	}
+
+	return cells[i]
+}
+
+func invalidFile(message string) error {
+	var violations domainerror.Violations
+	violations.Add("file", message)
+	return violations.Err()
+}
+
+func invalidColumns(message string) error {
+	var violations domainerror.Violations
+	violations.Add("columns", message)
+	return violations.Err()
+}
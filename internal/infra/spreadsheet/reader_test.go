@@ -0,0 +1,162 @@
+package spreadsheet_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/spreadsheet"
+	"github.com/stretchr/testify/assert"
+	"github.com/xuri/excelize/v2"
+	"io"
+	"strings"
+	"testing"
+)
+
+// maxRows the row cap of the reads, small enough to reach quickly
+const maxRows = 100
+
+func TestFormatOf(t *testing.T) {
+	format, err := spreadsheet.FormatOf("supplier.CSV")
+	assert.Nil(t, err)
+	assert.Equal(t, format, spreadsheet.CSV)
+
+	format, err = spreadsheet.FormatOf("supplier.xlsx")
+	assert.Nil(t, err)
+	assert.Equal(t, format, spreadsheet.XLSX)
+
+	_, err = spreadsheet.FormatOf("supplier.pdf")
+	assert.EqualError(t, err, "file must be a csv or a xlsx file")
+}
+
+func TestParseColumns(t *testing.T) {
+	columns, err := spreadsheet.ParseColumns("Fruta=name, Estoque=quantity,Valor=price")
+	assert.Nil(t, err)
+	assert.Equal(t, columns, map[string]string{"Fruta": "name", " Estoque": "quantity", "Valor": "price"})
+
+	_, err = spreadsheet.ParseColumns("Fruta=color")
+	assert.EqualError(t, err, `"Fruta=color" must map a header to one of name, quantity, price`)
+
+	_, err = spreadsheet.ParseColumns(" , ")
+	assert.EqualError(t, err, "columns must map at least one header")
+}
+
+func TestReadFruitRows_CSV(t *testing.T) {
+	t.Run("With default columns", func(t *testing.T) {
+		file := "\ufeffFruit,Origin,Qty,Preço\n" +
+			"banana,brazil,10,2.5\n" +
+			",,,\n" +
+			"\"uva\nitalia\",chile,3\n"
+
+		rows, err := spreadsheet.ReadFruitRows(strings.NewReader(file), spreadsheet.CSV, nil, maxRows)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rows, []*spreadsheet.FruitRow{
+			{Line: 2, Name: "banana", Quantity: "10", Price: "2.5"},
+			{Line: 4, Name: "uva\nitalia", Quantity: "3", Price: ""},
+		})
+	})
+
+	t.Run("With mapped columns", func(t *testing.T) {
+		file := "Fruta,Estoque,Valor\npera,1,3\n"
+
+		rows, err := spreadsheet.ReadFruitRows(strings.NewReader(file), spreadsheet.CSV, map[string]string{"fruta": "name", "ESTOQUE": "quantity", "valor": "price"}, maxRows)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rows, []*spreadsheet.FruitRow{{Line: 2, Name: "pera", Quantity: "1", Price: "3"}})
+	})
+
+	t.Run("With invalid headers", func(t *testing.T) {
+		_, err := spreadsheet.ReadFruitRows(strings.NewReader("name,fruit,price\n"), spreadsheet.CSV, nil, maxRows)
+		assert.EqualError(t, err, `columns "name" and "fruit" are both mapped to name, no column is mapped to quantity`)
+
+		_, err = spreadsheet.ReadFruitRows(strings.NewReader(""), spreadsheet.CSV, nil, maxRows)
+		assert.EqualError(t, err, "file is empty")
+
+		_, err = spreadsheet.ReadFruitRows(strings.NewReader("name,quantity,price\n\"banana,1,2\n"), spreadsheet.CSV, nil, maxRows)
+		assert.ErrorContains(t, err, "file is not a valid csv file")
+	})
+
+	t.Run("With too many rows", func(t *testing.T) {
+		file := "name,quantity,price\n" + strings.Repeat("banana,1,2\n", maxRows)
+
+		rows, err := spreadsheet.ReadFruitRows(strings.NewReader(file), spreadsheet.CSV, nil, maxRows)
+		assert.Nil(t, err)
+		assert.Len(t, rows, maxRows)
+
+		_, err = spreadsheet.ReadFruitRows(strings.NewReader(file+"uva,1,2\n"), spreadsheet.CSV, nil, maxRows)
+		assert.EqualError(t, err, fmt.Sprintf("file must hold at most %d rows", maxRows))
+	})
+
+	t.Run("With too large file", func(t *testing.T) {
+		// blank lines are skipped, so only the size stops an endless file
+		file := io.MultiReader(strings.NewReader("name,quantity,price\n"), repeated('\n'))
+
+		_, err := spreadsheet.ReadFruitRows(file, spreadsheet.CSV, nil, maxRows)
+		assert.EqualError(t, err, "file must be at most 10 MiB")
+	})
+}
+
+func TestReadFruitRows_XLSX(t *testing.T) {
+	t.Run("Read the first sheet", func(t *testing.T) {
+		f := excelize.NewFile()
+		sheet := f.GetSheetName(0)
+		assert.Nil(t, f.SetSheetRow(sheet, "A1", &[]interface{}{"Name", "Quantity", "Unit price"}))
+		assert.Nil(t, f.SetSheetRow(sheet, "A2", &[]interface{}{"banana", 10, 2.5}))
+		assert.Nil(t, f.SetSheetRow(sheet, "A4", &[]interface{}{"uva", 3, 1.25}))
+
+		// a currency format must not leak into the prices
+		style, err := f.NewStyle(&excelize.Style{NumFmt: 164, CustomNumFmt: strPtr(`"$"#,##0.00`)})
+		assert.Nil(t, err)
+		assert.Nil(t, f.SetCellStyle(sheet, "C2", "C4", style))
+
+		var buffer bytes.Buffer
+		assert.Nil(t, f.Write(&buffer))
+
+		rows, err := spreadsheet.ReadFruitRows(&buffer, spreadsheet.XLSX, nil, maxRows)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rows, []*spreadsheet.FruitRow{
+			{Line: 2, Name: "banana", Quantity: "10", Price: "2.5"},
+			{Line: 4, Name: "uva", Quantity: "3", Price: "1.25"},
+		})
+	})
+
+	t.Run("With invalid file", func(t *testing.T) {
+		_, err := spreadsheet.ReadFruitRows(strings.NewReader("name,quantity,price"), spreadsheet.XLSX, nil, maxRows)
+		assert.EqualError(t, err, "file is not a valid xlsx file")
+	})
+
+	t.Run("With zip bomb", func(t *testing.T) {
+		var buffer bytes.Buffer
+		archive := zip.NewWriter(&buffer)
+		sheet, err := archive.Create("xl/worksheets/sheet1.xml")
+		assert.Nil(t, err)
+		_, err = io.CopyN(sheet, repeated(' '), 101<<20)
+		assert.Nil(t, err)
+		assert.Nil(t, archive.Close())
+		assert.Less(t, buffer.Len(), 1<<20)
+
+		_, err = spreadsheet.ReadFruitRows(&buffer, spreadsheet.XLSX, nil, maxRows)
+		assert.EqualError(t, err, "file must be at most 100 MiB once uncompressed")
+	})
+
+	t.Run("With too large file", func(t *testing.T) {
+		_, err := spreadsheet.ReadFruitRows(repeated(0), spreadsheet.XLSX, nil, maxRows)
+		assert.EqualError(t, err, "file must be at most 10 MiB")
+	})
+}
+
+// repeated an endless reader of b
+type repeated byte
+
+func (r repeated) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(r)
+	}
+
+	return len(p), nil
+}
+
+func strPtr(s string) *string {
+	return &s
+}
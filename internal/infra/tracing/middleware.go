@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Middleware start a server span per request, continuing the trace of an incoming traceparent header.
+// The span is carried by the request context, and its trace id added to the request scoped logger.
+func Middleware(tp trace.TracerProvider) gin.HandlerFunc {
+	t := tracer(tp)
+
+	return func(c *gin.Context) {
+		ctx := Propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		spanName := fmt.Sprintf("%s %s", c.Request.Method, route)
+		if route == "" {
+			spanName = fmt.Sprintf("%s unmatched", c.Request.Method)
+		}
+
+		ctx, span := t.Start(
+			ctx,
+			spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(semconv.HTTPServerAttributesFromHTTPRequest(ServiceName, route, c.Request)...),
+		)
+		defer span.End()
+
+		if span.SpanContext().IsValid() {
+			ctx = logger.WithContext(ctx, logger.FromContext(ctx).WithField("trace_id", span.SpanContext().TraceID().String()))
+		}
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(semconv.HTTPAttributesFromHTTPStatusCode(status)...)
+		span.SetStatus(semconv.SpanStatusFromHTTPStatusCodeAndSpanKind(status, trace.SpanKindServer))
+	}
+}
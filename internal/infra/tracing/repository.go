@@ -0,0 +1,71 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FruitRepository decorate a fruit repository so every call runs in its own "repository <method>" span
+type FruitRepository struct {
+	tracer trace.Tracer
+	next   protocol.FruitRepository
+}
+
+func NewFruitRepository(tp trace.TracerProvider, next protocol.FruitRepository) *FruitRepository {
+	return &FruitRepository{
+		tracer: tracer(tp),
+		next:   next,
+	}
+}
+
+func (fr *FruitRepository) Save(ctx context.Context, fruit *entity.Fruit) error {
+	ctx, span := fr.start(ctx, "Save", attribute.String("tenant", fruit.Tenant), attribute.String("fruit.id", fruit.ID))
+	defer span.End()
+
+	err := fr.next.Save(ctx, fruit)
+	recordError(span, err)
+
+	return err
+}
+
+func (fr *FruitRepository) Get(ctx context.Context, tenant string, id string) (*entity.Fruit, error) {
+	ctx, span := fr.start(ctx, "Get", attribute.String("tenant", tenant), attribute.String("fruit.id", id))
+	defer span.End()
+
+	fruit, err := fr.next.Get(ctx, tenant, id)
+	recordError(span, err)
+
+	return fruit, err
+}
+
+func (fr *FruitRepository) Search(ctx context.Context, filter *protocol.FruitSearchFilter, offset int, limit int) (*protocol.FruitSearchResult, error) {
+	ctx, span := fr.start(ctx, "Search", attribute.String("tenant", filter.Tenant), attribute.Int("search.offset", offset), attribute.Int("search.limit", limit))
+	defer span.End()
+
+	result, err := fr.next.Search(ctx, filter, offset, limit)
+	recordError(span, err)
+
+	if err == nil {
+		span.SetAttributes(attribute.Int("search.total", result.Paging.Total))
+	}
+
+	return result, err
+}
+
+func (fr *FruitRepository) Delete(ctx context.Context, tenant string, id string, version int) error {
+	ctx, span := fr.start(ctx, "Delete", attribute.String("tenant", tenant), attribute.String("fruit.id", id), attribute.Int("fruit.version", version))
+	defer span.End()
+
+	err := fr.next.Delete(ctx, tenant, id, version)
+	recordError(span, err)
+
+	return err
+}
+
+func (fr *FruitRepository) start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return fr.tracer.Start(ctx, "repository "+method, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+}
@@ -0,0 +1,67 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ServiceName identify the api in the tracing backend
+	ServiceName = "go-gin-fruits"
+
+	instrumentationName = "github.com/ruancaetano/go-gin-fruits"
+)
+
+// Propagator read and write the W3C traceparent and baggage headers
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// NewExporter build the span exporter named by kind: stdout pretty prints spans to out, otlp ships them
+// over http to endpoint (host:port). "none" has no exporter, spans are still propagated but never leave the process.
+// "memory" keeps every span in a *tracetest.InMemoryExporter for tests to inspect, it is never reset so it isn't
+// offered to the server.
+func NewExporter(ctx context.Context, kind string, endpoint string, out io.Writer) (sdktrace.SpanExporter, error) {
+	switch kind {
+	case "none":
+		return nil, nil
+	case "memory":
+		return tracetest.NewInMemoryExporter(), nil
+	case "stdout":
+		return stdouttrace.New(stdouttrace.WithWriter(out), stdouttrace.WithPrettyPrint())
+	case "otlp":
+		return otlptracehttp.New(ctx, otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
+	default:
+		return nil, fmt.Errorf("unknown tracing exporter %q", kind)
+	}
+}
+
+// NewTracerProvider build a provider sampling sampleRatio of the new traces, continuing the sampling
+// decision of incoming ones, and batching spans to exporter when there is one
+func NewTracerProvider(exporter sdktrace.SpanExporter, sampleRatio float64) *sdktrace.TracerProvider {
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceNameKey.String(ServiceName))),
+	}
+
+	if exporter != nil {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(options...)
+}
+
+func tracer(tp trace.TracerProvider) trace.Tracer {
+	return tp.Tracer(instrumentationName)
+}
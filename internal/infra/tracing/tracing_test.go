@@ -0,0 +1,211 @@
+package tracing_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/repository"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/tracing"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// spanRecorder the spans exported by a provider built the way the server builds it, through the memory exporter
+type spanRecorder struct {
+	t        *testing.T
+	provider *sdktrace.TracerProvider
+	exporter *tracetest.InMemoryExporter
+}
+
+func newRecordedProvider(t *testing.T) (*sdktrace.TracerProvider, *spanRecorder) {
+	exporter, err := tracing.NewExporter(context.Background(), "memory", "", nil)
+	assert.Nil(t, err)
+
+	tp := tracing.NewTracerProvider(exporter, 1)
+	t.Cleanup(func() {
+		assert.Nil(t, tp.Shutdown(context.Background()))
+	})
+
+	return tp, &spanRecorder{t: t, provider: tp, exporter: exporter.(*tracetest.InMemoryExporter)}
+}
+
+// Ended flush the batched spans, then return every ended span in the order they ended
+func (sr *spanRecorder) Ended() []sdktrace.ReadOnlySpan {
+	assert.Nil(sr.t, sr.provider.ForceFlush(context.Background()))
+	return sr.exporter.GetSpans().Snapshots()
+}
+
+func spanNamed(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+
+	t.Fatalf("span %q not recorded", name)
+	return nil
+}
+
+func attributeOf(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func makeRouter(tp *sdktrace.TracerProvider) *gin.Engine {
+	fruitRepository := tracing.NewFruitRepository(tp, repository.NewFruitMemoryRepository())
+	getFruitUseCase := tracing.TraceUseCase(tp, "get_fruit", usecase.NewGetFruitUseCase(fruitRepository, auth.NewOwnerAuthorizer(false)))
+
+	r := gin.New()
+	r.Use(tracing.Middleware(tp))
+	r.GET("/fruits/:id", func(c *gin.Context) {
+		_, err := getFruitUseCase.Execute(c.Request.Context(), &usecase.GetFruitUseCaseInputDTO{ID: c.Param("id")})
+		if err != nil {
+			c.Status(http.StatusNotFound)
+			return
+		}
+
+		c.Status(http.StatusOK)
+	})
+
+	return r
+}
+
+func TestMiddleware_SpanChain(t *testing.T) {
+	tp, recorder := newRecordedProvider(t)
+	r := makeRouter(tp)
+
+	rr := httptest.NewRecorder()
+	r.ServeHTTP(rr, httptest.NewRequest("GET", "/fruits/unknown", nil))
+	assert.Equal(t, rr.Code, http.StatusNotFound)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 3)
+
+	server := spanNamed(t, spans, "GET /fruits/:id")
+	useCase := spanNamed(t, spans, "usecase get_fruit")
+	repo := spanNamed(t, spans, "repository Get")
+
+	assert.Equal(t, useCase.Parent().SpanID(), server.SpanContext().SpanID())
+	assert.Equal(t, repo.Parent().SpanID(), useCase.SpanContext().SpanID())
+	assert.Equal(t, repo.SpanContext().TraceID(), server.SpanContext().TraceID())
+
+	assert.Equal(t, attributeOf(server, "http.status_code").AsInt64(), int64(http.StatusNotFound))
+	assert.Equal(t, attributeOf(repo, "fruit.id").AsString(), "unknown")
+
+	assert.Equal(t, useCase.Status().Code, codes.Error)
+	assert.Equal(t, attributeOf(useCase, "error.kind").AsString(), string(domainerror.NotFound))
+	assert.Equal(t, repo.Status().Code, codes.Error)
+	assert.Len(t, repo.Events(), 1)
+}
+
+func TestMiddleware_ContinueIncomingTrace(t *testing.T) {
+	tp, recorder := newRecordedProvider(t)
+	r := makeRouter(tp)
+
+	req := httptest.NewRequest("GET", "/fruits/unknown", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	server := spanNamed(t, recorder.Ended(), "GET /fruits/:id")
+	assert.Equal(t, server.SpanContext().TraceID().String(), "4bf92f3577b34da6a3ce929d0e0e4736")
+	assert.Equal(t, server.Parent().SpanID().String(), "00f067aa0ba902b7")
+	assert.True(t, server.Parent().IsRemote())
+}
+
+func TestMiddleware_UnmatchedRoute(t *testing.T) {
+	tp, recorder := newRecordedProvider(t)
+	r := makeRouter(tp)
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/unknown", nil))
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, spans[0].Name(), "POST unmatched")
+}
+
+func TestMiddleware_TraceIDInLogs(t *testing.T) {
+	tp, recorder := newRecordedProvider(t)
+	log, hook := test.NewNullLogger()
+
+	r := gin.New()
+	r.Use(tracing.Middleware(tp))
+	r.GET("/ping", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context()).Info("pong")
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest("GET", "/ping", nil)
+	req = req.WithContext(logger.WithContext(req.Context(), log.WithField("request_id", "req-123")))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	server := spanNamed(t, recorder.Ended(), "GET /ping")
+	assert.Equal(t, hook.LastEntry().Data["trace_id"], server.SpanContext().TraceID().String())
+	assert.Equal(t, hook.LastEntry().Data["request_id"], "req-123")
+}
+
+func TestFruitRepository_Spans(t *testing.T) {
+	tp, recorder := newRecordedProvider(t)
+	fruitRepository := tracing.NewFruitRepository(tp, repository.NewFruitMemoryRepository())
+
+	fruit, err := entity.NewFruit("banana", "john", 10, 2)
+	assert.Nil(t, err)
+	assert.Nil(t, fruitRepository.Save(context.Background(), fruit))
+
+	_, err = fruitRepository.Get(context.Background(), tenant.Default, fruit.ID)
+	assert.Nil(t, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, spans[0].Name(), "repository Save")
+	assert.Equal(t, spans[1].Name(), "repository Get")
+	assert.Equal(t, spans[1].Status().Code, codes.Unset)
+}
+
+func TestNewExporter(t *testing.T) {
+	exporter, err := tracing.NewExporter(context.Background(), "none", "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, exporter)
+
+	var out bytes.Buffer
+	exporter, err = tracing.NewExporter(context.Background(), "stdout", "", &out)
+	assert.Nil(t, err)
+
+	tp := tracing.NewTracerProvider(exporter, 1)
+	_, span := tp.Tracer("test").Start(context.Background(), "stdout span")
+	span.End()
+	assert.Nil(t, tp.Shutdown(context.Background()))
+	assert.Contains(t, out.String(), "stdout span")
+
+	exporter, err = tracing.NewExporter(context.Background(), "memory", "", nil)
+	assert.Nil(t, err)
+	assert.IsType(t, exporter, &tracetest.InMemoryExporter{})
+
+	_, err = tracing.NewExporter(context.Background(), "jaeger", "", nil)
+	assert.EqualError(t, err, `unknown tracing exporter "jaeger"`)
+}
+
+func TestNewTracerProvider_SampleRatio(t *testing.T) {
+	tp := tracing.NewTracerProvider(nil, 0)
+
+	_, span := tp.Tracer("test").Start(context.Background(), "dropped")
+	assert.False(t, span.SpanContext().IsSampled())
+	assert.True(t, span.SpanContext().IsValid())
+}
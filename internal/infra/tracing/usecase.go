@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"context"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type tracedUseCase[I any, O any] struct {
+	tracer trace.Tracer
+	name   string
+	next   protocol.UseCase[I, O]
+}
+
+// TraceUseCase decorate u so every execution runs in its own "usecase <name>" span
+func TraceUseCase[I any, O any](tp trace.TracerProvider, name string, u protocol.UseCase[I, O]) protocol.UseCase[I, O] {
+	return &tracedUseCase[I, O]{
+		tracer: tracer(tp),
+		name:   name,
+		next:   u,
+	}
+}
+
+func (tu *tracedUseCase[I, O]) Execute(ctx context.Context, input I) (O, error) {
+	ctx, span := tu.tracer.Start(ctx, "usecase "+tu.name, trace.WithAttributes(attribute.String("usecase.name", tu.name)))
+	defer span.End()
+
+	output, err := tu.next.Execute(ctx, input)
+	recordError(span, err)
+
+	return output, err
+}
+
+// recordError flag span as failed, telling the domain error kind apart
+func recordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetAttributes(attribute.String("error.kind", string(domainerror.KindOf(err))))
+	span.SetStatus(codes.Error, err.Error())
+}
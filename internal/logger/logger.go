@@ -0,0 +1,45 @@
+package logger
+
+import (
+	"context"
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+type contextKey struct{}
+
+// New build the application logger: json lines in release mode so they can be shipped as they are,
+// human-friendly text otherwise. Unknown levels fall back to info, the config validates them beforehand.
+func New(mode string, level string, out io.Writer) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(out)
+
+	if mode == "release" {
+		log.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		log.SetFormatter(&logrus.TextFormatter{FullTimestamp: true})
+	}
+
+	parsed, err := logrus.ParseLevel(level)
+	if err != nil {
+		parsed = logrus.InfoLevel
+	}
+	log.SetLevel(parsed)
+
+	return log
+}
+
+// WithContext return a copy of ctx carrying entry, so everything down the call chain logs with its fields
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, contextKey{}, entry)
+}
+
+// FromContext return the entry carried by ctx, or one over the standard logger when there is none
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	return logrus.NewEntry(logrus.StandardLogger())
+}
@@ -0,0 +1,52 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	t.Run("Json lines in release mode", func(t *testing.T) {
+		var out bytes.Buffer
+		log := logger.New("release", "info", &out)
+
+		log.WithField("fruit_id", "some-uuid").Info("fruit created")
+
+		var line map[string]interface{}
+		assert.Nil(t, json.Unmarshal(out.Bytes(), &line))
+		assert.Equal(t, line["msg"], "fruit created")
+		assert.Equal(t, line["fruit_id"], "some-uuid")
+		assert.Equal(t, line["level"], "info")
+	})
+
+	t.Run("Text in debug mode", func(t *testing.T) {
+		var out bytes.Buffer
+		log := logger.New("debug", "debug", &out)
+
+		log.Debug("fruit created")
+
+		assert.Contains(t, out.String(), `level=debug msg="fruit created"`)
+	})
+
+	t.Run("With unknown level", func(t *testing.T) {
+		log := logger.New("debug", "loud", &bytes.Buffer{})
+		assert.Equal(t, log.GetLevel(), logrus.InfoLevel)
+	})
+}
+
+func TestFromContext(t *testing.T) {
+	entry := logger.FromContext(context.Background())
+	assert.Equal(t, entry.Logger, logrus.StandardLogger())
+
+	log := logger.New("test", "info", &bytes.Buffer{})
+	ctx := logger.WithContext(context.Background(), log.WithField("request_id", "abc"))
+
+	entry = logger.FromContext(ctx)
+	assert.Equal(t, entry.Logger, log)
+	assert.Equal(t, entry.Data["request_id"], "abc")
+}
@@ -0,0 +1,22 @@
+package mocks
+
+import (
+	"context"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	"github.com/stretchr/testify/mock"
+)
+
+type AuthorizerMock struct {
+	mock.Mock
+}
+
+func (am *AuthorizerMock) Authorize(c context.Context, action auth.Action, f *entity.Fruit) error {
+	args := am.Called(c, action, f)
+	return args.Error(0)
+}
+
+func (am *AuthorizerMock) ReadScope(c context.Context) (string, error) {
+	args := am.Called(c)
+	return args.String(0), args.Error(1)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/stretchr/testify/mock"
+)
+
+type FruitCursorCodecMock struct {
+	mock.Mock
+}
+
+func (cm *FruitCursorCodecMock) Encode(cursor *protocol.FruitSearchCursor) (string, error) {
+	args := cm.Called(cursor)
+	return args.String(0), args.Error(1)
+}
+
+func (cm *FruitCursorCodecMock) Decode(token string) (*protocol.FruitSearchCursor, error) {
+	args := cm.Called(token)
+	return args.Get(0).(*protocol.FruitSearchCursor), args.Error(1)
+}
@@ -16,8 +16,8 @@ func (fr *FruitRepositoryMock) Save(c context.Context, f *entity.Fruit) error {
 	return args.Error(0)
 }
 
-func (fr *FruitRepositoryMock) Get(c context.Context, id string) (*entity.Fruit, error) {
-	args := fr.Called(c, id)
+func (fr *FruitRepositoryMock) Get(c context.Context, tenant string, id string) (*entity.Fruit, error) {
+	args := fr.Called(c, tenant, id)
 	return args.Get(0).(*entity.Fruit), args.Error(1)
 }
 
@@ -25,3 +25,8 @@ func (fr *FruitRepositoryMock) Search(ctx context.Context, filter *protocol.Frui
 	args := fr.Called(ctx, filter, offset, limit)
 	return args.Get(0).(*protocol.FruitSearchResult), args.Error(1)
 }
+
+func (fr *FruitRepositoryMock) Delete(c context.Context, tenant string, id string, version int) error {
+	args := fr.Called(c, tenant, id, version)
+	return args.Error(0)
+}
@@ -0,0 +1,20 @@
+package mocks
+
+import (
+	"context"
+	"github.com/stretchr/testify/mock"
+)
+
+// TransactorMock run fn unless an error is returned for InTransaction, then hand back fn's error
+type TransactorMock struct {
+	mock.Mock
+}
+
+func (tm *TransactorMock) InTransaction(c context.Context, fn func(context.Context) error) error {
+	args := tm.Called(c)
+	if err := args.Error(0); err != nil {
+		return err
+	}
+
+	return fn(c)
+}
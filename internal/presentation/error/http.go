@@ -1,11 +1,126 @@
 package error
 
+import (
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"net/http"
+)
+
+const ProblemContentType = "application/problem+json"
+
+const (
+	BadRequestProblem         = "/problems/bad-request"
+	NotFoundProblem           = "/problems/not-found"
+	ValidationProblem         = "/problems/validation-error"
+	ConflictProblem           = "/problems/conflict"
+	InternalProblem           = "/problems/internal-error"
+	PreconditionFailedProblem = "/problems/precondition-failed"
+	UnauthenticatedProblem    = "/problems/unauthenticated"
+	ForbiddenProblem          = "/problems/forbidden"
+	UnsupportedMediaProblem   = "/problems/unsupported-media-type"
+	FailedDependencyProblem   = "/problems/failed-dependency"
+	PayloadTooLargeProblem    = "/problems/payload-too-large"
+	NotAcceptableProblem      = "/problems/not-acceptable"
+)
+
+// HttpError is a RFC 7807 problem details document
 // swagger:response HttpError
 type HttpError struct {
+	Type     string            `json:"type"`
+	Title    string            `json:"title"`
+	Status   int               `json:"status"`
+	Detail   string            `json:"detail,omitempty"`
+	Instance string            `json:"instance,omitempty"`
+	Errors   []*FieldViolation `json:"errors,omitempty"`
+
+	// cause the error hidden behind an internal server error, logged when the problem is written
+	cause error
+}
+
+type FieldViolation struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Status  int    `json:"status"`
 }
 
 func (he *HttpError) Error() string {
-	return he.Message
+	return he.Detail
+}
+
+func NewBadRequestError(detail string) *HttpError {
+	return newHttpError(BadRequestProblem, http.StatusBadRequest, detail)
+}
+
+func NewUnsupportedMediaTypeError(detail string) *HttpError {
+	return newHttpError(UnsupportedMediaProblem, http.StatusUnsupportedMediaType, detail)
+}
+
+func NewPayloadTooLargeError(detail string) *HttpError {
+	return newHttpError(PayloadTooLargeProblem, http.StatusRequestEntityTooLarge, detail)
+}
+
+// NewNotAcceptableError the problem of a response asked in a format the endpoint can't produce
+func NewNotAcceptableError(detail string) *HttpError {
+	return newHttpError(NotAcceptableProblem, http.StatusNotAcceptable, detail)
+}
+
+// NewFailedDependencyError the problem of an operation undone or never run because another one failed
+func NewFailedDependencyError(detail string) *HttpError {
+	return newHttpError(FailedDependencyProblem, http.StatusFailedDependency, detail)
+}
+
+func NewInternalServerError() *HttpError {
+	return newHttpError(InternalProblem, http.StatusInternalServerError, "internal server error")
+}
+
+// NewHttpError translate domain errors to http errors, errors outside the domain taxonomy are hidden behind a 500
+func NewHttpError(err error) *HttpError {
+	switch domainerror.KindOf(err) {
+	case domainerror.NotFound:
+		return newHttpError(NotFoundProblem, http.StatusNotFound, err.Error())
+	case domainerror.Validation:
+		he := newHttpError(ValidationProblem, http.StatusUnprocessableEntity, err.Error())
+		for _, v := range domainerror.ViolationsOf(err) {
+			he.Errors = append(he.Errors, &FieldViolation{Field: v.Field, Message: v.Message})
+		}
+		return he
+	case domainerror.Conflict:
+		return newHttpError(ConflictProblem, http.StatusConflict, err.Error())
+	case domainerror.PreconditionFailed:
+		return newHttpError(PreconditionFailedProblem, http.StatusPreconditionFailed, err.Error())
+	case domainerror.Unauthenticated:
+		return newHttpError(UnauthenticatedProblem, http.StatusUnauthorized, err.Error())
+	case domainerror.Forbidden:
+		return newHttpError(ForbiddenProblem, http.StatusForbidden, err.Error())
+	default:
+		he := NewInternalServerError()
+		he.cause = err
+		return he
+	}
+}
+
+// Respond write he as an application/problem+json response, using the request path as problem instance.
+// The cause of internal server errors is logged, since it never reaches the client.
+func Respond(c *gin.Context, he *HttpError) {
+	if c.Request != nil {
+		if he.Instance == "" {
+			he.Instance = c.Request.URL.Path
+		}
+
+		if he.cause != nil {
+			logger.FromContext(c.Request.Context()).WithError(he.cause).Error("internal server error")
+		}
+	}
+
+	c.Header("Content-Type", ProblemContentType)
+	c.JSON(he.Status, he)
+}
+
+func newHttpError(problemType string, status int, detail string) *HttpError {
+	return &HttpError{
+		Type:   problemType,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
 }
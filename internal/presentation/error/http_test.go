@@ -1,16 +1,111 @@
 package error_test
 
 import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
 func TestHttpError_Error(t *testing.T) {
 	err := error2.HttpError{
-		Message: "invalid params",
-		Status:  400,
+		Detail: "invalid params",
+		Status: 400,
 	}
 
 	assert.Equal(t, err.Error(), "invalid params")
 }
+
+func TestNewHttpError(t *testing.T) {
+	err := error2.NewHttpError(domainerror.NewNotFoundError("fruit not found"))
+	assert.Equal(t, err.Status, http.StatusNotFound)
+	assert.Equal(t, err.Detail, "fruit not found")
+
+	err = error2.NewHttpError(domainerror.NewValidationError("name is required"))
+	assert.Equal(t, err.Status, http.StatusUnprocessableEntity)
+	assert.Equal(t, err.Detail, "name is required")
+
+	var violations domainerror.Violations
+	violations.Add("quantity", "quantity must be greater than zero")
+	violations.Add("price", "price must be greater than zero")
+	err = error2.NewHttpError(violations.Err())
+	assert.Equal(t, err.Type, error2.ValidationProblem)
+	assert.Equal(t, err.Detail, "quantity must be greater than zero; price must be greater than zero")
+	assert.Len(t, err.Errors, 2)
+	assert.Equal(t, err.Errors[1].Field, "price")
+
+	err = error2.NewHttpError(domainerror.NewConflictError("fruit already exists"))
+	assert.Equal(t, err.Status, http.StatusConflict)
+	assert.Equal(t, err.Detail, "fruit already exists")
+
+	err = error2.NewHttpError(domainerror.NewPreconditionFailedError("fruit is at version 2, not 1"))
+	assert.Equal(t, err.Status, http.StatusPreconditionFailed)
+	assert.Equal(t, err.Type, error2.PreconditionFailedProblem)
+	assert.Equal(t, err.Detail, "fruit is at version 2, not 1")
+
+	err = error2.NewHttpError(domainerror.NewUnauthenticatedError("invalid api key"))
+	assert.Equal(t, err.Status, http.StatusUnauthorized)
+	assert.Equal(t, err.Type, error2.UnauthenticatedProblem)
+	assert.Equal(t, err.Detail, "invalid api key")
+
+	err = error2.NewHttpError(domainerror.NewForbiddenError("only admins can purge fruits"))
+	assert.Equal(t, err.Status, http.StatusForbidden)
+	assert.Equal(t, err.Type, error2.ForbiddenProblem)
+	assert.Equal(t, err.Detail, "only admins can purge fruits")
+
+	err = error2.NewHttpError(domainerror.NewInternalError("fail to save fruit", errors.New("disk is full")))
+	assert.Equal(t, err.Status, http.StatusInternalServerError)
+	assert.Equal(t, err.Detail, "internal server error")
+
+	err = error2.NewHttpError(errors.New("unexpected"))
+	assert.Equal(t, err.Status, http.StatusInternalServerError)
+	assert.Equal(t, err.Detail, "internal server error")
+}
+
+func TestRespond(t *testing.T) {
+	rr := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rr)
+	ctx.Request = httptest.NewRequest("GET", "/fruits/some-uuid", nil)
+
+	error2.Respond(ctx, error2.NewHttpError(domainerror.NewNotFoundError("fruit not found")))
+
+	var response error2.HttpError
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Nil(t, err)
+	assert.Equal(t, rr.Code, http.StatusNotFound)
+	assert.Equal(t, rr.Header().Get("Content-Type"), "application/problem+json")
+	assert.Equal(t, response.Type, error2.NotFoundProblem)
+	assert.Equal(t, response.Title, "Not Found")
+	assert.Equal(t, response.Status, http.StatusNotFound)
+	assert.Equal(t, response.Detail, "fruit not found")
+	assert.Equal(t, response.Instance, "/fruits/some-uuid")
+}
+
+func TestRespond_LogInternalErrorCause(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	rr := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rr)
+	ctx.Request = httptest.NewRequest("GET", "/fruits/some-uuid", nil)
+	ctx.Request = ctx.Request.WithContext(logger.WithContext(ctx.Request.Context(), log.WithField("request_id", "req-123")))
+
+	error2.Respond(ctx, error2.NewHttpError(errors.New("disk is full")))
+
+	assert.Equal(t, rr.Code, http.StatusInternalServerError)
+	assert.NotContains(t, rr.Body.String(), "disk is full")
+	assert.Equal(t, hook.LastEntry().Data["request_id"], "req-123")
+	assert.EqualError(t, hook.LastEntry().Data[logrus.ErrorKey].(error), "disk is full")
+
+	hook.Reset()
+	error2.Respond(ctx, error2.NewHttpError(domainerror.NewNotFoundError("fruit not found")))
+	assert.Nil(t, hook.LastEntry())
+}
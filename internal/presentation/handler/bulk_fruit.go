@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+)
+
+type BulkFruitOperationDTO struct {
+	Op       string  `json:"op" enums:"create,update,delete"`
+	ID       string  `json:"id,omitempty"`
+	Name     string  `json:"name,omitempty"`
+	Quantity int     `json:"quantity,omitempty"`
+	Price    float64 `json:"price,omitempty"`
+	// Version only update the fruit when it is still at this version, like If-Match
+	Version *int `json:"version,omitempty"`
+}
+
+type BulkFruitRequestDTO struct {
+	// Atomic apply every operation or none, refused when the repository has no transactions
+	Atomic     bool                     `json:"atomic"`
+	Operations []*BulkFruitOperationDTO `json:"operations"`
+}
+
+type BulkFruitResultDTO struct {
+	Op string `json:"op"`
+	// Status the http status the operation would have been answered with on its own
+	Status int    `json:"status"`
+	ID     string `json:"id,omitempty"`
+	// Error why the operation failed, 424 for operations rolled back or skipped because another one failed
+	Error *error2.HttpError `json:"error,omitempty" swaggertype:"object"`
+}
+
+type BulkFruitResponseDTO struct {
+	Succeeded int                   `json:"succeeded"`
+	Failed    int                   `json:"failed"`
+	Results   []*BulkFruitResultDTO `json:"results"`
+}
+
+// bulkSuccessStatus the status of each operation when it succeeds
+var bulkSuccessStatus = map[usecase.BulkOp]int{
+	usecase.BulkCreate: http.StatusCreated,
+	usecase.BulkUpdate: http.StatusOK,
+	usecase.BulkDelete: http.StatusOK,
+}
+
+// MakeBulkFruitHandler generate handler function to http bulk fruit request
+// @Summary      Create, update and delete fruits in bulk
+// @Description  Apply a batch of operations, each one like its own endpoint would, and report the result of each in order. Created fruits belong to the caller. Atomic batches apply every operation or none, when the repository supports transactions.
+// @Tags         fruits
+// @Accept       json
+// @Produce      json
+// @Param		 body body BulkFruitRequestDTO true "Bulk request body"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {object} BulkFruitResponseDTO
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/bulk [post]
+func MakeBulkFruitHandler(u protocol.UseCase[*usecase.BulkFruitUseCaseInputDTO, *usecase.BulkFruitUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := principalOf(c)
+		if !ok {
+			return
+		}
+
+		body := &BulkFruitRequestDTO{}
+		err := c.ShouldBindJSON(body)
+		if err != nil {
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+			return
+		}
+
+		input := &usecase.BulkFruitUseCaseInputDTO{Atomic: body.Atomic}
+		for _, operation := range body.Operations {
+			if operation == nil {
+				error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+				return
+			}
+
+			input.Operations = append(input.Operations, &usecase.BulkFruitOperation{
+				Op:              usecase.BulkOp(operation.Op),
+				ID:              operation.ID,
+				Name:            operation.Name,
+				Owner:           principal.Subject,
+				Quantity:        operation.Quantity,
+				Price:           operation.Price,
+				ExpectedVersion: operation.Version,
+			})
+		}
+
+		output, err := u.Execute(c.Request.Context(), input)
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		// rolled back and skipped operations point to the operation that failed the batch
+		failed := -1
+		for i, item := range output.Items {
+			if item.Outcome == usecase.BulkFailed {
+				failed = i
+				break
+			}
+		}
+
+		response := &BulkFruitResponseDTO{
+			Succeeded: output.Succeeded,
+			Failed:    output.Failed,
+			Results:   make([]*BulkFruitResultDTO, len(output.Items)),
+		}
+
+		for i, item := range output.Items {
+			result := &BulkFruitResultDTO{Op: string(item.Op), ID: item.ID}
+
+			switch item.Outcome {
+			case usecase.BulkSucceeded:
+				result.Status = bulkSuccessStatus[item.Op]
+			case usecase.BulkFailed:
+				result.Error = error2.NewHttpError(item.Err)
+			case usecase.BulkRolledBack:
+				result.Error = error2.NewFailedDependencyError(fmt.Sprintf("rolled back, operation %d of the batch failed", failed))
+			case usecase.BulkSkipped:
+				result.Error = error2.NewFailedDependencyError(fmt.Sprintf("not applied, operation %d of the batch failed", failed))
+			}
+
+			if result.Error != nil {
+				result.Status = result.Error.Status
+			}
+			response.Results[i] = result
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type BulkFruitUseCaseMock struct {
+	mock.Mock
+}
+
+func (b *BulkFruitUseCaseMock) Execute(ctx context.Context, i *usecase.BulkFruitUseCaseInputDTO) (*usecase.BulkFruitUseCaseOutputDTO, error) {
+	args := b.Called(ctx, i)
+
+	return args.Get(0).(*usecase.BulkFruitUseCaseOutputDTO), args.Error(1)
+}
+
+func TestBulkFruitHandler(t *testing.T) {
+	t.Run("With invalid body", func(t *testing.T) {
+		u := &BulkFruitUseCaseMock{}
+		h := handler.MakeBulkFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+
+		r := httptest.NewRequest("POST", "/fruits/bulk", strings.NewReader(`{"operations": [null]}`))
+		ctx.Request = withPrincipal(r, "owner")
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request body")
+		u.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+	})
+
+	t.Run("When usecase fails", func(t *testing.T) {
+		var violations domainerror.Violations
+		violations.Add("atomic", "atomic batches need a repository with transactions")
+
+		u := &BulkFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.BulkFruitUseCaseOutputDTO{}, violations.Err())
+		h := handler.MakeBulkFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+
+		r := httptest.NewRequest("POST", "/fruits/bulk", strings.NewReader(`{"atomic": true, "operations": [{"op": "delete", "id": "id"}]}`))
+		ctx.Request = withPrincipal(r, "owner")
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+		assert.Equal(t, response.Errors[0].Field, "atomic")
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		version := 2
+		u := &BulkFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.BulkFruitUseCaseInputDTO{
+			Atomic: true,
+			Operations: []*usecase.BulkFruitOperation{
+				{Op: usecase.BulkCreate, Name: "uva", Owner: "owner", Quantity: 1, Price: 10.10},
+				{Op: usecase.BulkUpdate, ID: "missing-id", Owner: "owner", Quantity: 2, Price: 5, ExpectedVersion: &version},
+				{Op: usecase.BulkDelete, ID: "valid-id", Owner: "owner"},
+			},
+		}).Return(&usecase.BulkFruitUseCaseOutputDTO{
+			Items: []*usecase.BulkFruitUseCaseOutputItem{
+				{Op: usecase.BulkCreate, Outcome: usecase.BulkRolledBack, ID: "new-id"},
+				{Op: usecase.BulkUpdate, Outcome: usecase.BulkFailed, ID: "missing-id", Err: domainerror.NewNotFoundError("fruit not found")},
+				{Op: usecase.BulkDelete, Outcome: usecase.BulkSkipped, ID: "valid-id"},
+			},
+			Failed: 3,
+		}, nil)
+		h := handler.MakeBulkFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+
+		body := `{"atomic": true, "operations": [
+			{"op": "create", "name": "uva", "quantity": 1, "price": 10.10},
+			{"op": "update", "id": "missing-id", "quantity": 2, "price": 5, "version": 2},
+			{"op": "delete", "id": "valid-id"}
+		]}`
+		r := httptest.NewRequest("POST", "/fruits/bulk", strings.NewReader(body))
+		ctx.Request = withPrincipal(r, "owner")
+
+		var response handler.BulkFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.Succeeded, 0)
+		assert.Equal(t, response.Failed, 3)
+		assert.Len(t, response.Results, 3)
+
+		assert.Equal(t, response.Results[0].Op, "create")
+		assert.Equal(t, response.Results[0].ID, "new-id")
+		assert.Equal(t, response.Results[0].Status, http.StatusFailedDependency)
+		assert.Equal(t, response.Results[0].Error.Type, error2.FailedDependencyProblem)
+		assert.Equal(t, response.Results[0].Error.Detail, "rolled back, operation 1 of the batch failed")
+
+		assert.Equal(t, response.Results[1].Status, http.StatusNotFound)
+		assert.Equal(t, response.Results[1].Error.Detail, "fruit not found")
+
+		assert.Equal(t, response.Results[2].Status, http.StatusFailedDependency)
+		assert.Equal(t, response.Results[2].Error.Detail, "not applied, operation 1 of the batch failed")
+	})
+
+	t.Run("Success without failures", func(t *testing.T) {
+		u := &BulkFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.BulkFruitUseCaseOutputDTO{
+			Items: []*usecase.BulkFruitUseCaseOutputItem{
+				{Op: usecase.BulkCreate, Outcome: usecase.BulkSucceeded, ID: "new-id"},
+				{Op: usecase.BulkDelete, Outcome: usecase.BulkSucceeded, ID: "valid-id"},
+			},
+			Succeeded: 2,
+		}, nil)
+		h := handler.MakeBulkFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+
+		body := `{"operations": [{"op": "create", "name": "uva", "quantity": 1, "price": 10.10}, {"op": "delete", "id": "valid-id"}]}`
+		r := httptest.NewRequest("POST", "/fruits/bulk", strings.NewReader(body))
+		ctx.Request = withPrincipal(r, "owner")
+
+		var response handler.BulkFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.Succeeded, 2)
+		assert.Equal(t, response.Results[0].Status, http.StatusCreated)
+		assert.Nil(t, response.Results[0].Error)
+		assert.Equal(t, response.Results[1].Status, http.StatusOK)
+	})
+}
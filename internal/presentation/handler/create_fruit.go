@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"net/http"
 	"time"
 )
@@ -32,40 +33,40 @@ type CreateFruitResponseDTO struct {
 // @Accept       json
 // @Produce      json
 // @Param		 body body CreateFruitRequestDTO true "Create fruit request body"
-// @Param		 x-owner header string true "fruit owner"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
 // @Success		 201 {object} CreateFruitResponseDTO
 // @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
 // @Failure		 500 {object} error.HttpError
 // @Router       /fruits/ [post]
 func MakeCreateFruitHandler(u protocol.UseCase[*usecase.CreateFruitUseCaseInputDTO, *usecase.CreateFruitUseCaseOutputDTO]) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		principal, ok := principalOf(c)
+		if !ok {
+			return
+		}
 
 		body := &CreateFruitRequestDTO{}
-		err := c.BindJSON(body)
+		err := c.ShouldBindJSON(body)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "invalid request body",
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
 			return
 		}
 
-		owner := c.GetHeader("x-owner")
-
 		input := &usecase.CreateFruitUseCaseInputDTO{
 			Name:     body.Name,
 			Price:    body.Price,
 			Quantity: body.Quantity,
-			Owner:    owner,
+			Owner:    principal.Subject,
 		}
 
 		output, err := u.Execute(c.Request.Context(), input)
 
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": err.Error(),
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
@@ -3,9 +3,9 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
@@ -36,7 +36,7 @@ func TestCraeteFruitHandler(t *testing.T) {
 		ctx, _ := gin.CreateTestContext(rr)
 
 		r := httptest.NewRequest("POST", "/fruits", nil)
-		ctx.Request = r
+		ctx.Request = withPrincipal(r, "owner")
 
 		var response error2.HttpError
 		h(ctx)
@@ -47,12 +47,16 @@ func TestCraeteFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "invalid request body")
+		assert.Equal(t, response.Detail, "invalid request body")
 	})
 
 	t.Run("When usecase fails", func(t *testing.T) {
+		var violations domainerror.Violations
+		violations.Add("name", "name is required")
+		violations.Add("owner", "owner is required")
+
 		u := &CreateFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.CreateFruitUseCaseOutputDTO{}, errors.New("name is required"))
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.CreateFruitUseCaseOutputDTO{}, violations.Err())
 		h := handler.MakeCreateFruitHandler(u)
 
 		rr := httptest.NewRecorder()
@@ -60,7 +64,7 @@ func TestCraeteFruitHandler(t *testing.T) {
 
 		body := `{"name": "", "quantity": 1, "price": 10.10}`
 		r := httptest.NewRequest("POST", "/fruits", strings.NewReader(body))
-		ctx.Request = r
+		ctx.Request = withPrincipal(r, "owner")
 
 		var response error2.HttpError
 		h(ctx)
@@ -70,22 +74,31 @@ func TestCraeteFruitHandler(t *testing.T) {
 		}
 
 		assert.Nil(t, err)
-		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "name is required")
+		assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+		assert.Equal(t, rr.Header().Get("Content-Type"), "application/problem+json")
+		assert.Equal(t, response.Type, error2.ValidationProblem)
+		assert.Equal(t, response.Title, "Unprocessable Entity")
+		assert.Equal(t, response.Instance, "/fruits")
+		assert.Equal(t, response.Detail, "name is required; owner is required")
+		assert.Len(t, response.Errors, 2)
+		assert.Equal(t, response.Errors[0].Field, "name")
+		assert.Equal(t, response.Errors[0].Message, "name is required")
+		assert.Equal(t, response.Errors[1].Field, "owner")
+		assert.Equal(t, response.Errors[1].Message, "owner is required")
 	})
 
 	t.Run("Success", func(t *testing.T) {
 		fruitMock, _ := entity.NewFruit("uva", "owner", 1, 10.10)
 
 		u := &CreateFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.CreateFruitUseCaseOutputDTO{
+		u.On("Execute", mock.Anything, &usecase.CreateFruitUseCaseInputDTO{Name: "uva", Quantity: 1, Price: 10.10, Owner: "owner"}).Return(&usecase.CreateFruitUseCaseOutputDTO{
 			ID:        fruitMock.ID,
 			CreatedAt: fruitMock.CreatedAt,
 			UpdatedAt: fruitMock.UpdatedAt,
 			Name:      fruitMock.Name,
 			Quantity:  fruitMock.Quantity,
 			Price:     fruitMock.Price,
-			Status:    fruitMock.Status,
+			Status:    string(fruitMock.Status),
 			Owner:     fruitMock.Owner,
 		}, nil)
 		h := handler.MakeCreateFruitHandler(u)
@@ -95,8 +108,7 @@ func TestCraeteFruitHandler(t *testing.T) {
 
 		body := `{"name": "uva", "quantity": 1, "price": 10.10}`
 		r := httptest.NewRequest("POST", "/fruits", strings.NewReader(body))
-		r.Header.Set("x-owner", "owner")
-		ctx.Request = r
+		ctx.Request = withPrincipal(r, "owner")
 
 		var response handler.CreateFruitResponseDTO
 		h(ctx)
@@ -112,7 +124,7 @@ func TestCraeteFruitHandler(t *testing.T) {
 		assert.Equal(t, response.Owner, fruitMock.Owner)
 		assert.Equal(t, response.Price, fruitMock.Price)
 		assert.Equal(t, response.Quantity, fruitMock.Quantity)
-		assert.Equal(t, response.Status, fruitMock.Status)
+		assert.Equal(t, response.Status, string(fruitMock.Status))
 		assert.True(t, response.CreatedAt.Equal(fruitMock.CreatedAt))
 		assert.True(t, response.UpdatedAt.Equal(fruitMock.UpdatedAt))
 	})
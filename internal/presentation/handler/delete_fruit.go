@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"net/http"
 	"time"
 )
@@ -26,8 +27,15 @@ type DeleteFruitResponseDTO struct {
 // @Accept       json
 // @Produce      json
 // @Param		 id path string true "Fruit id"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
 // @Success		 200 {object} DeleteFruitResponseDTO
 // @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
 // @Failure		 500 {object} error.HttpError
 // @Router       /fruits/{id} [delete]
 func MakeDeleteFruitHandler(u protocol.UseCase[*usecase.DeleteFruitUseCaseInputDTO, *usecase.DeleteFruitUseCaseOutputDTO]) gin.HandlerFunc {
@@ -35,10 +43,7 @@ func MakeDeleteFruitHandler(u protocol.UseCase[*usecase.DeleteFruitUseCaseInputD
 
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "invalid request param",
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
 			return
 		}
 
@@ -49,10 +54,7 @@ func MakeDeleteFruitHandler(u protocol.UseCase[*usecase.DeleteFruitUseCaseInputD
 		output, err := u.Execute(c.Request.Context(), input)
 
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": err.Error(),
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
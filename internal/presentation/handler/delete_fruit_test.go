@@ -3,9 +3,9 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
@@ -49,12 +49,12 @@ func TestDeleteFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "invalid request param")
+		assert.Equal(t, response.Detail, "invalid request param")
 	})
 
 	t.Run("With usecase fail", func(t *testing.T) {
 		u := &DeleteFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.DeleteFruitUseCaseOutputDTO{}, errors.New("item not found"))
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.DeleteFruitUseCaseOutputDTO{}, domainerror.NewNotFoundError("fruit not found"))
 
 		h := handler.MakeDeleteFruitHandler(u)
 
@@ -75,8 +75,8 @@ func TestDeleteFruitHandler(t *testing.T) {
 		}
 
 		assert.Nil(t, err)
-		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "item not found")
+		assert.Equal(t, rr.Code, http.StatusNotFound)
+		assert.Equal(t, response.Detail, "fruit not found")
 
 	})
 
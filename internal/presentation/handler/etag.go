@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"strconv"
+	"strings"
+
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+)
+
+// etag format a fruit version as a strong entity tag, e.g. "3"
+func etag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+// parseIfMatch read the fruit version expected by an If-Match header, nil when the header is absent or "*".
+// Anything but a single strong fruit etag can never match, so it fails the precondition.
+func parseIfMatch(header string) (*int, error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+
+	unquoted, err := strconv.Unquote(header)
+	if err != nil || !strings.HasPrefix(header, `"`) {
+		return nil, domainerror.NewPreconditionFailedError("if-match must be a single fruit etag")
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil {
+		return nil, domainerror.NewPreconditionFailedError("if-match must be a single fruit etag")
+	}
+
+	return &version, nil
+}
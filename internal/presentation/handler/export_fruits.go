@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	csvMediaType    = "text/csv"
+	ndjsonMediaType = "application/x-ndjson"
+	jsonMediaType   = "application/json"
+)
+
+// exportFormat how exported fruits are encoded, the media type being sent back as the response content type
+type exportFormat struct {
+	mediaType  string
+	extension  string
+	newEncoder func(w io.Writer) exportEncoder
+}
+
+var exportFormats = map[string]*exportFormat{
+	"csv":    {mediaType: csvMediaType + "; charset=utf-8", extension: "csv", newEncoder: newCSVEncoder},
+	"ndjson": {mediaType: ndjsonMediaType, extension: "ndjson", newEncoder: newNDJSONEncoder},
+	"json":   {mediaType: jsonMediaType + "; charset=utf-8", extension: "json", newEncoder: newJSONEncoder},
+}
+
+// exportMediaTypes the formats of the media types an export negotiates, json first as it is answered to clients
+// accepting anything
+var exportMediaTypes = []struct{ mediaType, format string }{
+	{jsonMediaType, "json"},
+	{csvMediaType, "csv"},
+	{ndjsonMediaType, "ndjson"},
+	{"application/ndjson", "ndjson"},
+}
+
+// exportEncoder write exported fruits in one format: begin before the first fruit, end after the last
+type exportEncoder interface {
+	begin() error
+	write(fruit *SearchFruitResponseResult) error
+	end() error
+}
+
+// MakeExportFruitsHandler generate handler function to http export fruits request
+// @Summary      Export fruits
+// @Description  Stream every fruit matching the search filters as csv, ndjson or a json array, by creation date unless sorted, within the export timeout. The format is taken from the format param, or else negotiated with the Accept header, json being the default.
+// @Tags         fruits
+// @Produce      json
+// @Produce      text/csv
+// @Produce      application/x-ndjson
+// @Param		 format query string false "Format of the export, overriding the Accept header" Enums(csv, ndjson, json)
+// @Param		 name query string false "Part of the fruit name"
+// @Param		 match query string false "How the name is matched, exports only take contains" Enums(contains)
+// @Param		 status query []string false "Fruit statuses, repeated or comma separated" collectionFormat(multi)
+// @Param		 owner query string false "Fruit owner"
+// @Param		 min_price query number false "Minimum price, inclusive"
+// @Param		 max_price query number false "Maximum price, inclusive"
+// @Param		 min_quantity query int false "Minimum quantity, inclusive"
+// @Param		 max_quantity query int false "Maximum quantity, inclusive"
+// @Param		 created_after query string false "Created after this RFC 3339 instant"
+// @Param		 created_before query string false "Created before this RFC 3339 instant"
+// @Param		 updated_after query string false "Last updated after this RFC 3339 instant"
+// @Param		 updated_before query string false "Last updated before this RFC 3339 instant"
+// @Param		 sort query string false "Comma separated fields to order by, descending when prefixed by -: name, price, quantity, createdAt, updatedAt" example(-price,name)
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {array} SearchFruitResponseResult
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 406 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/export [get]
+func MakeExportFruitsHandler(u protocol.UseCase[*usecase.ExportFruitsUseCaseInputDTO, *usecase.ExportFruitsUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		format, problem := exportFormatOf(c)
+		if problem != nil {
+			error2.Respond(c, problem)
+			return
+		}
+
+		criteria, ok := searchCriteriaOf(c)
+		if !ok {
+			return
+		}
+
+		encoder := format.newEncoder(c.Writer)
+
+		// the status and headers are only sent with the first fruit, so a refused export still gets its problem
+		started := false
+		start := func() error {
+			if started {
+				return nil
+			}
+			started = true
+
+			c.Header("Content-Type", format.mediaType)
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="fruits.%s"`, format.extension))
+			c.Status(http.StatusOK)
+
+			return encoder.begin()
+		}
+
+		_, err := u.Execute(c.Request.Context(), &usecase.ExportFruitsUseCaseInputDTO{
+			Criteria: criteria,
+			Write: func(r *usecase.SearchFruitUseCaseOutputResult) error {
+				if err := start(); err != nil {
+					return err
+				}
+
+				return encoder.write(&SearchFruitResponseResult{
+					ID:        r.ID,
+					CreatedAt: r.CreatedAt,
+					UpdatedAt: r.UpdatedAt,
+					Name:      r.Name,
+					Owner:     r.Owner,
+					Quantity:  r.Quantity,
+					Price:     r.Price,
+					Status:    r.Status,
+					Score:     r.Score,
+				})
+			},
+		})
+
+		if err == nil {
+			if err = start(); err == nil {
+				err = encoder.end()
+			}
+		}
+
+		if err != nil {
+			if !started {
+				error2.Respond(c, error2.NewHttpError(err))
+				return
+			}
+
+			// too late for a problem, the client is left with a truncated body
+			logger.FromContext(c.Request.Context()).WithError(err).Error("fruit export interrupted")
+			c.Abort()
+		}
+	}
+}
+
+// exportFormatOf the format asked by the format param, or else the first one the Accept header takes, in the
+// header order as clients list their preference first
+func exportFormatOf(c *gin.Context) (*exportFormat, *error2.HttpError) {
+	if name, ok := c.GetQuery("format"); ok {
+		format, ok := exportFormats[strings.ToLower(name)]
+		if !ok {
+			return nil, error2.NewBadRequestError("format must be one of csv, ndjson, json")
+		}
+
+		return format, nil
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.TrimSpace(accept) == "" {
+		return exportFormats["json"], nil
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(part, ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		if refused(params) {
+			continue
+		}
+
+		for _, offered := range exportMediaTypes {
+			kind, _, _ := strings.Cut(offered.mediaType, "/")
+			if mediaType == offered.mediaType || mediaType == kind+"/*" || mediaType == "*/*" {
+				return exportFormats[offered.format], nil
+			}
+		}
+	}
+
+	return nil, error2.NewNotAcceptableError("fruits are exported as text/csv, application/x-ndjson or application/json")
+}
+
+// refused whether the params of an accepted media type weight it q=0
+func refused(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		if strings.EqualFold(key, "q") {
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			return err == nil && q == 0
+		}
+	}
+
+	return false
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func newCSVEncoder(w io.Writer) exportEncoder {
+	return &csvEncoder{w: csv.NewWriter(w)}
+}
+
+func (e *csvEncoder) begin() error {
+	return e.w.Write([]string{"id", "name", "quantity", "price", "owner", "status", "date_created", "date_last_updated"})
+}
+
+func (e *csvEncoder) write(fruit *SearchFruitResponseResult) error {
+	return e.w.Write([]string{
+		fruit.ID,
+		csvCell(fruit.Name),
+		strconv.Itoa(fruit.Quantity),
+		strconv.FormatFloat(fruit.Price, 'f', -1, 64),
+		csvCell(fruit.Owner),
+		fruit.Status,
+		fruit.CreatedAt.Format(time.RFC3339Nano),
+		fruit.UpdatedAt.Format(time.RFC3339Nano),
+	})
+}
+
+func (e *csvEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// csvCell quote text a spreadsheet would otherwise evaluate as a formula
+func csvCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+
+	return s
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func newNDJSONEncoder(w io.Writer) exportEncoder {
+	return &ndjsonEncoder{enc: json.NewEncoder(w)}
+}
+
+func (e *ndjsonEncoder) begin() error {
+	return nil
+}
+
+func (e *ndjsonEncoder) write(fruit *SearchFruitResponseResult) error {
+	return e.enc.Encode(fruit)
+}
+
+func (e *ndjsonEncoder) end() error {
+	return nil
+}
+
+// jsonEncoder write a json array a fruit at a time, instead of marshalling it whole
+type jsonEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONEncoder(w io.Writer) exportEncoder {
+	return &jsonEncoder{w: w}
+}
+
+func (e *jsonEncoder) begin() error {
+	_, err := io.WriteString(e.w, "[")
+	return err
+}
+
+func (e *jsonEncoder) write(fruit *SearchFruitResponseResult) error {
+	b, err := json.Marshal(fruit)
+	if err != nil {
+		return err
+	}
+
+	if e.count > 0 {
+		b = append([]byte(","), b...)
+	}
+	e.count++
+
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonEncoder) end() error {
+	_, err := io.WriteString(e.w, "]\n")
+	return err
+}
@@ -0,0 +1,254 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ExportFruitsUseCaseMock struct {
+	mock.Mock
+}
+
+func (e *ExportFruitsUseCaseMock) Execute(ctx context.Context, input *usecase.ExportFruitsUseCaseInputDTO) (*usecase.ExportFruitsUseCaseOutputDTO, error) {
+	args := e.Called(ctx, input)
+
+	return args.Get(0).(*usecase.ExportFruitsUseCaseOutputDTO), args.Error(1)
+}
+
+// exporting make the mock write fruits then fail with err, if any
+func exporting(fruits []*usecase.SearchFruitUseCaseOutputResult, err error) (*ExportFruitsUseCaseMock, *usecase.ExportFruitsUseCaseInputDTO) {
+	u := &ExportFruitsUseCaseMock{}
+	var called usecase.ExportFruitsUseCaseInputDTO
+
+	u.On("Execute", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		input := args.Get(1).(*usecase.ExportFruitsUseCaseInputDTO)
+		called = *input
+
+		for _, fruit := range fruits {
+			if writeErr := input.Write(fruit); writeErr != nil {
+				panic(writeErr)
+			}
+		}
+	}).Return(&usecase.ExportFruitsUseCaseOutputDTO{Exported: len(fruits)}, err)
+
+	return u, &called
+}
+
+func exportRequest(query string, accept string) *http.Request {
+	r := httptest.NewRequest("GET", "/fruits/export?"+query, nil)
+	if accept != "" {
+		r.Header.Set("Accept", accept)
+	}
+
+	return r
+}
+
+func TestExportFruitsHandler(t *testing.T) {
+	created := time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)
+	fruits := []*usecase.SearchFruitUseCaseOutputResult{
+		{ID: "1", Name: "banana", Quantity: 10, Price: 2.5, Owner: "owner", Status: "comestible", CreatedAt: created, UpdatedAt: created},
+		{ID: "2", Name: "uva", Quantity: 3, Price: 12, Owner: "=cmd", Status: "reserved", CreatedAt: created, UpdatedAt: created.Add(time.Hour)},
+	}
+
+	t.Run("With formats", func(t *testing.T) {
+		cases := map[string]struct {
+			request     *http.Request
+			contentType string
+			filename    string
+			body        string
+		}{
+			"csv": {
+				request:     exportRequest("format=csv", ""),
+				contentType: "text/csv; charset=utf-8",
+				filename:    "fruits.csv",
+				body: "id,name,quantity,price,owner,status,date_created,date_last_updated\n" +
+					"1,banana,10,2.5,owner,comestible,2023-01-02T03:04:05Z,2023-01-02T03:04:05Z\n" +
+					"2,uva,3,12,'=cmd,reserved,2023-01-02T03:04:05Z,2023-01-02T04:04:05Z\n",
+			},
+			"ndjson": {
+				request:     exportRequest("", "application/x-ndjson"),
+				contentType: "application/x-ndjson",
+				filename:    "fruits.ndjson",
+				body: `{"id":"1","date_created":"2023-01-02T03:04:05Z","date_last_updated":"2023-01-02T03:04:05Z","name":"banana","quantity":10,"price":2.5,"owner":"owner","status":"comestible"}` + "\n" +
+					`{"id":"2","date_created":"2023-01-02T03:04:05Z","date_last_updated":"2023-01-02T04:04:05Z","name":"uva","quantity":3,"price":12,"owner":"=cmd","status":"reserved"}` + "\n",
+			},
+			"json": {
+				request:     exportRequest("", ""),
+				contentType: "application/json; charset=utf-8",
+				filename:    "fruits.json",
+				body: `[{"id":"1","date_created":"2023-01-02T03:04:05Z","date_last_updated":"2023-01-02T03:04:05Z","name":"banana","quantity":10,"price":2.5,"owner":"owner","status":"comestible"},` +
+					`{"id":"2","date_created":"2023-01-02T03:04:05Z","date_last_updated":"2023-01-02T04:04:05Z","name":"uva","quantity":3,"price":12,"owner":"=cmd","status":"reserved"}]` + "\n",
+			},
+			"format param over accept header": {
+				request:     exportRequest("format=ndjson", "text/csv"),
+				contentType: "application/x-ndjson",
+				filename:    "fruits.ndjson",
+			},
+			"first accepted type": {
+				request:     exportRequest("", "application/xml, text/csv;q=0.9, application/json;q=0.8"),
+				contentType: "text/csv; charset=utf-8",
+				filename:    "fruits.csv",
+			},
+			"refused type skipped": {
+				request:     exportRequest("", "text/csv;q=0, */*"),
+				contentType: "application/json; charset=utf-8",
+				filename:    "fruits.json",
+			},
+			"wildcard subtype": {
+				request:     exportRequest("", "text/*"),
+				contentType: "text/csv; charset=utf-8",
+				filename:    "fruits.csv",
+			},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				u, _ := exporting(fruits, nil)
+
+				rr := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(rr)
+				ctx.Request = tc.request
+
+				handler.MakeExportFruitsHandler(u)(ctx)
+
+				assert.Equal(t, rr.Code, http.StatusOK)
+				assert.Equal(t, rr.Header().Get("Content-Type"), tc.contentType)
+				assert.Equal(t, rr.Header().Get("Content-Disposition"), `attachment; filename="`+tc.filename+`"`)
+				if tc.body != "" {
+					assert.Equal(t, rr.Body.String(), tc.body)
+				}
+			})
+		}
+	})
+
+	t.Run("With nothing to export", func(t *testing.T) {
+		cases := map[string]string{
+			"csv":    "id,name,quantity,price,owner,status,date_created,date_last_updated\n",
+			"ndjson": "",
+			"json":   "[]\n",
+		}
+
+		for format, body := range cases {
+			t.Run(format, func(t *testing.T) {
+				u, _ := exporting(nil, nil)
+
+				rr := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(rr)
+				ctx.Request = exportRequest("format="+format, "")
+
+				handler.MakeExportFruitsHandler(u)(ctx)
+
+				assert.Equal(t, rr.Code, http.StatusOK)
+				assert.Equal(t, rr.Body.String(), body)
+			})
+		}
+	})
+
+	t.Run("With search filters", func(t *testing.T) {
+		u, called := exporting(nil, nil)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = exportRequest("name=ban&match=contains&status=comestible,reserved&owner=bob&min_price=1.5&max_quantity=9&created_after=2023-01-01T00:00:00Z&sort=-price&offset=3&limit=5", "")
+
+		handler.MakeExportFruitsHandler(u)(ctx)
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+
+		criteria := called.Criteria
+		assert.Equal(t, criteria.Name, "ban")
+		assert.Equal(t, criteria.Match, "contains")
+		assert.Equal(t, criteria.Statuses, []string{"comestible", "reserved"})
+		assert.Equal(t, criteria.Owner, "bob")
+		assert.Equal(t, *criteria.MinPrice, 1.5)
+		assert.Equal(t, *criteria.MaxQuantity, 9)
+		assert.Equal(t, *criteria.CreatedAfter, time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, criteria.Sort, "-price")
+		assert.Equal(t, criteria.Offset, 0)
+		assert.Equal(t, criteria.Limit, 0)
+		assert.Nil(t, criteria.Cursor)
+	})
+
+	t.Run("With invalid request", func(t *testing.T) {
+		cases := map[string]struct {
+			request *http.Request
+			err     error
+			status  int
+			detail  string
+		}{
+			"unknown format": {
+				request: exportRequest("format=xml", ""),
+				status:  http.StatusBadRequest,
+				detail:  "format must be one of csv, ndjson, json",
+			},
+			"unacceptable type": {
+				request: exportRequest("", "application/xml, application/jsonl"),
+				status:  http.StatusNotAcceptable,
+				detail:  "fruits are exported as text/csv, application/x-ndjson or application/json",
+			},
+			"invalid query": {
+				request: exportRequest("min_price=cheap", ""),
+				status:  http.StatusBadRequest,
+				detail:  "invalid request query",
+			},
+			"fuzzy match": {
+				request: exportRequest("name=ban&match=fuzzy", ""),
+				err:     domainerror.NewValidationError("exports only match names by contains"),
+				status:  http.StatusUnprocessableEntity,
+				detail:  "exports only match names by contains",
+			},
+			"refused criteria": {
+				request: exportRequest("sort=color", ""),
+				err:     domainerror.NewValidationError("sort must be one of name, price, quantity, createdAt, updatedAt"),
+				status:  http.StatusUnprocessableEntity,
+				detail:  "sort must be one of name, price, quantity, createdAt, updatedAt",
+			},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				u, _ := exporting(nil, tc.err)
+
+				rr := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(rr)
+				ctx.Request = tc.request
+
+				handler.MakeExportFruitsHandler(u)(ctx)
+
+				var response error2.HttpError
+				assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+				assert.Equal(t, rr.Code, tc.status)
+				assert.Equal(t, response.Detail, tc.detail)
+				assert.Empty(t, rr.Header().Get("Content-Disposition"))
+			})
+		}
+	})
+
+	t.Run("With failure after the first fruit", func(t *testing.T) {
+		u, _ := exporting(fruits[:1], errors.New("connection lost"))
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = exportRequest("format=json", "")
+
+		handler.MakeExportFruitsHandler(u)(ctx)
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.True(t, ctx.IsAborted())
+		assert.True(t, strings.HasPrefix(rr.Body.String(), `[{"id":"1"`))
+		assert.False(t, strings.HasSuffix(rr.Body.String(), "]\n"))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"net/http"
 	"time"
 )
@@ -26,18 +27,22 @@ type GetFruitResponseDTO struct {
 // @Accept       json
 // @Produce      json
 // @Param		 id path string true "Fruit id"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
 // @Success		 200 {object} GetFruitResponseDTO
+// @Header		 200 {string} ETag "Fruit version"
 // @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
 // @Failure		 500 {object} error.HttpError
 // @Router       /fruits/{id} [get]
 func MakeGetFruitHandler(u protocol.UseCase[*usecase.GetFruitUseCaseInputDTO, *usecase.GetFruitUseCaseOutputDTO]) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "invalid request param",
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
 			return
 		}
 
@@ -47,10 +52,7 @@ func MakeGetFruitHandler(u protocol.UseCase[*usecase.GetFruitUseCaseInputDTO, *u
 		output, err := u.Execute(c.Request.Context(), input)
 
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": err.Error(),
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
@@ -64,6 +66,7 @@ func MakeGetFruitHandler(u protocol.UseCase[*usecase.GetFruitUseCaseInputDTO, *u
 			Price:     output.Price,
 			Quantity:  output.Quantity,
 		}
+		c.Header("ETag", etag(output.Version))
 		c.JSON(http.StatusOK, response)
 	}
 }
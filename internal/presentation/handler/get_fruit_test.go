@@ -3,9 +3,9 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
@@ -49,12 +49,12 @@ func TestGetFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "invalid request param")
+		assert.Equal(t, response.Detail, "invalid request param")
 	})
 
 	t.Run("With usecase fail", func(t *testing.T) {
 		u := &GetFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.GetFruitUseCaseOutputDTO{}, errors.New("item not found"))
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.GetFruitUseCaseOutputDTO{}, domainerror.NewNotFoundError("fruit not found"))
 
 		h := handler.MakeGetFruitHandler(u)
 
@@ -78,8 +78,8 @@ func TestGetFruitHandler(t *testing.T) {
 		}
 
 		assert.Nil(t, err)
-		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "item not found")
+		assert.Equal(t, rr.Code, http.StatusNotFound)
+		assert.Equal(t, response.Detail, "fruit not found")
 
 	})
 
@@ -104,7 +104,8 @@ func TestGetFruitHandler(t *testing.T) {
 			Quantity:  fruitMock.Quantity,
 			CreatedAt: fruitMock.CreatedAt,
 			UpdatedAt: fruitMock.UpdatedAt,
-			Status:    fruitMock.Status,
+			Status:    string(fruitMock.Status),
+			Version:   3,
 		}, nil)
 
 		h := handler.MakeGetFruitHandler(u)
@@ -130,6 +131,7 @@ func TestGetFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Header().Get("ETag"), `"3"`)
 		assert.Equal(t, response.ID, fruitMock.ID)
 		assert.Equal(t, response.Name, fruitMock.Name)
 		assert.Equal(t, response.Owner, fruitMock.Owner)
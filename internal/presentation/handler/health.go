@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/health"
+	"net/http"
+)
+
+type LivenessResponseDTO struct {
+	Status health.Status `json:"status"`
+}
+
+// MakeLivenessHandler generate handler function to http liveness probe, answering as long as the process serves requests
+// @Summary      Liveness probe
+// @Description  Tell the process is alive, without checking its dependencies
+// @Tags         health
+// @Produce      json
+// @Success		 200 {object} LivenessResponseDTO
+// @Router       /healthz [get]
+func MakeLivenessHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, &LivenessResponseDTO{Status: health.StatusUp})
+	}
+}
+
+// MakeReadinessHandler generate handler function to http readiness probe, checking every registered dependency
+// @Summary      Readiness probe
+// @Description  Check every dependency the api needs, failing while the server is draining on shutdown
+// @Tags         health
+// @Produce      json
+// @Success		 200 {object} health.Report
+// @Failure		 503 {object} health.Report
+// @Router       /readyz [get]
+func MakeReadinessHandler(checker *health.Checker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := checker.Ready(c.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != health.StatusUp {
+			status = http.StatusServiceUnavailable
+		}
+
+		c.JSON(status, report)
+	}
+}
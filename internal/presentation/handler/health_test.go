@@ -0,0 +1,84 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/health"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLivenessHandler(t *testing.T) {
+	h := handler.MakeLivenessHandler()
+
+	rr := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rr)
+	ctx.Request = httptest.NewRequest("GET", "/healthz", nil)
+
+	var response handler.LivenessResponseDTO
+	h(ctx)
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Nil(t, err)
+	assert.Equal(t, rr.Code, http.StatusOK)
+	assert.Equal(t, response.Status, health.StatusUp)
+}
+
+func TestReadinessHandler(t *testing.T) {
+	probe := func(checker *health.Checker) (*httptest.ResponseRecorder, *health.Report) {
+		h := handler.MakeReadinessHandler(checker)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = httptest.NewRequest("GET", "/readyz", nil)
+
+		var response health.Report
+		h(ctx)
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+		assert.Nil(t, err)
+
+		return rr, &response
+	}
+
+	t.Run("With every dependency up", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Register("repository", func(context.Context) error {
+			return nil
+		})
+
+		rr, response := probe(checker)
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.Status, health.StatusUp)
+		assert.Equal(t, response.Components["repository"].Status, health.StatusUp)
+	})
+
+	t.Run("With dependency down", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Register("repository", func(context.Context) error {
+			return errors.New("database is locked")
+		})
+
+		rr, response := probe(checker)
+
+		assert.Equal(t, rr.Code, http.StatusServiceUnavailable)
+		assert.Equal(t, response.Status, health.StatusDown)
+		assert.Equal(t, response.Components["repository"].Error, "database is locked")
+	})
+
+	t.Run("While draining", func(t *testing.T) {
+		checker := health.NewChecker(time.Second)
+		checker.Drain()
+
+		rr, response := probe(checker)
+
+		assert.Equal(t, rr.Code, http.StatusServiceUnavailable)
+		assert.True(t, response.Draining)
+	})
+}
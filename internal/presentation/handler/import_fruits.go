@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/infra/spreadsheet"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+)
+
+type ImportFruitsRequestDTO struct {
+	Mode    string `form:"mode"`
+	DryRun  bool   `form:"dry_run"`
+	Columns string `form:"columns"`
+}
+
+type ImportFruitsRowDTO struct {
+	Line   int    `json:"line"`
+	Action string `json:"action"`
+	ID     string `json:"id,omitempty"`
+	// Error why the row was refused, when it was
+	Error *error2.HttpError `json:"error,omitempty" swaggertype:"object"`
+}
+
+type ImportFruitsResponseDTO struct {
+	DryRun  bool                  `json:"dry_run"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Rows    []*ImportFruitsRowDTO `json:"rows"`
+}
+
+// MakeImportFruitsHandler generate handler function to http import fruits request
+// @Summary      Import fruits from a spreadsheet
+// @Description  Create the fruits of a csv or xlsx file, or upsert them by name, reporting what was done with each row. The first row holds the headers, recognized by default as name, quantity and price. Imported fruits belong to the caller.
+// @Tags         fruits
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param		 file formData file true "csv or xlsx file, at most 10 MiB"
+// @Param		 mode formData string false "create a fruit per row, or upsert the caller fruits with the same name" Enums(create, upsert) default(create)
+// @Param		 dry_run formData bool false "validate the rows and tell what would be done, without changing any fruit"
+// @Param		 columns formData string false "headers of the fruit fields, e.g. Fruta=name,Estoque=quantity,Valor=price"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {object} ImportFruitsResponseDTO
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 413 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/import [post]
+func MakeImportFruitsHandler(u protocol.UseCase[*usecase.ImportFruitsUseCaseInputDTO, *usecase.ImportFruitsUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := principalOf(c)
+		if !ok {
+			return
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, spreadsheet.MaxFileSize)
+
+		header, err := c.FormFile("file")
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			error2.Respond(c, error2.NewPayloadTooLargeError(fmt.Sprintf("file must be at most %d MiB", spreadsheet.MaxFileSize>>20)))
+			return
+		case err != nil:
+			error2.Respond(c, error2.NewBadRequestError("file is required"))
+			return
+		}
+
+		body := &ImportFruitsRequestDTO{Mode: string(usecase.ImportCreate)}
+		if err := c.ShouldBind(body); err != nil {
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+			return
+		}
+
+		format, err := spreadsheet.FormatOf(header.Filename)
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		var columns map[string]string
+		if body.Columns != "" {
+			if columns, err = spreadsheet.ParseColumns(body.Columns); err != nil {
+				error2.Respond(c, error2.NewHttpError(err))
+				return
+			}
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			error2.Respond(c, error2.NewBadRequestError("file is required"))
+			return
+		}
+		defer file.Close()
+
+		rows, err := spreadsheet.ReadFruitRows(file, format, columns, usecase.MaxImportRows)
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		output, err := u.Execute(c.Request.Context(), &usecase.ImportFruitsUseCaseInputDTO{
+			Rows:   importRowsOf(rows),
+			Owner:  principal.Subject,
+			Mode:   usecase.ImportMode(body.Mode),
+			DryRun: body.DryRun,
+		})
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		response := &ImportFruitsResponseDTO{
+			DryRun:  output.DryRun,
+			Created: output.Created,
+			Updated: output.Updated,
+			Failed:  output.Failed,
+			Rows:    make([]*ImportFruitsRowDTO, len(output.Rows)),
+		}
+
+		for i, row := range output.Rows {
+			response.Rows[i] = &ImportFruitsRowDTO{Line: row.Line, Action: string(row.Action), ID: row.ID}
+			if row.Err != nil {
+				response.Rows[i].Error = error2.NewHttpError(row.Err)
+			}
+		}
+
+		c.JSON(http.StatusOK, response)
+	}
+}
+
+// importRowsOf map the rows read from an import file to the rows of the import
+func importRowsOf(rows []*spreadsheet.FruitRow) []*usecase.ImportFruitRow {
+	mapped := make([]*usecase.ImportFruitRow, len(rows))
+	for i, row := range rows {
+		mapped[i] = &usecase.ImportFruitRow{Line: row.Line, Name: row.Name, Quantity: row.Quantity, Price: row.Price}
+	}
+
+	return mapped
+}
@@ -0,0 +1,156 @@
+package handler_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type ImportFruitsUseCaseMock struct {
+	mock.Mock
+}
+
+func (i *ImportFruitsUseCaseMock) Execute(ctx context.Context, input *usecase.ImportFruitsUseCaseInputDTO) (*usecase.ImportFruitsUseCaseOutputDTO, error) {
+	args := i.Called(ctx, input)
+
+	return args.Get(0).(*usecase.ImportFruitsUseCaseOutputDTO), args.Error(1)
+}
+
+// importRequest build a multipart upload of content named filename, along with the form fields
+func importRequest(t *testing.T, filename string, content string, fields map[string]string) *http.Request {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		assert.Nil(t, err)
+		_, err = part.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+
+	for name, value := range fields {
+		assert.Nil(t, writer.WriteField(name, value))
+	}
+	assert.Nil(t, writer.Close())
+
+	r := httptest.NewRequest("POST", "/fruits/import", &body)
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return withPrincipal(r, "owner")
+}
+
+func TestImportFruitsHandler(t *testing.T) {
+	t.Run("With invalid upload", func(t *testing.T) {
+		cases := map[string]struct {
+			request *http.Request
+			status  int
+			detail  string
+		}{
+			"missing file": {
+				request: importRequest(t, "", "", nil),
+				status:  http.StatusBadRequest,
+				detail:  "file is required",
+			},
+			"unknown format": {
+				request: importRequest(t, "fruits.pdf", "", nil),
+				status:  http.StatusUnprocessableEntity,
+				detail:  "file must be a csv or a xlsx file",
+			},
+			"invalid columns": {
+				request: importRequest(t, "fruits.csv", "", map[string]string{"columns": "Fruta"}),
+				status:  http.StatusUnprocessableEntity,
+				detail:  `"Fruta" must map a header to one of name, quantity, price`,
+			},
+			"missing headers": {
+				request: importRequest(t, "fruits.csv", "name,price\n", nil),
+				status:  http.StatusUnprocessableEntity,
+				detail:  "no column is mapped to quantity",
+			},
+			"too large file": {
+				request: importRequest(t, "fruits.csv", strings.Repeat("a", 11<<20), nil),
+				status:  http.StatusRequestEntityTooLarge,
+				detail:  "file must be at most 10 MiB",
+			},
+		}
+
+		for name, tc := range cases {
+			t.Run(name, func(t *testing.T) {
+				u := &ImportFruitsUseCaseMock{}
+				h := handler.MakeImportFruitsHandler(u)
+
+				rr := httptest.NewRecorder()
+				ctx, _ := gin.CreateTestContext(rr)
+				ctx.Request = tc.request
+
+				var response error2.HttpError
+				h(ctx)
+				err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+				assert.Nil(t, err)
+				assert.Equal(t, rr.Code, tc.status)
+				assert.Equal(t, response.Detail, tc.detail)
+				u.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Success", func(t *testing.T) {
+		var violations domainerror.Violations
+		violations.Add("quantity", "quantity must be a whole number")
+
+		u := &ImportFruitsUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.ImportFruitsUseCaseInputDTO{
+			Rows: []*usecase.ImportFruitRow{
+				{Line: 2, Name: "banana", Quantity: "10", Price: "2.5"},
+				{Line: 3, Name: "uva", Quantity: "many", Price: "1"},
+			},
+			Owner:  "owner",
+			Mode:   usecase.ImportUpsert,
+			DryRun: true,
+		}).Return(&usecase.ImportFruitsUseCaseOutputDTO{
+			Rows: []*usecase.ImportFruitsUseCaseOutputRow{
+				{Line: 2, Action: usecase.ImportUpdated, ID: "some-uuid"},
+				{Line: 3, Action: usecase.ImportFailed, Err: violations.Err()},
+			},
+			Updated: 1,
+			Failed:  1,
+			DryRun:  true,
+		}, nil)
+		h := handler.MakeImportFruitsHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = importRequest(t, "fruits.csv", "Fruta,Estoque,Valor\nbanana,10,2.5\nuva,many,1\n", map[string]string{
+			"mode":    "upsert",
+			"dry_run": "true",
+			"columns": "Fruta=name,Estoque=quantity,Valor=price",
+		})
+
+		var response handler.ImportFruitsResponseDTO
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.True(t, response.DryRun)
+		assert.Equal(t, response.Updated, 1)
+		assert.Equal(t, response.Failed, 1)
+		assert.Equal(t, response.Rows[0].Action, "updated")
+		assert.Equal(t, response.Rows[0].ID, "some-uuid")
+		assert.Nil(t, response.Rows[0].Error)
+		assert.Equal(t, response.Rows[1].Line, 3)
+		assert.Equal(t, response.Rows[1].Error.Status, http.StatusUnprocessableEntity)
+		assert.Equal(t, response.Rows[1].Error.Errors[0].Field, "quantity")
+	})
+}
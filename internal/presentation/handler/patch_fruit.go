@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// acceptPatch the patch media types, advertised in the Accept-Patch header
+var acceptPatch = strings.Join([]string{string(usecase.MergePatch), string(usecase.JSONPatch)}, ", ")
+
+type PatchFruitResponseDTO struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"date_created"`
+	UpdatedAt time.Time `json:"date_last_updated"`
+	Name      string    `json:"name"`
+	Quantity  int       `json:"quantity"`
+	Price     float64   `json:"price"`
+	Owner     string    `json:"owner"`
+	Status    string    `json:"status"`
+}
+
+// MakePatchFruitHandler generate handler function to http patch fruit request
+// @Summary      Patch fruit
+// @Description  Change some fields of a fruit with a JSON Merge Patch or a JSON Patch. Name, quantity and price can be changed, and the owner by admins; id, dates and status cannot.
+// @Tags         fruits
+// @Accept       application/merge-patch+json,application/json-patch+json
+// @Produce      json
+// @Param		 id path string true "Fruit id"
+// @Param		 body body object true "Merge patch, e.g. {\"price\": 12.5}, or JSON patch, e.g. [{\"op\": \"replace\", \"path\": \"/price\", \"value\": 12.5}]"
+// @Param		 If-Match header string false "Only patch when the fruit still has this ETag"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {object} PatchFruitResponseDTO
+// @Header		 200 {string} ETag "Fruit version"
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
+// @Failure		 412 {object} error.HttpError
+// @Failure		 415 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/{id} [patch]
+func MakePatchFruitHandler(u protocol.UseCase[*usecase.PatchFruitUseCaseInputDTO, *usecase.PatchFruitUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.Param("id")
+		if id == "" {
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
+			return
+		}
+
+		format := usecase.PatchFormat(c.ContentType())
+		if format != usecase.MergePatch && format != usecase.JSONPatch {
+			c.Header("Accept-Patch", acceptPatch)
+			error2.Respond(c, error2.NewUnsupportedMediaTypeError("patch must be sent as "+acceptPatch))
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil || !json.Valid(body) {
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+			return
+		}
+
+		expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		output, err := u.Execute(c.Request.Context(), &usecase.PatchFruitUseCaseInputDTO{
+			ID:              id,
+			Format:          format,
+			Patch:           body,
+			ExpectedVersion: expectedVersion,
+		})
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		response := &PatchFruitResponseDTO{
+			ID:        output.ID,
+			CreatedAt: output.CreatedAt,
+			UpdatedAt: output.UpdatedAt,
+			Name:      output.Name,
+			Status:    output.Status,
+			Owner:     output.Owner,
+			Price:     output.Price,
+			Quantity:  output.Quantity,
+		}
+		c.Header("ETag", etag(output.Version))
+		c.JSON(http.StatusOK, response)
+	}
+}
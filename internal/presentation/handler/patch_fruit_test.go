@@ -0,0 +1,104 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type PatchFruitUseCaseMock struct {
+	mock.Mock
+}
+
+func (c *PatchFruitUseCaseMock) Execute(ctx context.Context, i *usecase.PatchFruitUseCaseInputDTO) (*usecase.PatchFruitUseCaseOutputDTO, error) {
+	args := c.Called(ctx, i)
+
+	return args.Get(0).(*usecase.PatchFruitUseCaseOutputDTO), args.Error(1)
+}
+
+func TestPatchFruitHandler(t *testing.T) {
+	serve := func(u *PatchFruitUseCaseMock, contentType string, body string, header http.Header) *httptest.ResponseRecorder {
+		h := handler.MakePatchFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("PATCH", "/fruits/some-uuid", strings.NewReader(body))
+		r.Header = header
+		r.Header.Set("Content-Type", contentType)
+		ctx.Request = r
+
+		h(ctx)
+		return rr
+	}
+
+	t.Run("With unsupported content type", func(t *testing.T) {
+		u := &PatchFruitUseCaseMock{}
+		rr := serve(u, "application/json", `{"price": 1}`, http.Header{})
+
+		var response error2.HttpError
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusUnsupportedMediaType)
+		assert.Equal(t, rr.Header().Get("Accept-Patch"), "application/merge-patch+json, application/json-patch+json")
+		u.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+	})
+
+	t.Run("With malformed body", func(t *testing.T) {
+		u := &PatchFruitUseCaseMock{}
+		rr := serve(u, "application/merge-patch+json", `{"price": `, http.Header{})
+
+		var response error2.HttpError
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request body")
+	})
+
+	t.Run("When usecase fails", func(t *testing.T) {
+		u := &PatchFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.PatchFruitUseCaseOutputDTO{}, domainerror.NewConflictError("patch test operation failed"))
+		rr := serve(u, "application/json-patch+json", `[{"op": "test", "path": "/price", "value": 2}]`, http.Header{})
+
+		assert.Equal(t, rr.Code, http.StatusConflict)
+	})
+
+	t.Run("When usecase success", func(t *testing.T) {
+		version := 1
+		body := `{"price": 12.5}`
+
+		u := &PatchFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.PatchFruitUseCaseInputDTO{ID: "some-uuid", Format: usecase.MergePatch, Patch: []byte(body), ExpectedVersion: &version}).Return(&usecase.PatchFruitUseCaseOutputDTO{
+			ID:       "some-uuid",
+			Name:     "uva",
+			Quantity: 1,
+			Price:    12.5,
+			Version:  2,
+		}, nil)
+		rr := serve(u, "application/merge-patch+json; charset=utf-8", body, http.Header{"If-Match": []string{`"1"`}})
+
+		var response handler.PatchFruitResponseDTO
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Header().Get("ETag"), `"2"`)
+		assert.Equal(t, response.Price, 12.5)
+		u.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,18 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+)
+
+// principalOf return the identity verified by the authentication middleware, responding 401 when there is none
+func principalOf(c *gin.Context) (*auth.Principal, bool) {
+	principal, ok := auth.PrincipalFrom(c.Request.Context())
+	if !ok {
+		error2.Respond(c, error2.NewHttpError(domainerror.NewUnauthenticatedError("missing credentials")))
+	}
+
+	return principal, ok
+}
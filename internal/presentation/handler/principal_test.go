@@ -0,0 +1,47 @@
+package handler_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// withPrincipal authenticate r as subject, as the authentication middleware would
+func withPrincipal(r *http.Request, subject string) *http.Request {
+	return r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Subject: subject}))
+}
+
+func TestHandler_WithoutPrincipal(t *testing.T) {
+	handlers := map[string]gin.HandlerFunc{
+		"create":  handler.MakeCreateFruitHandler(&CreateFruitUseCaseMock{}),
+		"restore": handler.MakeRestoreFruitHandler(&RestoreFruitUseCaseMock{}),
+		"bulk":    handler.MakeBulkFruitHandler(&BulkFruitUseCaseMock{}),
+		"import":  handler.MakeImportFruitsHandler(&ImportFruitsUseCaseMock{}),
+	}
+
+	for name, h := range handlers {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rr)
+			ctx.Params = []gin.Param{
+				{Key: "id", Value: "some-uuid"},
+			}
+			ctx.Request = httptest.NewRequest("POST", "/fruits", strings.NewReader(`{"name": "uva", "quantity": 1, "price": 10.10}`))
+
+			var response error2.HttpError
+			h(ctx)
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+			assert.Nil(t, err)
+			assert.Equal(t, rr.Code, http.StatusUnauthorized)
+			assert.Equal(t, response.Type, error2.UnauthenticatedProblem)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+)
+
+// MakePurgeFruitHandler generate handler function to http purge fruit request
+// @Summary      Purge a fruit
+// @Description  Permanently remove a deleted (podrido or discarded) fruit, it can't be restored afterwards
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Param		 id path string true "Fruit id"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 204
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /admin/fruits/{id} [delete]
+func MakePurgeFruitHandler(u protocol.UseCase[*usecase.PurgeFruitUseCaseInputDTO, *usecase.PurgeFruitUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id := c.Param("id")
+		if id == "" {
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
+			return
+		}
+
+		input := &usecase.PurgeFruitUseCaseInputDTO{
+			ID: id,
+		}
+
+		_, err := u.Execute(c.Request.Context(), input)
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		c.Status(http.StatusNoContent)
+	}
+}
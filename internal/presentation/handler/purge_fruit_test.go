@@ -0,0 +1,93 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type PurgeFruitUseCaseMock struct {
+	mock.Mock
+}
+
+func (c *PurgeFruitUseCaseMock) Execute(ctx context.Context, i *usecase.PurgeFruitUseCaseInputDTO) (*usecase.PurgeFruitUseCaseOutputDTO, error) {
+	args := c.Called(ctx, i)
+	return args.Get(0).(*usecase.PurgeFruitUseCaseOutputDTO), args.Error(1)
+}
+
+func TestPurgeFruitHandler(t *testing.T) {
+	t.Run("With invalid param", func(t *testing.T) {
+		u := &PurgeFruitUseCaseMock{}
+		h := handler.MakePurgeFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: ""},
+		}
+
+		r := httptest.NewRequest("DELETE", "/admin/fruits/{id}", nil)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request param")
+	})
+
+	t.Run("With not deleted fruit", func(t *testing.T) {
+		u := &PurgeFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.PurgeFruitUseCaseOutputDTO{}, domainerror.NewConflictError("fruit with status comestible must be deleted before being purged"))
+		h := handler.MakePurgeFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("DELETE", "/admin/fruits/{id}", nil)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusConflict)
+		assert.Equal(t, response.Detail, "fruit with status comestible must be deleted before being purged")
+	})
+
+	t.Run("When usecase success", func(t *testing.T) {
+		u := &PurgeFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.PurgeFruitUseCaseInputDTO{ID: "some-uuid"}).Return(&usecase.PurgeFruitUseCaseOutputDTO{ID: "some-uuid"}, nil)
+		h := handler.MakePurgeFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("DELETE", "/admin/fruits/{id}", nil)
+		ctx.Request = r
+
+		h(ctx)
+		ctx.Writer.WriteHeaderNow()
+
+		assert.Equal(t, rr.Code, http.StatusNoContent)
+		assert.Empty(t, rr.Body.String())
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+	"time"
+)
+
+type RestoreFruitResponseDTO struct {
+	ID         string    `json:"id"`
+	CreatedAt  time.Time `json:"date_created"`
+	UpdatedAt  time.Time `json:"date_last_updated"`
+	Name       string    `json:"name"`
+	Quantity   int       `json:"quantity"`
+	Price      float64   `json:"price"`
+	Owner      string    `json:"owner"`
+	Status     string    `json:"status"`
+	RestoredBy string    `json:"restored_by"`
+	RestoredAt time.Time `json:"date_restored"`
+}
+
+// MakeRestoreFruitHandler generate handler function to http restore fruit request
+// @Summary      Restore a deleted fruit
+// @Description  Move a podrido fruit back to the status it had before being deleted
+// @Tags         fruits
+// @Accept       json
+// @Produce      json
+// @Param		 id path string true "Fruit id"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {object} RestoreFruitResponseDTO
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/{id}/restore [post]
+func MakeRestoreFruitHandler(u protocol.UseCase[*usecase.RestoreFruitUseCaseInputDTO, *usecase.RestoreFruitUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := principalOf(c)
+		if !ok {
+			return
+		}
+
+		id := c.Param("id")
+		if id == "" {
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
+			return
+		}
+
+		input := &usecase.RestoreFruitUseCaseInputDTO{
+			ID:         id,
+			RestoredBy: principal.Subject,
+		}
+
+		output, err := u.Execute(c.Request.Context(), input)
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		response := &RestoreFruitResponseDTO{
+			ID:         output.ID,
+			CreatedAt:  output.CreatedAt,
+			UpdatedAt:  output.UpdatedAt,
+			Name:       output.Name,
+			Status:     output.Status,
+			Owner:      output.Owner,
+			Price:      output.Price,
+			Quantity:   output.Quantity,
+			RestoredBy: output.RestoredBy,
+			RestoredAt: output.RestoredAt,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,112 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type RestoreFruitUseCaseMock struct {
+	mock.Mock
+}
+
+func (c *RestoreFruitUseCaseMock) Execute(ctx context.Context, i *usecase.RestoreFruitUseCaseInputDTO) (*usecase.RestoreFruitUseCaseOutputDTO, error) {
+	args := c.Called(ctx, i)
+	return args.Get(0).(*usecase.RestoreFruitUseCaseOutputDTO), args.Error(1)
+}
+
+func TestRestoreFruitHandler(t *testing.T) {
+	t.Run("With invalid param", func(t *testing.T) {
+		u := &RestoreFruitUseCaseMock{}
+		h := handler.MakeRestoreFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: ""},
+		}
+
+		r := httptest.NewRequest("POST", "/fruits/{id}/restore", nil)
+		ctx.Request = withPrincipal(r, "clerk")
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request param")
+	})
+
+	t.Run("With not deleted fruit", func(t *testing.T) {
+		u := &RestoreFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.RestoreFruitUseCaseOutputDTO{}, domainerror.NewConflictError("fruit with status comestible is not deleted"))
+		h := handler.MakeRestoreFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("POST", "/fruits/{id}/restore", nil)
+		ctx.Request = withPrincipal(r, "clerk")
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusConflict)
+		assert.Equal(t, response.Detail, "fruit with status comestible is not deleted")
+	})
+
+	t.Run("When usecase success", func(t *testing.T) {
+		restoredAt := time.Now()
+
+		u := &RestoreFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.RestoreFruitUseCaseInputDTO{ID: "some-uuid", RestoredBy: "clerk"}).Return(&usecase.RestoreFruitUseCaseOutputDTO{
+			ID:         "some-uuid",
+			CreatedAt:  restoredAt.Add(-time.Hour),
+			UpdatedAt:  restoredAt,
+			Name:       "uva",
+			Quantity:   1,
+			Price:      10.0,
+			Status:     "comestible",
+			Owner:      "owner",
+			RestoredBy: "clerk",
+			RestoredAt: restoredAt,
+		}, nil)
+		h := handler.MakeRestoreFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("POST", "/fruits/{id}/restore", nil)
+		ctx.Request = withPrincipal(r, "clerk")
+
+		var response handler.RestoreFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.ID, "some-uuid")
+		assert.Equal(t, response.Status, "comestible")
+		assert.Equal(t, response.RestoredBy, "clerk")
+		assert.True(t, response.RestoredAt.Equal(restoredAt))
+	})
+}
@@ -4,11 +4,26 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// SearchFruitRequestFilters the optional search filters, instants are RFC 3339 timestamps
+type SearchFruitRequestFilters struct {
+	Owner         string     `form:"owner"`
+	MinPrice      *float64   `form:"min_price"`
+	MaxPrice      *float64   `form:"max_price"`
+	MinQuantity   *int       `form:"min_quantity"`
+	MaxQuantity   *int       `form:"max_quantity"`
+	CreatedAfter  *time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore *time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedAfter  *time.Time `form:"updated_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	UpdatedBefore *time.Time `form:"updated_before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
 type SearchFruitResponsePaging struct {
 	Total  int `json:"total"`
 	Offset int `json:"offset"`
@@ -24,32 +39,61 @@ type SearchFruitResponseResult struct {
 	Price     float64   `json:"price"`
 	Owner     string    `json:"owner"`
 	Status    string    `json:"status"`
+	// Score the relevance to a fuzzy name, from 0 to 1
+	Score float64 `json:"score,omitempty"`
+}
+
+// SearchFruitResponseCursors the cursors to pass back to fetch the pages around the results
+type SearchFruitResponseCursors struct {
+	Next string `json:"next,omitempty"`
+	Prev string `json:"prev,omitempty"`
 }
 
 type SearchFruitResponseDTO struct {
 	Paging  *SearchFruitResponsePaging
 	Results []*SearchFruitResponseResult
+	// Cursors only set when paging with cursors
+	Cursors *SearchFruitResponseCursors `json:"Cursors,omitempty"`
 }
 
 // MakeSearchFruitHandler generate handler function to http search fruit request
 // @Summary      Search fruits
-// @Description  Search fruits by name and status
+// @Description  Search fruits matching every given filter
 // @Tags         fruits
 // @Accept       json
 // @Produce      json
-// @Param		 name query string true "Fruit name"
-// @Param		 status query string true "Fruit status"
+// @Param		 name query string false "Part of the fruit name"
+// @Param		 match query string false "How the name is matched: contains, or fuzzy to ignore accents, tolerate typos and rank by relevance" Enums(contains, fuzzy)
+// @Param		 status query []string false "Fruit statuses, repeated or comma separated" collectionFormat(multi)
+// @Param		 owner query string false "Fruit owner"
+// @Param		 min_price query number false "Minimum price, inclusive"
+// @Param		 max_price query number false "Maximum price, inclusive"
+// @Param		 min_quantity query int false "Minimum quantity, inclusive"
+// @Param		 max_quantity query int false "Maximum quantity, inclusive"
+// @Param		 created_after query string false "Created after this RFC 3339 instant"
+// @Param		 created_before query string false "Created before this RFC 3339 instant"
+// @Param		 updated_after query string false "Last updated after this RFC 3339 instant"
+// @Param		 updated_before query string false "Last updated before this RFC 3339 instant"
+// @Param		 sort query string false "Comma separated fields to order by, descending when prefixed by -: name, price, quantity, createdAt, updatedAt" example(-price,name)
+// @Param		 cursor query string false "Page with cursors instead of offset: empty for the first page, then a cursor of the previous response. The total is then 0"
 // @Param		 offset query int false "Pagination offset" 1
 // @Param		 limit query int false "Pagination limit" 100
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
 // @Success		 200 {object} SearchFruitResponseResult
 // @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
 // @Failure		 500 {object} error.HttpError
 // @Router       /fruits/search [get]
 func MakeSearchFruitHandler(u protocol.UseCase[*usecase.SearchFruitUseCaseInputDTO, *usecase.SearchFruitUseCaseOutputDTO]) gin.HandlerFunc {
 	return func(c *gin.Context) {
-
-		name := c.Query("name")
-		status := c.Query("status")
+		input, ok := searchCriteriaOf(c)
+		if !ok {
+			return
+		}
 
 		var offset int64
 		var limit int64
@@ -63,20 +107,17 @@ func MakeSearchFruitHandler(u protocol.UseCase[*usecase.SearchFruitUseCaseInputD
 			limit = 0
 		}
 
-		input := &usecase.SearchFruitUseCaseInputDTO{
-			Name:   name,
-			Status: status,
-			Offset: int(offset),
-			Limit:  int(limit),
+		input.Offset = int(offset)
+		input.Limit = int(limit)
+
+		if cursor, ok := c.GetQuery("cursor"); ok {
+			input.Cursor = &cursor
 		}
 
 		output, err := u.Execute(c.Request.Context(), input)
 
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": err.Error(),
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
@@ -91,6 +132,7 @@ func MakeSearchFruitHandler(u protocol.UseCase[*usecase.SearchFruitUseCaseInputD
 				Quantity:  r.Quantity,
 				Price:     r.Price,
 				Status:    r.Status,
+				Score:     r.Score,
 			})
 		}
 
@@ -103,6 +145,52 @@ func MakeSearchFruitHandler(u protocol.UseCase[*usecase.SearchFruitUseCaseInputD
 			Results: mappedResults,
 		}
 
+		if output.Cursors != nil {
+			response.Cursors = &SearchFruitResponseCursors{
+				Next: output.Cursors.Next,
+				Prev: output.Cursors.Prev,
+			}
+		}
+
 		c.JSON(http.StatusOK, response)
 	}
 }
+
+// searchCriteriaOf read the search filters and sort of the query, responding 400 when they can't be parsed
+func searchCriteriaOf(c *gin.Context) (*usecase.SearchFruitUseCaseInputDTO, bool) {
+	var filters SearchFruitRequestFilters
+	if err := c.ShouldBindQuery(&filters); err != nil {
+		error2.Respond(c, error2.NewBadRequestError("invalid request query"))
+		return nil, false
+	}
+
+	return &usecase.SearchFruitUseCaseInputDTO{
+		Name:          c.Query("name"),
+		Match:         c.Query("match"),
+		Statuses:      queryList(c, "status"),
+		Owner:         filters.Owner,
+		MinPrice:      filters.MinPrice,
+		MaxPrice:      filters.MaxPrice,
+		MinQuantity:   filters.MinQuantity,
+		MaxQuantity:   filters.MaxQuantity,
+		CreatedAfter:  filters.CreatedAfter,
+		CreatedBefore: filters.CreatedBefore,
+		UpdatedAfter:  filters.UpdatedAfter,
+		UpdatedBefore: filters.UpdatedBefore,
+		Sort:          c.Query("sort"),
+	}, true
+}
+
+// queryList collect the values of a query param given repeated, comma separated or both
+func queryList(c *gin.Context, key string) []string {
+	var values []string
+	for _, param := range c.QueryArray(key) {
+		for _, value := range strings.Split(param, ",") {
+			if value = strings.TrimSpace(value); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+
+	return values
+}
@@ -3,9 +3,9 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
@@ -29,7 +29,7 @@ func (c *SearchFruitUseCaseMock) Execute(ctx context.Context, i *usecase.SearchF
 func TestSearchFruitHandler(t *testing.T) {
 	t.Run("With usecase fail", func(t *testing.T) {
 		u := &SearchFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.SearchFruitUseCaseOutputDTO{}, errors.New("name is required"))
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.SearchFruitUseCaseOutputDTO{}, domainerror.NewValidationError("name is required"))
 
 		h := handler.MakeSearchFruitHandler(u)
 
@@ -54,8 +54,8 @@ func TestSearchFruitHandler(t *testing.T) {
 		}
 
 		assert.Nil(t, err)
-		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "name is required")
+		assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+		assert.Equal(t, response.Detail, "name is required")
 	})
 
 	t.Run("With usecase success", func(t *testing.T) {
@@ -117,4 +117,102 @@ func TestSearchFruitHandler(t *testing.T) {
 		assert.Equal(t, response.Results[0].ID, fruitResults[0].ID)
 		assert.Equal(t, response.Results[1].ID, fruitResults[1].ID)
 	})
+
+	t.Run("With every filter", func(t *testing.T) {
+		createdAfter := time.Date(2022, time.December, 1, 0, 0, 0, 0, time.UTC)
+		minPrice := 2.5
+		maxQuantity := 10
+
+		u := &SearchFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.SearchFruitUseCaseInputDTO{
+			Name:         "uva",
+			Statuses:     []string{"comestible", "reserved", "sold"},
+			Owner:        "ruan",
+			MinPrice:     &minPrice,
+			MaxQuantity:  &maxQuantity,
+			CreatedAfter: &createdAfter,
+			Sort:         "-price,name",
+			Offset:       1,
+			Limit:        10,
+		}).Return(&usecase.SearchFruitUseCaseOutputDTO{Paging: &usecase.SearchFruitUseCaseOutputPaging{}}, nil)
+
+		h := handler.MakeSearchFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = httptest.NewRequest("GET", "/fruits/search?name=uva&status=comestible,reserved&status=sold&owner=ruan&min_price=2.5&max_quantity=10&created_after=2022-12-01T00:00:00Z&sort=-price,name&offset=1&limit=10", nil)
+
+		h(ctx)
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+		u.AssertExpectations(t)
+	})
+
+	t.Run("With fuzzy match", func(t *testing.T) {
+		u := &SearchFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.SearchFruitUseCaseInputDTO{Name: "bananna", Match: "fuzzy", Offset: 1, Limit: 10}).Return(&usecase.SearchFruitUseCaseOutputDTO{
+			Paging:  &usecase.SearchFruitUseCaseOutputPaging{Total: 1, Limit: 10, Offset: 1},
+			Results: []*usecase.SearchFruitUseCaseOutputResult{{ID: "banana-id", Name: "banana", Score: 0.686}},
+		}, nil)
+
+		h := handler.MakeSearchFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = httptest.NewRequest("GET", "/fruits/search?name=bananna&match=fuzzy&offset=1&limit=10", nil)
+
+		var response handler.SearchFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.Results[0].Score, 0.686)
+		u.AssertExpectations(t)
+	})
+
+	t.Run("With cursor", func(t *testing.T) {
+		cursor := "token"
+
+		u := &SearchFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.SearchFruitUseCaseInputDTO{Cursor: &cursor, Limit: 10}).Return(&usecase.SearchFruitUseCaseOutputDTO{
+			Paging:  &usecase.SearchFruitUseCaseOutputPaging{Total: 30, Limit: 10},
+			Cursors: &usecase.SearchFruitUseCaseOutputCursors{Next: "next-token", Prev: "prev-token"},
+		}, nil)
+
+		h := handler.MakeSearchFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Request = httptest.NewRequest("GET", "/fruits/search?cursor=token&limit=10", nil)
+
+		var response handler.SearchFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.Cursors, &handler.SearchFruitResponseCursors{Next: "next-token", Prev: "prev-token"})
+		u.AssertExpectations(t)
+	})
+
+	for name, query := range map[string]string{"price": "min_price=cheap", "quantity": "max_quantity=1.5", "date": "created_before=yesterday"} {
+		t.Run("With malformed "+name, func(t *testing.T) {
+			u := &SearchFruitUseCaseMock{}
+			h := handler.MakeSearchFruitHandler(u)
+
+			rr := httptest.NewRecorder()
+			ctx, _ := gin.CreateTestContext(rr)
+			ctx.Request = httptest.NewRequest("GET", "/fruits/search?offset=1&limit=10&"+query, nil)
+
+			var response error2.HttpError
+			h(ctx)
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+			assert.Nil(t, err)
+			assert.Equal(t, rr.Code, http.StatusBadRequest)
+			assert.Equal(t, response.Detail, "invalid request query")
+			u.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+		})
+	}
 }
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"net/http"
+	"time"
+)
+
+type TransitionFruitRequestDTO struct {
+	Status string `json:"status"`
+}
+
+type TransitionFruitResponseDTO struct {
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"date_created"`
+	UpdatedAt time.Time `json:"date_last_updated"`
+	Name      string    `json:"name"`
+	Quantity  int       `json:"quantity"`
+	Price     float64   `json:"price"`
+	Owner     string    `json:"owner"`
+	Status    string    `json:"status"`
+}
+
+// MakeTransitionFruitHandler generate handler function to http fruit status transition request
+// @Summary      Transition fruit status
+// @Description  Move fruit to another status (comestible, reserved, sold, podrido, discarded) following the lifecycle rules
+// @Tags         fruits
+// @Accept       json
+// @Produce      json
+// @Param		 id path string true "Fruit id"
+// @Param		 body body TransitionFruitRequestDTO true "Transition request body DTO"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
+// @Success		 200 {object} TransitionFruitResponseDTO
+// @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
+// @Failure		 500 {object} error.HttpError
+// @Router       /fruits/{id}/transitions [post]
+func MakeTransitionFruitHandler(u protocol.UseCase[*usecase.TransitionFruitUseCaseInputDTO, *usecase.TransitionFruitUseCaseOutputDTO]) gin.HandlerFunc {
+	return func(c *gin.Context) {
+
+		id := c.Param("id")
+		if id == "" {
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
+			return
+		}
+
+		body := &TransitionFruitRequestDTO{}
+		err := c.ShouldBindJSON(body)
+		if err != nil {
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+			return
+		}
+
+		input := &usecase.TransitionFruitUseCaseInputDTO{
+			ID:     id,
+			Status: body.Status,
+		}
+
+		output, err := u.Execute(c.Request.Context(), input)
+
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		response := &TransitionFruitResponseDTO{
+			ID:        output.ID,
+			CreatedAt: output.CreatedAt,
+			UpdatedAt: output.UpdatedAt,
+			Name:      output.Name,
+			Status:    output.Status,
+			Owner:     output.Owner,
+			Price:     output.Price,
+			Quantity:  output.Quantity,
+		}
+		c.JSON(http.StatusOK, response)
+	}
+}
@@ -0,0 +1,131 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type TransitionFruitUseCaseMock struct {
+	mock.Mock
+}
+
+func (c *TransitionFruitUseCaseMock) Execute(ctx context.Context, i *usecase.TransitionFruitUseCaseInputDTO) (*usecase.TransitionFruitUseCaseOutputDTO, error) {
+	args := c.Called(ctx, i)
+	return args.Get(0).(*usecase.TransitionFruitUseCaseOutputDTO), args.Error(1)
+}
+
+func TestTransitionFruitHandler(t *testing.T) {
+	t.Run("With invalid param", func(t *testing.T) {
+		u := &TransitionFruitUseCaseMock{}
+		h := handler.MakeTransitionFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: ""},
+		}
+
+		r := httptest.NewRequest("POST", "/fruits/{id}/transitions", nil)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request param")
+	})
+
+	t.Run("With empty body", func(t *testing.T) {
+		u := &TransitionFruitUseCaseMock{}
+		h := handler.MakeTransitionFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		r := httptest.NewRequest("POST", "/fruits/{id}/transitions", nil)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusBadRequest)
+		assert.Equal(t, response.Detail, "invalid request body")
+	})
+
+	t.Run("With illegal transition", func(t *testing.T) {
+		u := &TransitionFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.TransitionFruitUseCaseOutputDTO{}, domainerror.NewConflictError("fruit cannot go from sold to comestible"))
+		h := handler.MakeTransitionFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		body := `{"status": "comestible"}`
+		r := httptest.NewRequest("POST", "/fruits/{id}/transitions", strings.NewReader(body))
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusConflict)
+		assert.Equal(t, response.Detail, "fruit cannot go from sold to comestible")
+	})
+
+	t.Run("When usecase success", func(t *testing.T) {
+		u := &TransitionFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.TransitionFruitUseCaseInputDTO{ID: "some-uuid", Status: "sold"}).Return(&usecase.TransitionFruitUseCaseOutputDTO{
+			ID:        "some-uuid",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+			Name:      "uva",
+			Quantity:  1,
+			Price:     10.0,
+			Status:    "sold",
+			Owner:     "owner",
+		}, nil)
+		h := handler.MakeTransitionFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		body := `{"status": "sold"}`
+		r := httptest.NewRequest("POST", "/fruits/{id}/transitions", strings.NewReader(body))
+		ctx.Request = r
+
+		var response handler.TransitionFruitResponseDTO
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, response.ID, "some-uuid")
+		assert.Equal(t, response.Status, "sold")
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"net/http"
 	"time"
 )
@@ -32,8 +33,19 @@ type UpdateFruitResponseDTO struct {
 // @Produce      json
 // @Param		 id path string true "Fruit id"
 // @Param		 body body UpdateFruitRequestDTO true "Update request body DTO"
+// @Param		 If-Match header string false "Only update when the fruit still has this ETag"
+// @Param		 X-Tenant-ID header string false "Tenant the fruits belong to, defaults to the credentials tenant or default"
+// @Security	 ApiKeyAuth
+// @Security	 BearerAuth
 // @Success		 200 {object} UpdateFruitResponseDTO
+// @Header		 200 {string} ETag "Fruit version"
 // @Failure		 400 {object} error.HttpError
+// @Failure		 401 {object} error.HttpError
+// @Failure		 403 {object} error.HttpError
+// @Failure		 404 {object} error.HttpError
+// @Failure		 409 {object} error.HttpError
+// @Failure		 412 {object} error.HttpError
+// @Failure		 422 {object} error.HttpError
 // @Failure		 500 {object} error.HttpError
 // @Router       /fruits/{id} [put]
 func MakeUpdateFruitHandler(u protocol.UseCase[*usecase.UpdateFruitUseCaseInputDTO, *usecase.UpdateFruitUseCaseOutputDTO]) gin.HandlerFunc {
@@ -41,37 +53,35 @@ func MakeUpdateFruitHandler(u protocol.UseCase[*usecase.UpdateFruitUseCaseInputD
 
 		id := c.Param("id")
 		if id == "" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "invalid request param",
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewBadRequestError("invalid request param"))
 			return
 
 		}
 
 		body := &UpdateFruitRequestDTO{}
-		err := c.BindJSON(body)
+		err := c.ShouldBindJSON(body)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": "invalid request body",
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewBadRequestError("invalid request body"))
+			return
+		}
+
+		expectedVersion, err := parseIfMatch(c.GetHeader("If-Match"))
+		if err != nil {
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
 		input := &usecase.UpdateFruitUseCaseInputDTO{
-			ID:       id,
-			Price:    body.Price,
-			Quantity: body.Quantity,
+			ID:              id,
+			Price:           body.Price,
+			Quantity:        body.Quantity,
+			ExpectedVersion: expectedVersion,
 		}
 
 		output, err := u.Execute(c.Request.Context(), input)
 
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"message": err.Error(),
-				"status":  http.StatusBadRequest,
-			})
+			error2.Respond(c, error2.NewHttpError(err))
 			return
 		}
 
@@ -85,6 +95,7 @@ func MakeUpdateFruitHandler(u protocol.UseCase[*usecase.UpdateFruitUseCaseInputD
 			Price:     output.Price,
 			Quantity:  output.Quantity,
 		}
+		c.Header("ETag", etag(output.Version))
 		c.JSON(http.StatusOK, response)
 	}
 }
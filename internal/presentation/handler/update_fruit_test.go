@@ -3,9 +3,9 @@ package handler_test
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/entity"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
 	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
 	"github.com/ruancaetano/go-gin-fruits/internal/presentation/handler"
@@ -51,7 +51,7 @@ func TestUpdateFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "invalid request param")
+		assert.Equal(t, response.Detail, "invalid request param")
 	})
 
 	t.Run("With empty body", func(t *testing.T) {
@@ -76,12 +76,12 @@ func TestUpdateFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "invalid request body")
+		assert.Equal(t, response.Detail, "invalid request body")
 	})
 
 	t.Run("When usecase fails", func(t *testing.T) {
 		u := &UpdateFruitUseCaseMock{}
-		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.UpdateFruitUseCaseOutputDTO{}, errors.New("quantity must be greater than zero"))
+		u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.UpdateFruitUseCaseOutputDTO{}, domainerror.NewValidationError("quantity must be greater than zero"))
 		h := handler.MakeUpdateFruitHandler(u)
 
 		rr := httptest.NewRecorder()
@@ -102,8 +102,60 @@ func TestUpdateFruitHandler(t *testing.T) {
 		}
 
 		assert.Nil(t, err)
-		assert.Equal(t, rr.Code, http.StatusBadRequest)
-		assert.Equal(t, response.Message, "quantity must be greater than zero")
+		assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+		assert.Equal(t, response.Detail, "quantity must be greater than zero")
+	})
+
+	t.Run("With invalid if-match header", func(t *testing.T) {
+		u := &UpdateFruitUseCaseMock{}
+		h := handler.MakeUpdateFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		body := `{"quantity": 100, "price": 100.0}`
+		r := httptest.NewRequest("PUT", "/fruits/{id}", strings.NewReader(body))
+		r.Header.Set("If-Match", `W/"1"`)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusPreconditionFailed)
+		assert.Equal(t, response.Detail, "if-match must be a single fruit etag")
+		u.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+	})
+
+	t.Run("With stale if-match header", func(t *testing.T) {
+		expectedVersion := 1
+
+		u := &UpdateFruitUseCaseMock{}
+		u.On("Execute", mock.Anything, &usecase.UpdateFruitUseCaseInputDTO{ID: "some-uuid", Quantity: 100, Price: 100.0, ExpectedVersion: &expectedVersion}).Return(&usecase.UpdateFruitUseCaseOutputDTO{}, domainerror.NewPreconditionFailedError("fruit is at version 2, not 1"))
+		h := handler.MakeUpdateFruitHandler(u)
+
+		rr := httptest.NewRecorder()
+		ctx, _ := gin.CreateTestContext(rr)
+		ctx.Params = []gin.Param{
+			{Key: "id", Value: "some-uuid"},
+		}
+
+		body := `{"quantity": 100, "price": 100.0}`
+		r := httptest.NewRequest("PUT", "/fruits/{id}", strings.NewReader(body))
+		r.Header.Set("If-Match", `"1"`)
+		ctx.Request = r
+
+		var response error2.HttpError
+		h(ctx)
+		err := json.Unmarshal([]byte(rr.Body.String()), &response)
+
+		assert.Nil(t, err)
+		assert.Equal(t, rr.Code, http.StatusPreconditionFailed)
+		assert.Equal(t, response.Detail, "fruit is at version 2, not 1")
 	})
 
 	t.Run("When usecase success", func(t *testing.T) {
@@ -117,8 +169,9 @@ func TestUpdateFruitHandler(t *testing.T) {
 			Name:      fruitMock.Name,
 			Quantity:  100,
 			Price:     100.0,
-			Status:    fruitMock.Status,
+			Status:    string(fruitMock.Status),
 			Owner:     fruitMock.Owner,
+			Version:   2,
 		}, nil)
 		h := handler.MakeUpdateFruitHandler(u)
 
@@ -141,12 +194,13 @@ func TestUpdateFruitHandler(t *testing.T) {
 
 		assert.Nil(t, err)
 		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Header().Get("ETag"), `"2"`)
 		assert.Equal(t, response.ID, fruitMock.ID)
 		assert.Equal(t, response.Name, fruitMock.Name)
 		assert.Equal(t, response.Owner, fruitMock.Owner)
 		assert.Equal(t, response.Price, 100.0)
 		assert.Equal(t, response.Quantity, 100)
-		assert.Equal(t, response.Status, fruitMock.Status)
+		assert.Equal(t, response.Status, string(fruitMock.Status))
 		assert.True(t, response.CreatedAt.Equal(fruitMock.CreatedAt))
 		assert.False(t, response.UpdatedAt.Equal(fruitMock.UpdatedAt))
 	})
@@ -0,0 +1,59 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/protocol"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+)
+
+// RetentionJob periodically purge fruits that have been deleted for longer than the retention period
+type RetentionJob struct {
+	useCase  protocol.UseCase[*usecase.PurgeExpiredFruitsUseCaseInputDTO, *usecase.PurgeExpiredFruitsUseCaseOutputDTO]
+	period   time.Duration
+	interval time.Duration
+}
+
+func NewRetentionJob(u protocol.UseCase[*usecase.PurgeExpiredFruitsUseCaseInputDTO, *usecase.PurgeExpiredFruitsUseCaseOutputDTO], period time.Duration, interval time.Duration) *RetentionJob {
+	return &RetentionJob{
+		useCase:  u,
+		period:   period,
+		interval: interval,
+	}
+}
+
+// Run purge expired fruits right away and then on every interval, until ctx is done
+func (rj *RetentionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(rj.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := rj.RunOnce(ctx); err != nil {
+			logger.FromContext(ctx).WithError(err).Error("fail to purge expired fruits")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (rj *RetentionJob) RunOnce(ctx context.Context) (int, error) {
+	output, err := rj.useCase.Execute(ctx, &usecase.PurgeExpiredFruitsUseCaseInputDTO{
+		DeletedBefore: time.Now().Add(-rj.period),
+	})
+
+	if output == nil {
+		return 0, err
+	}
+
+	if len(output.Purged) > 0 {
+		logger.FromContext(ctx).WithField("purged", len(output.Purged)).Infof("purged fruits deleted for more than %s", rj.period)
+	}
+
+	return len(output.Purged), err
+}
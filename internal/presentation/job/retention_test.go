@@ -0,0 +1,65 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/usecase"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/job"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+type PurgeExpiredFruitsUseCaseMock struct {
+	mock.Mock
+}
+
+func (c *PurgeExpiredFruitsUseCaseMock) Execute(ctx context.Context, i *usecase.PurgeExpiredFruitsUseCaseInputDTO) (*usecase.PurgeExpiredFruitsUseCaseOutputDTO, error) {
+	args := c.Called(ctx, i)
+	output, _ := args.Get(0).(*usecase.PurgeExpiredFruitsUseCaseOutputDTO)
+	return output, args.Error(1)
+}
+
+func TestRetentionJob_RunOnce(t *testing.T) {
+	t.Run("Purge fruits deleted before the retention period", func(t *testing.T) {
+		period := 24 * time.Hour
+		expectedCutoff := time.Now().Add(-period)
+
+		u := &PurgeExpiredFruitsUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.MatchedBy(func(i *usecase.PurgeExpiredFruitsUseCaseInputDTO) bool {
+			return i.DeletedBefore.Sub(expectedCutoff) < time.Second && !i.DeletedBefore.Before(expectedCutoff)
+		})).Return(&usecase.PurgeExpiredFruitsUseCaseOutputDTO{Purged: []string{"a", "b"}}, nil)
+
+		j := job.NewRetentionJob(u, period, time.Hour)
+
+		purged, err := j.RunOnce(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, purged, 2)
+	})
+
+	t.Run("With usecase fail", func(t *testing.T) {
+		u := &PurgeExpiredFruitsUseCaseMock{}
+		u.On("Execute", mock.Anything, mock.Anything).Return(nil, errors.New("search failed"))
+
+		j := job.NewRetentionJob(u, time.Hour, time.Hour)
+
+		purged, err := j.RunOnce(context.Background())
+		assert.EqualError(t, err, "search failed")
+		assert.Equal(t, purged, 0)
+	})
+}
+
+func TestRetentionJob_Run(t *testing.T) {
+	u := &PurgeExpiredFruitsUseCaseMock{}
+	u.On("Execute", mock.Anything, mock.Anything).Return(&usecase.PurgeExpiredFruitsUseCaseOutputDTO{}, nil)
+
+	j := job.NewRetentionJob(u, time.Hour, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 55*time.Millisecond)
+	defer cancel()
+
+	j.Run(ctx)
+
+	assert.GreaterOrEqual(t, len(u.Calls), 2)
+}
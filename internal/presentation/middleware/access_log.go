@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/sirupsen/logrus"
+)
+
+// AccessLog log one line per request once it has been served, through the request scoped logger
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		status := c.Writer.Status()
+		entry := logger.FromContext(c.Request.Context()).WithFields(logrus.Fields{
+			"method":    c.Request.Method,
+			"path":      c.Request.URL.Path,
+			"route":     c.FullPath(),
+			"status":    status,
+			"latency":   time.Since(start).String(),
+			"client_ip": c.ClientIP(),
+			"size":      c.Writer.Size(),
+		})
+
+		switch {
+		case status >= http.StatusInternalServerError:
+			entry.Error("request served")
+		case status >= http.StatusBadRequest:
+			entry.Warn("request served")
+		default:
+			entry.Info("request served")
+		}
+	}
+}
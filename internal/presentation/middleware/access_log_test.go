@@ -0,0 +1,54 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAccessLog(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	r := gin.New()
+	r.Use(middleware.RequestID(log), middleware.AccessLog())
+	r.GET("/fruits/:id", func(c *gin.Context) {
+		c.Status(http.StatusNotFound)
+	})
+	r.GET("/ping", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("Log successful requests as info", func(t *testing.T) {
+		hook.Reset()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/ping", nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-123")
+		r.ServeHTTP(rr, req)
+
+		entry := hook.LastEntry()
+		assert.Equal(t, entry.Level, logrus.InfoLevel)
+		assert.Equal(t, entry.Message, "request served")
+		assert.Equal(t, entry.Data["request_id"], "req-123")
+		assert.Equal(t, entry.Data["method"], "GET")
+		assert.Equal(t, entry.Data["status"], http.StatusOK)
+	})
+
+	t.Run("Log client errors as warning", func(t *testing.T) {
+		hook.Reset()
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/fruits/some-uuid", nil))
+
+		entry := hook.LastEntry()
+		assert.Equal(t, entry.Level, logrus.WarnLevel)
+		assert.Equal(t, entry.Data["path"], "/fruits/some-uuid")
+		assert.Equal(t, entry.Data["route"], "/fruits/:id")
+		assert.Equal(t, entry.Data["status"], http.StatusNotFound)
+	})
+}
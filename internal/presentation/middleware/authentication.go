@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/sirupsen/logrus"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Authenticate refuse requests without valid credentials with a 401. The verified principal is carried by
+// the request context, and its subject added to the request scoped logger and span.
+func Authenticate(a infraauth.Authenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := a.Authenticate(c.Request)
+		if err == nil && principal == nil {
+			err = domainerror.NewUnauthenticatedError("missing credentials")
+		}
+
+		if err != nil {
+			c.Abort()
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		ctx := c.Request.Context()
+		ctx = auth.WithPrincipal(ctx, principal)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).WithFields(logrus.Fields{"subject": principal.Subject, "auth_method": principal.Method}))
+		trace.SpanFromContext(ctx).SetAttributes(semconv.EnduserIDKey.String(principal.Subject))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
@@ -0,0 +1,66 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAuthenticate(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	authenticator, err := infraauth.NewAPIKeyAuthenticator([]infraauth.APIKey{{Key: "s3cr3t", Subject: "ruan"}})
+	assert.Nil(t, err)
+
+	r := gin.New()
+	r.Use(middleware.RequestID(log), middleware.Authenticate(authenticator))
+	r.POST("/fruits", func(c *gin.Context) {
+		principal, _ := auth.PrincipalFrom(c.Request.Context())
+		logger.FromContext(c.Request.Context()).Info("creating fruit")
+		c.String(http.StatusCreated, principal.Subject)
+	})
+
+	t.Run("With valid api key", func(t *testing.T) {
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/fruits", nil)
+		req.Header.Set(infraauth.APIKeyHeader, "s3cr3t")
+		req.Header.Set("x-owner", "someone-else")
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, rr.Code, http.StatusCreated)
+		assert.Equal(t, rr.Body.String(), "ruan")
+		assert.Equal(t, hook.LastEntry().Data["subject"], "ruan")
+		assert.Equal(t, hook.LastEntry().Data["auth_method"], auth.MethodAPIKey)
+	})
+
+	for name, key := range map[string]string{"invalid api key": "wrong", "missing credentials": ""} {
+		t.Run("With "+name, func(t *testing.T) {
+			hook.Reset()
+
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("POST", "/fruits", nil)
+			if key != "" {
+				req.Header.Set(infraauth.APIKeyHeader, key)
+			}
+			r.ServeHTTP(rr, req)
+
+			var response error2.HttpError
+			err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+			assert.Nil(t, err)
+			assert.Equal(t, rr.Code, http.StatusUnauthorized)
+			assert.Equal(t, response.Type, error2.UnauthenticatedProblem)
+			assert.Equal(t, response.Detail, name)
+			assert.Nil(t, hook.LastEntry())
+		})
+	}
+}
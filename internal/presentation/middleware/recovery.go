@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"io"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+)
+
+// Recovery turn panics into a 500 problem response, logging the panic and its stack with the request id
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(io.Discard, func(c *gin.Context, recovered interface{}) {
+		logger.FromContext(c.Request.Context()).
+			WithField("stack", string(debug.Stack())).
+			Errorf("panic recovered: %v", recovered)
+
+		error2.Respond(c, error2.NewInternalServerError())
+		c.Abort()
+	})
+}
@@ -0,0 +1,45 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRecovery(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	r := gin.New()
+	r.Use(middleware.RequestID(log), middleware.AccessLog(), middleware.Recovery())
+	r.GET("/fruits/:id", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/fruits/some-uuid", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+	r.ServeHTTP(rr, req)
+
+	var response error2.HttpError
+	err := json.Unmarshal(rr.Body.Bytes(), &response)
+
+	assert.Nil(t, err)
+	assert.Equal(t, rr.Code, http.StatusInternalServerError)
+	assert.Equal(t, rr.Header().Get("Content-Type"), error2.ProblemContentType)
+	assert.Equal(t, response.Detail, "internal server error")
+
+	entries := hook.AllEntries()
+	assert.Len(t, entries, 2)
+	assert.Equal(t, entries[0].Message, "panic recovered: boom")
+	assert.Equal(t, entries[0].Data["request_id"], "req-123")
+	assert.Contains(t, entries[0].Data["stack"], "runtime/debug.Stack")
+	assert.Equal(t, entries[1].Level, logrus.ErrorLevel)
+	assert.Equal(t, entries[1].Data["status"], http.StatusInternalServerError)
+}
@@ -0,0 +1,59 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	r := gin.New()
+	r.Use(middleware.RequestID(log))
+	r.GET("/fruits/:id", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context()).Info("getting fruit")
+		c.Status(http.StatusOK)
+	})
+
+	t.Run("With incoming request id", func(t *testing.T) {
+		hook.Reset()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/fruits/some-uuid", nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-123")
+		r.ServeHTTP(rr, req)
+
+		assert.Equal(t, rr.Header().Get(middleware.RequestIDHeader), "req-123")
+		assert.Equal(t, hook.LastEntry().Data["request_id"], "req-123")
+	})
+
+	t.Run("Without request id", func(t *testing.T) {
+		hook.Reset()
+
+		rr := httptest.NewRecorder()
+		r.ServeHTTP(rr, httptest.NewRequest("GET", "/fruits/some-uuid", nil))
+
+		id := rr.Header().Get(middleware.RequestIDHeader)
+		assert.Len(t, id, 36)
+		assert.Equal(t, hook.LastEntry().Data["request_id"], id)
+	})
+
+	t.Run("With malformed request id", func(t *testing.T) {
+		for _, id := range []string{"forged\nlevel=error", strings.Repeat("a", 129)} {
+			rr := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", "/fruits/some-uuid", nil)
+			req.Header.Set(middleware.RequestIDHeader, id)
+			r.ServeHTTP(rr, req)
+
+			assert.NotEqual(t, rr.Header().Get(middleware.RequestIDHeader), id)
+			assert.Len(t, rr.Header().Get(middleware.RequestIDHeader), 36)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	domainerror "github.com/ruancaetano/go-gin-fruits/internal/domain/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant scope the request to a tenant: the one the credentials are bound to, else the X-Tenant-ID header,
+// else the default tenant. Asking for a tenant other than the credentials one is refused with a 403, and so is
+// asking for any tenant but the default one with unbound credentials, unless they belong to an admin.
+// Must run after Authenticate.
+func Tenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := resolveTenant(c)
+		if err != nil {
+			c.Abort()
+			error2.Respond(c, error2.NewHttpError(err))
+			return
+		}
+
+		ctx := c.Request.Context()
+		ctx = tenant.WithTenant(ctx, id)
+		ctx = logger.WithContext(ctx, logger.FromContext(ctx).WithField("tenant", id))
+		trace.SpanFromContext(ctx).SetAttributes(attribute.String("tenant", id))
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
+
+func resolveTenant(c *gin.Context) (string, error) {
+	requested := c.GetHeader(TenantHeader)
+
+	principal, ok := auth.PrincipalFrom(c.Request.Context())
+	if ok && principal.Tenant != "" {
+		if requested != "" && requested != principal.Tenant {
+			return "", domainerror.NewForbiddenError("credentials are bound to another tenant")
+		}
+
+		return principal.Tenant, nil
+	}
+
+	if requested == "" {
+		return tenant.Default, nil
+	}
+
+	if err := tenant.Validate(requested); err != nil {
+		return "", err
+	}
+
+	// callers are trusted with their claims when authentication is disabled for local development
+	chooses := ok && (principal.HasRole(auth.RoleAdmin) || principal.Method == auth.MethodDev)
+	if requested != tenant.Default && !chooses {
+		return "", domainerror.NewForbiddenError("only admins can choose a tenant, credentials must be bound to one otherwise")
+	}
+
+	return requested, nil
+}
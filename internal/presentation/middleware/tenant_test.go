@@ -0,0 +1,112 @@
+package middleware_test
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/domain/tenant"
+	infraauth "github.com/ruancaetano/go-gin-fruits/internal/infra/auth"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	error2 "github.com/ruancaetano/go-gin-fruits/internal/presentation/error"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTenant(t *testing.T) {
+	log, hook := test.NewNullLogger()
+
+	authenticator, err := infraauth.NewAPIKeyAuthenticator([]infraauth.APIKey{
+		{Key: "any-tenant", Subject: "ruan", Roles: []string{auth.RoleAdmin}},
+		{Key: "unbound", Subject: "bob"},
+		{Key: "acme-only", Subject: "clerk", Tenant: "acme"},
+	})
+	assert.Nil(t, err)
+
+	r := gin.New()
+	r.Use(middleware.RequestID(log), middleware.Authenticate(authenticator), middleware.Tenant())
+	r.GET("/fruits", func(c *gin.Context) {
+		logger.FromContext(c.Request.Context()).Info("searching fruits")
+		c.String(http.StatusOK, tenant.FromContext(c.Request.Context()))
+	})
+
+	serve := func(key string, requested string) *httptest.ResponseRecorder {
+		hook.Reset()
+
+		rr := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/fruits", nil)
+		req.Header.Set(infraauth.APIKeyHeader, key)
+		if requested != "" {
+			req.Header.Set(middleware.TenantHeader, requested)
+		}
+		r.ServeHTTP(rr, req)
+
+		return rr
+	}
+
+	t.Run("Default tenant when none is asked for", func(t *testing.T) {
+		rr := serve("any-tenant", "")
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), tenant.Default)
+	})
+
+	t.Run("Tenant from header", func(t *testing.T) {
+		rr := serve("any-tenant", "globex")
+
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), "globex")
+		assert.Equal(t, hook.LastEntry().Data["tenant"], "globex")
+	})
+
+	t.Run("With unbound credentials other than an admin", func(t *testing.T) {
+		rr := serve("unbound", "")
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), tenant.Default)
+
+		rr = serve("unbound", tenant.Default)
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), tenant.Default)
+
+		rr = serve("unbound", "globex")
+
+		var response error2.HttpError
+		assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, rr.Code, http.StatusForbidden)
+		assert.Equal(t, response.Detail, "only admins can choose a tenant, credentials must be bound to one otherwise")
+		assert.Nil(t, hook.LastEntry())
+	})
+
+	t.Run("Tenant bound to the credentials", func(t *testing.T) {
+		rr := serve("acme-only", "")
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), "acme")
+
+		rr = serve("acme-only", "acme")
+		assert.Equal(t, rr.Code, http.StatusOK)
+		assert.Equal(t, rr.Body.String(), "acme")
+	})
+
+	t.Run("With header naming another tenant than the credentials", func(t *testing.T) {
+		rr := serve("acme-only", "globex")
+
+		var response error2.HttpError
+		assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, rr.Code, http.StatusForbidden)
+		assert.Equal(t, response.Type, error2.ForbiddenProblem)
+		assert.Nil(t, hook.LastEntry())
+	})
+
+	t.Run("With malformed tenant", func(t *testing.T) {
+		rr := serve("any-tenant", "acme/../globex")
+
+		var response error2.HttpError
+		assert.Nil(t, json.Unmarshal(rr.Body.Bytes(), &response))
+		assert.Equal(t, rr.Code, http.StatusUnprocessableEntity)
+		assert.Equal(t, response.Type, error2.ValidationProblem)
+		assert.Nil(t, hook.LastEntry())
+	})
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/logger"
+	"net"
+	"time"
+)
+
+// connKey the context key of the connection a request was read from
+type connKey struct{}
+
+// WithConn carry the connection in the context of the requests read from it, to be set as the server ConnContext
+// so routes can move its write deadline
+func WithConn(ctx context.Context, c net.Conn) context.Context {
+	return context.WithValue(ctx, connKey{}, c)
+}
+
+// WriteTimeout give the route d from now on to write its response in place of the server write timeout, no limit
+// when d is zero. The server sets its own deadline again before reading the next request of the connection.
+// Requests whose connection wasn't carried by WithConn keep the server write timeout.
+func WriteTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if conn, ok := c.Request.Context().Value(connKey{}).(net.Conn); ok {
+			var deadline time.Time
+			if d > 0 {
+				deadline = time.Now().Add(d)
+			}
+
+			if err := conn.SetWriteDeadline(deadline); err != nil {
+				logger.FromContext(c.Request.Context()).WithError(err).Warn("fail to move the write deadline")
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware_test
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/ruancaetano/go-gin-fruits/internal/presentation/middleware"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWriteTimeout(t *testing.T) {
+	slow := func(c *gin.Context) {
+		time.Sleep(200 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	}
+
+	r := gin.New()
+	r.GET("/slow", slow)
+	r.GET("/slow/limited", middleware.WriteTimeout(time.Second), slow)
+	r.GET("/slow/unlimited", middleware.WriteTimeout(0), slow)
+
+	server := httptest.NewUnstartedServer(r)
+	server.Config.WriteTimeout = 50 * time.Millisecond
+	server.Config.ConnContext = middleware.WithConn
+	server.Start()
+	defer server.Close()
+
+	get := func(path string) (string, error) {
+		res, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			return "", err
+		}
+		defer res.Body.Close()
+
+		body, err := io.ReadAll(res.Body)
+		return string(body), err
+	}
+
+	t.Run("With the server write timeout", func(t *testing.T) {
+		_, err := get("/slow")
+		assert.NotNil(t, err)
+	})
+
+	t.Run("With the route write timeout", func(t *testing.T) {
+		for _, path := range []string{"/slow/limited", "/slow/unlimited"} {
+			body, err := get(path)
+			assert.Nil(t, err)
+			assert.Equal(t, body, "done")
+		}
+	})
+
+	t.Run("With the server write timeout back on the next request", func(t *testing.T) {
+		_, err := get("/slow/limited")
+		assert.Nil(t, err)
+
+		_, err = get("/slow")
+		assert.NotNil(t, err)
+	})
+}